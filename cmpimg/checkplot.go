@@ -7,23 +7,43 @@ package cmpimg
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"flag"
 	"image"
 	"image/png"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 )
 
 var GenerateTestData = flag.Bool("regen", false, "Uses the current state to regenerate the test data.")
 
+// UpdateGolden indicates whether the golden files that differ from the
+// generated files, or are missing, are overwritten by the generated files.
+// It is true when the CMPIMG_UPDATE environment variable holds a true
+// boolean value.
+var UpdateGolden = envBool("CMPIMG_UPDATE")
+
+func envBool(name string) bool {
+	v, _ := strconv.ParseBool(os.Getenv(name))
+	return v
+}
+
 func goldenPath(path string) string {
 	ext := filepath.Ext(path)
 	noext := strings.TrimSuffix(path, ext)
 	return noext + "_golden" + ext
 }
 
+func diffPath(path string) string {
+	ext := filepath.Ext(path)
+	noext := strings.TrimSuffix(path, ext)
+	return noext + "_diff.png"
+}
+
 // CheckPlot checks a generated plot against a previously created reference.
 // If GenerateTestData = true, it regenerates the reference.
 // For image.Image formats, a base64 encoded png representation is output to
@@ -36,10 +56,32 @@ func CheckPlot(ExampleFunc func(), t *testing.T, filenames ...string) {
 // The normalized delta parameter describes how tight the matching should be
 // performed, where delta=0 expresses a perfect match, and delta=1 a very loose match.
 // If GenerateTestData = true, it regenerates the reference.
-// For image.Image formats, a base64 encoded png representation is output to
+// For image.Image formats, the YIQ distance between the images is reported,
+// a difference image is written next to the generated file with a "_diff.png"
+// suffix, ignored by git, and a base64 encoded png representation is output to
 // the testing log when a difference is identified.
 func CheckPlotApprox(ExampleFunc func(), t *testing.T, delta float64, filenames ...string) {
 	t.Helper()
+	switch {
+	case delta < 0:
+		delta = 0
+	case delta > 1:
+		delta = 1
+	}
+	CheckPlotMetric(ExampleFunc, t, YIQ, delta, filenames...)
+}
+
+// CheckPlotMetric checks a generated plot against a previously created reference,
+// using the metric m and the threshold on the distance between the images.
+// If GenerateTestData = true, it regenerates the reference.
+// If UpdateGolden = true, it overwrites the references that differ from the
+// generated plots or are missing, and logs the list of updated references.
+// For image.Image formats, the distance between the images is reported, a
+// difference image is written next to the generated file with a "_diff.png"
+// suffix, ignored by git, and a base64 encoded png representation is output to
+// the testing log when a difference is identified.
+func CheckPlotMetric(ExampleFunc func(), t *testing.T, m Metric, threshold float64, filenames ...string) {
+	t.Helper()
 
 	paths := make([]string, len(filenames))
 	for i, fn := range filenames {
@@ -64,6 +106,15 @@ func CheckPlotApprox(ExampleFunc func(), t *testing.T, delta float64, filenames
 
 	// Read the images we've just generated and check them against the
 	// Golden Images.
+	var updated []string
+	if UpdateGolden {
+		defer func() {
+			t.Logf("cmpimg: updated %d of %d golden files", len(updated), len(paths))
+			for _, golden := range updated {
+				t.Logf("cmpimg: updated %s", golden)
+			}
+		}()
+	}
 	for _, path := range paths {
 		got, err := os.ReadFile(path)
 		if err != nil {
@@ -72,21 +123,38 @@ func CheckPlotApprox(ExampleFunc func(), t *testing.T, delta float64, filenames
 		}
 		golden := goldenPath(path)
 		want, err := os.ReadFile(golden)
-		if err != nil {
+		switch {
+		case err == nil:
+		case UpdateGolden && errors.Is(err, fs.ErrNotExist):
+			// The missing golden file is created below.
+			want = nil
+		default:
 			t.Errorf("Failed to read golden file %s: %v", golden, err)
 			continue
 		}
 		typ := filepath.Ext(path)[1:] // remove the dot in e.g. ".pdf"
-		ok, err := EqualApprox(typ, got, want, delta)
-		if err != nil {
-			t.Errorf("failed to compare image for %s: %v", path, err)
+		ok, dist := false, 1.0
+		if want != nil {
+			ok, dist, err = EqualMetric(typ, got, want, m, threshold)
+			if err != nil && !UpdateGolden {
+				t.Errorf("failed to compare image for %s: %v", path, err)
+				continue
+			}
+		}
+		if !ok && UpdateGolden {
+			_ = os.Remove(golden)
+			if err := os.Rename(path, golden); err != nil {
+				t.Errorf("failed to update golden file %s: %v", golden, err)
+				continue
+			}
+			updated = append(updated, golden)
 			continue
 		}
 		if !ok {
-			t.Errorf("image mismatch for %s\n", path)
-
 			switch typ {
 			case "jpeg", "jpg", "png", "tiff", "tif":
+				t.Errorf("image mismatch for %s: %v distance %g above threshold %g\n", path, m, dist, threshold)
+
 				v1, _, err := image.Decode(bytes.NewReader(got))
 				if err != nil {
 					t.Errorf("failed to decode %s: %v", path, err)
@@ -108,6 +176,13 @@ func CheckPlotApprox(ExampleFunc func(), t *testing.T, delta float64, filenames
 					t.Errorf("failed to encode difference png: %v", err)
 					continue
 				}
+				diff := diffPath(path)
+				err = os.WriteFile(diff, buf.Bytes(), 0o644)
+				if err != nil {
+					t.Errorf("failed to write difference png: %v", err)
+				} else {
+					t.Logf("image diff written to %s", diff)
+				}
 				t.Log("image diff:")
 				t.Log("IMAGE:" + base64.StdEncoding.EncodeToString(buf.Bytes()))
 
@@ -119,6 +194,9 @@ func CheckPlotApprox(ExampleFunc func(), t *testing.T, delta float64, filenames
 				}
 				t.Log("image check:")
 				t.Log("IMAGE:" + base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+			default:
+				t.Errorf("image mismatch for %s\n", path)
 			}
 		}
 	}
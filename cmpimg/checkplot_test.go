@@ -0,0 +1,68 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmpimg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPlotUpdate(t *testing.T) {
+	defer func(v bool) { UpdateGolden = v }(UpdateGolden)
+	UpdateGolden = true
+
+	names := []string{"update_same.svg", "update_diff.svg", "update_new.svg"}
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join("testdata", name)
+		defer os.Remove(paths[i])
+		defer os.Remove(goldenPath(paths[i]))
+	}
+	write := func(path, content string) {
+		err := os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatalf("could not write %s: %v", path, err)
+		}
+	}
+	write(goldenPath(paths[0]), "<svg>1.0000001</svg>")
+	write(goldenPath(paths[1]), "<svg>old</svg>")
+
+	mock := new(testing.T)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		CheckPlot(func() {
+			for _, path := range paths {
+				write(path, "<svg>1</svg>")
+			}
+		}, mock, names...)
+	}()
+	<-done
+	if mock.Failed() {
+		t.Errorf("unexpected failure in update mode")
+	}
+
+	for i, want := range []struct {
+		golden    string
+		generated bool
+	}{
+		{golden: "<svg>1.0000001</svg>", generated: true},
+		{golden: "<svg>1</svg>", generated: false},
+		{golden: "<svg>1</svg>", generated: false},
+	} {
+		got, err := os.ReadFile(goldenPath(paths[i]))
+		if err != nil {
+			t.Errorf("could not read golden file of %s: %v", names[i], err)
+		}
+		if string(got) != want.golden {
+			t.Errorf("unexpected golden file for %s: got:%q want:%q", names[i], got, want.golden)
+		}
+		_, err = os.Stat(paths[i])
+		if generated := err == nil; generated != want.generated {
+			t.Errorf("unexpected generated file for %s: got:%v want:%v", names[i], generated, want.generated)
+		}
+	}
+}
@@ -40,6 +40,8 @@ func Equal(typ string, raw1, raw2 []byte) (bool, error) {
 // and returns whether the two images are equal or not.
 //
 // EqualApprox may return an error if the decoding of the raw image somehow failed.
+// EPS, PDF and SVG documents are normalized before their comparison, as
+// described by Normalize.
 // EqualApprox only uses the normalized delta parameter for "jpeg", "jpg", "png",
 // and "tiff" images. It ignores that parameter for other document types.
 func EqualApprox(typ string, raw1, raw2 []byte, delta float64) (bool, error) {
@@ -51,23 +53,11 @@ func EqualApprox(typ string, raw1, raw2 []byte, delta float64) (bool, error) {
 	}
 
 	switch typ {
-	case "svg", "tex":
+	case "tex", "txt", "ansi", "html":
 		return bytes.Equal(raw1, raw2), nil
 
-	case "eps":
-		lines1, lines2 := strings.Split(string(raw1), "\n"), strings.Split(string(raw2), "\n")
-		if len(lines1) != len(lines2) {
-			return false, nil
-		}
-		for i, line1 := range lines1 {
-			if strings.Contains(line1, "CreationDate") {
-				continue
-			}
-			if line1 != lines2[i] {
-				return false, nil
-			}
-		}
-		return true, nil
+	case "svg", "eps":
+		return bytes.Equal(Normalize(typ, raw1), Normalize(typ, raw2)), nil
 
 	case "pdf":
 		pdf1, err := pdf.NewReader(bytes.NewReader(raw1), int64(len(raw1)))
@@ -106,16 +96,29 @@ func cmpPdf(pdf1, pdf2 *pdf.Reader) bool {
 	}
 
 	for i := 1; i <= n1; i++ {
-		p1 := pdf1.Page(i).Content()
-		p2 := pdf2.Page(i).Content()
+		p1 := normalizePDF(pdf1.Page(i).Content())
+		p2 := normalizePDF(pdf2.Page(i).Content())
 		if !reflect.DeepEqual(p1, p2) {
 			return false
 		}
 	}
 
-	t1 := pdf1.Trailer().String()
-	t2 := pdf2.Trailer().String()
-	return t1 == t2
+	return trailer(pdf1) == trailer(pdf2)
+}
+
+// trailer returns the trailer of the PDF document, without
+// its volatile Info and ID entries.
+func trailer(r *pdf.Reader) string {
+	var buf strings.Builder
+	t := r.Trailer()
+	for _, k := range t.Keys() {
+		switch k {
+		case "Info", "ID":
+			continue
+		}
+		fmt.Fprintf(&buf, "/%s %v ", k, t.Key(k))
+	}
+	return buf.String()
 }
 
 func cmpImg(v1, v2 image.Image, delta float64) bool {
@@ -160,7 +163,12 @@ func cmpImg(v1, v2 image.Image, delta float64) bool {
 // - http://www.progmat.uaem.mx:8080/artVol2Num2/Articulo3Vol2Num2.pdf
 func yiqEqApprox(c1, c2 color.RGBA, d2 float64) bool {
 	const max = 35215.0 // difference between 2 maximally different pixels.
+	return yiqDelta(c1, c2) <= max*d2
+}
 
+// yiqDelta returns the squared difference between the colors
+// of 2 pixels in the NTSC YIQ color space.
+func yiqDelta(c1, c2 color.RGBA) float64 {
 	var (
 		r1 = float64(c1.R)
 		g1 = float64(c1.G)
@@ -181,10 +189,8 @@ func yiqEqApprox(c1, c2 color.RGBA, d2 float64) bool {
 		y = y1 - y2
 		i = i1 - i2
 		q = q1 - q2
-
-		diff = 0.5053*y*y + 0.299*i*i + 0.1957*q*q
 	)
-	return diff <= max*d2
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
 }
 
 func newRGBAFrom(src image.Image) *image.RGBA {
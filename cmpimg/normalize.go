@@ -0,0 +1,104 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmpimg
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+
+	"rsc.io/pdf"
+)
+
+// scale is the inverse of the precision of the numbers
+// of the documents kept by their normalization.
+const scale = 1e3
+
+var (
+	floatRE   = regexp.MustCompile(`-?[0-9]*\.[0-9]+(?:[eE][-+]?[0-9]+)?`)
+	commentRE = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// Normalize returns the raw representation of a document of the
+// given type ("eps", "svg"), with its volatile content removed so
+// that documents drawing the same content compare equal:
+//   - the comments of SVG documents, and the lines of EPS
+//     documents holding their CreationDate, Creator or
+//     Producer;
+//   - the floating point noise of the numbers, which are
+//     rounded to 3 decimals.
+//
+// Normalize returns raw unchanged for other document types.
+// PDF documents are compared by their parsed content, which is
+// normalized in the same way by Equal and EqualApprox.
+func Normalize(typ string, raw []byte) []byte {
+	switch typ {
+	case "svg":
+		return roundFloats(commentRE.ReplaceAll(raw, nil))
+	case "eps":
+		lines := bytes.Split(raw, []byte("\n"))
+		dst := lines[:0]
+		for _, line := range lines {
+			if !isVolatileEPS(line) {
+				dst = append(dst, line)
+			}
+		}
+		return roundFloats(bytes.Join(dst, []byte("\n")))
+	default:
+		return raw
+	}
+}
+
+func isVolatileEPS(line []byte) bool {
+	if bytes.Contains(line, []byte("CreationDate")) {
+		return true
+	}
+	for _, prefix := range []string{"%%Creator", "%%Producer"} {
+		if bytes.HasPrefix(line, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// roundFloats returns b with its decimal numbers rounded.
+func roundFloats(b []byte) []byte {
+	return floatRE.ReplaceAllFunc(b, func(s []byte) []byte {
+		v, err := strconv.ParseFloat(string(s), 64)
+		if err != nil {
+			return s
+		}
+		return strconv.AppendFloat(nil, round(v), 'f', -1, 64)
+	})
+}
+
+// round returns v rounded to the normalized number of decimals.
+func round(v float64) float64 {
+	v = math.Round(v*scale) / scale
+	if v == 0 {
+		// Remove the sign of negative zeros.
+		return 0
+	}
+	return v
+}
+
+// normalizePDF returns the content of the page, with its
+// numbers rounded.
+func normalizePDF(c pdf.Content) pdf.Content {
+	for i, t := range c.Text {
+		t.FontSize = round(t.FontSize)
+		t.X = round(t.X)
+		t.Y = round(t.Y)
+		t.W = round(t.W)
+		c.Text[i] = t
+	}
+	for i, r := range c.Rect {
+		r.Min.X, r.Min.Y = round(r.Min.X), round(r.Min.Y)
+		r.Max.X, r.Max.Y = round(r.Max.X), round(r.Max.Y)
+		c.Rect[i] = r
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmpimg
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, test := range []struct {
+		typ  string
+		raw  string
+		want string
+	}{
+		{
+			typ:  "svg",
+			raw:  "<?xml version=\"1.0\"?>\n<!-- Generated by\nsomething -->\n<path d=\"M0.1234,-0.0001L12.0000001,3\" />",
+			want: "<?xml version=\"1\"?>\n\n<path d=\"M0.123,0L12,3\" />",
+		},
+		{
+			typ:  "eps",
+			raw:  "%!PS-Adobe-3.0 EPSF-3.0\n%%Creator me\n%%CreationDate: now\n%%Producer you\n1.99999 2.5 moveto\n",
+			want: "%!PS-Adobe-3 EPSF-3\n2 2.5 moveto\n",
+		},
+		{
+			typ:  "txt",
+			raw:  "1.23456",
+			want: "1.23456",
+		},
+	} {
+		got := string(Normalize(test.typ, []byte(test.raw)))
+		if got != test.want {
+			t.Errorf("unexpected normalized %s:\ngot: %q\nwant:%q", test.typ, got, test.want)
+		}
+	}
+}
+
+func TestEqualNormalized(t *testing.T) {
+	for _, test := range []struct {
+		typ        string
+		raw1, raw2 string
+		want       bool
+	}{
+		{typ: "svg", raw1: "<!-- A -->\n<path d=\"M1.0000001,2\"/>", raw2: "<!-- B -->\n<path d=\"M0.9999999,2\"/>", want: true},
+		{typ: "svg", raw1: "<path d=\"M1.1,2\"/>", raw2: "<path d=\"M1.2,2\"/>", want: false},
+		{typ: "eps", raw1: "%%Creator A\n0.30000000000000004 setgray\n", raw2: "%%Creator B\n0.3 setgray\n", want: true},
+		{typ: "eps", raw1: "0.3 setgray\n", raw2: "0.4 setgray\n", want: false},
+	} {
+		got, err := Equal(test.typ, []byte(test.raw1), []byte(test.raw2))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != test.want {
+			t.Errorf("unexpected result for %s %q and %q: got:%v want:%v", test.typ, test.raw1, test.raw2, got, test.want)
+		}
+	}
+
+	// The PDF documents of the vgpdf golden files differ by
+	// their creation date.
+	raw1, err := os.ReadFile("../vg/vgpdf/testdata/arc_golden.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := Equal("pdf", raw1, raw1)
+	if !ok || err != nil {
+		t.Errorf("unexpected result for identical pdf: got:%v, %v", ok, err)
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmpimg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Metric is a measure of the difference between two raster images.
+type Metric int
+
+const (
+	// YIQ is the largest difference between the colors of
+	// the pixels of the images in the NTSC YIQ color space,
+	// as used by EqualApprox.
+	YIQ Metric = iota
+
+	// SSIM is one minus the mean structural similarity
+	// index of the luma of the images, over 8×8 windows.
+	// It is tolerant of small changes of the intensity
+	// and of the contrast of the images, such as the ones
+	// produced by different gamma or anti-aliasing, while
+	// it is sensitive to changes of their structure.
+	SSIM
+
+	// Antialias is the fraction of the pixels of the images
+	// that differ and are not anti-aliased pixels of either
+	// image. The pixels differ if their YIQ difference is
+	// larger than 0.1, and are anti-aliased if they lie
+	// between a darker and a brighter neighbor, each of
+	// which have several identical neighbors in both
+	// images, as described by the pixelmatch library.
+	Antialias
+)
+
+// String returns the name of the metric.
+func (m Metric) String() string {
+	switch m {
+	case YIQ:
+		return "YIQ"
+	case SSIM:
+		return "SSIM"
+	case Antialias:
+		return "Antialias"
+	default:
+		return fmt.Sprintf("Metric(%d)", int(m))
+	}
+}
+
+// Distance returns the difference between the raster images
+// a and b measured by the metric m. The difference is between
+// 0 for identical images and 1 for maximally different images,
+// and is 1 for images of different sizes.
+func Distance(m Metric, a, b image.Image) float64 {
+	img1, ok := a.(*image.RGBA)
+	if !ok {
+		img1 = newRGBAFrom(a)
+	}
+	img2, ok := b.(*image.RGBA)
+	if !ok {
+		img2 = newRGBAFrom(b)
+	}
+	if img1.Bounds().Size() != img2.Bounds().Size() {
+		return 1
+	}
+	if img1.Bounds().Empty() {
+		return 0
+	}
+
+	switch m {
+	case YIQ:
+		return yiqDistance(img1, img2)
+	case SSIM:
+		return ssimDistance(img1, img2)
+	case Antialias:
+		return antialiasDistance(img1, img2)
+	default:
+		panic(fmt.Errorf("cmpimg: unknown metric %v", m))
+	}
+}
+
+// EqualMetric takes the raw representation of two images, raw1 and raw2,
+// together with the underlying image type ("eps", "jpeg", "jpg", "pdf", "png", "svg", "tiff"),
+// and returns whether the distance between the images measured by the
+// metric m is at most threshold, and the distance.
+//
+// EqualMetric may return an error if the decoding of the raw image somehow failed.
+// EqualMetric only uses the metric and the threshold for "jpeg", "jpg", "png",
+// and "tiff" images. The distance between documents of other types is 0 if
+// they are equal, and 1 otherwise.
+func EqualMetric(typ string, raw1, raw2 []byte, m Metric, threshold float64) (bool, float64, error) {
+	switch typ {
+	case "jpeg", "jpg", "png", "tiff":
+		v1, _, err := image.Decode(bytes.NewReader(raw1))
+		if err != nil {
+			return false, 1, err
+		}
+		v2, _, err := image.Decode(bytes.NewReader(raw2))
+		if err != nil {
+			return false, 1, err
+		}
+		d := Distance(m, v1, v2)
+		return d <= threshold, d, nil
+
+	default:
+		ok, err := Equal(typ, raw1, raw2)
+		if !ok || err != nil {
+			return false, 1, err
+		}
+		return true, 0, nil
+	}
+}
+
+func yiqDistance(a, b *image.RGBA) float64 {
+	const max = 35215.0 // difference between 2 maximally different pixels.
+
+	var d float64
+	bnd := a.Bounds()
+	off := b.Bounds().Min.Sub(bnd.Min)
+	for y := bnd.Min.Y; y < bnd.Max.Y; y++ {
+		for x := bnd.Min.X; x < bnd.Max.X; x++ {
+			d = math.Max(d, yiqDelta(a.RGBAAt(x, y), b.RGBAAt(x+off.X, y+off.Y)))
+		}
+	}
+	return math.Sqrt(d / max)
+}
+
+// luma returns the luma of the pixels of img, composited
+// over a white background, in row-major order.
+func luma(img *image.RGBA) []float64 {
+	bnd := img.Bounds()
+	dst := make([]float64, 0, bnd.Dx()*bnd.Dy())
+	for y := bnd.Min.Y; y < bnd.Max.Y; y++ {
+		for x := bnd.Min.X; x < bnd.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			bg := 255 - float64(c.A)
+			r := float64(c.R) + bg
+			g := float64(c.G) + bg
+			b := float64(c.B) + bg
+			dst = append(dst, r*0.29889531+g*0.58662247+b*0.11448223)
+		}
+	}
+	return dst
+}
+
+func ssimDistance(a, b *image.RGBA) float64 {
+	const (
+		win = 8
+		c1  = (0.01 * 255) * (0.01 * 255)
+		c2  = (0.03 * 255) * (0.03 * 255)
+	)
+
+	w, h := a.Bounds().Dx(), a.Bounds().Dy()
+	ya, yb := luma(a), luma(b)
+
+	// Summed-area tables of the luma, of their squares and
+	// of their product, with a leading row and column of
+	// zeros.
+	var (
+		stride = w + 1
+		sa     = make([]float64, stride*(h+1))
+		sb     = make([]float64, len(sa))
+		saa    = make([]float64, len(sa))
+		sbb    = make([]float64, len(sa))
+		sab    = make([]float64, len(sa))
+	)
+	acc := func(s []float64, i int, v float64) {
+		s[i] = v + s[i-1] + s[i-stride] - s[i-stride-1]
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			va, vb := ya[y*w+x], yb[y*w+x]
+			i := (y+1)*stride + x + 1
+			acc(sa, i, va)
+			acc(sb, i, vb)
+			acc(saa, i, va*va)
+			acc(sbb, i, vb*vb)
+			acc(sab, i, va*vb)
+		}
+	}
+
+	ww, wh := win, win
+	if w < ww {
+		ww = w
+	}
+	if h < wh {
+		wh = h
+	}
+	n := float64(ww * wh)
+	sum := func(s []float64, x, y int) float64 {
+		return s[(y+wh)*stride+x+ww] - s[y*stride+x+ww] - s[(y+wh)*stride+x] + s[y*stride+x]
+	}
+
+	var total float64
+	for y := 0; y+wh <= h; y++ {
+		for x := 0; x+ww <= w; x++ {
+			ma := sum(sa, x, y) / n
+			mb := sum(sb, x, y) / n
+			va := sum(saa, x, y)/n - ma*ma
+			vb := sum(sbb, x, y)/n - mb*mb
+			cov := sum(sab, x, y)/n - ma*mb
+			total += ((2*ma*mb + c1) * (2*cov + c2)) / ((ma*ma + mb*mb + c1) * (va + vb + c2))
+		}
+	}
+	ssim := total / float64((w-ww+1)*(h-wh+1))
+	return math.Min(math.Max(1-ssim, 0), 1)
+}
+
+func antialiasDistance(a, b *image.RGBA) float64 {
+	const threshold = 0.1
+
+	var (
+		bnd  = a.Bounds()
+		w, h = bnd.Dx(), bnd.Dy()
+		pa   = pixels{img: a, luma: luma(a)}
+		pb   = pixels{img: b, luma: luma(b)}
+		max  = 35215.0 * threshold * threshold
+		n    int
+	)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if yiqDelta(pa.at(x, y), pb.at(x, y)) <= max {
+				continue
+			}
+			if pa.antialiased(x, y, pb) || pb.antialiased(x, y, pa) {
+				continue
+			}
+			n++
+		}
+	}
+	return float64(n) / float64(w*h)
+}
+
+// pixels is an image with the luma of its pixels.
+type pixels struct {
+	img  *image.RGBA
+	luma []float64
+}
+
+// at returns the color of the pixel at (x, y), relative
+// to the bounds of the image.
+func (p pixels) at(x, y int) color.RGBA {
+	min := p.img.Bounds().Min
+	return p.img.RGBAAt(min.X+x, min.Y+y)
+}
+
+// antialiased returns whether the pixel at (x, y), relative to
+// the bounds of the image, is an anti-aliased pixel, that is
+// whether it has at most two identical neighbors, and both a
+// darker and a brighter neighbor, one of which has more than
+// two identical neighbors in both the image and the other
+// image.
+func (p pixels) antialiased(x, y int, other pixels) bool {
+	var (
+		w, h           = p.img.Bounds().Dx(), p.img.Bounds().Dy()
+		l              = p.luma[y*w+x]
+		zeros          = edge(x, y, w, h)
+		min, max       float64
+		minX, minY     int
+		maxX, maxY     int
+		x0, y0, x1, y1 = neighborhood(x, y, w, h)
+	)
+	for nx := x0; nx <= x1; nx++ {
+		for ny := y0; ny <= y1; ny++ {
+			if nx == x && ny == y {
+				continue
+			}
+			switch d := p.luma[ny*w+nx] - l; {
+			case d == 0:
+				zeros++
+				if zeros > 2 {
+					return false
+				}
+			case d < min:
+				min, minX, minY = d, nx, ny
+			case d > max:
+				max, maxX, maxY = d, nx, ny
+			}
+		}
+	}
+	if min == 0 || max == 0 {
+		return false
+	}
+	return (p.hasManySiblings(minX, minY) && other.hasManySiblings(minX, minY)) ||
+		(p.hasManySiblings(maxX, maxY) && other.hasManySiblings(maxX, maxY))
+}
+
+// hasManySiblings returns whether the pixel at (x, y) has more
+// than two identical neighbors.
+func (p pixels) hasManySiblings(x, y int) bool {
+	var (
+		w, h           = p.img.Bounds().Dx(), p.img.Bounds().Dy()
+		c              = p.at(x, y)
+		zeros          = edge(x, y, w, h)
+		x0, y0, x1, y1 = neighborhood(x, y, w, h)
+	)
+	for nx := x0; nx <= x1; nx++ {
+		for ny := y0; ny <= y1; ny++ {
+			if nx == x && ny == y {
+				continue
+			}
+			if p.at(nx, ny) == c {
+				zeros++
+				if zeros > 2 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// neighborhood returns the bounds of the neighborhood of the
+// pixel at (x, y) in an image of size w×h.
+func neighborhood(x, y, w, h int) (x0, y0, x1, y1 int) {
+	x0, y0 = x-1, y-1
+	if x0 < 0 {
+		x0 = 0
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+	x1, y1 = x+1, y+1
+	if x1 > w-1 {
+		x1 = w - 1
+	}
+	if y1 > h-1 {
+		y1 = h - 1
+	}
+	return x0, y0, x1, y1
+}
+
+// edge returns 1 if the pixel at (x, y) lies on the edge of an
+// image of size w×h, counting the missing neighbors as one
+// identical neighbor, and 0 otherwise.
+func edge(x, y, w, h int) int {
+	if x == 0 || x == w-1 || y == 0 || y == h-1 {
+		return 1
+	}
+	return 0
+}
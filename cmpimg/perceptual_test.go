@@ -0,0 +1,192 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmpimg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// square returns a white image with a black square, whose left
+// edge at x0 is painted with the color edge.
+func square(x0 int, edge color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			c := color.Color(color.White)
+			switch {
+			case y < 8 || y >= 24 || x < x0 || x >= 24:
+			case x == x0:
+				c = edge
+			default:
+				c = color.Black
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDistance(t *testing.T) {
+	var (
+		hard    = square(8, color.Black)
+		aliased = square(8, color.Gray{Y: 0x80})
+		shifted = square(10, color.Black)
+		small   = image.NewRGBA(image.Rect(0, 0, 8, 8))
+	)
+	blank := image.NewRGBA(hard.Bounds())
+	for i := range blank.Pix {
+		blank.Pix[i] = 0xff
+	}
+	inverted := image.NewRGBA(hard.Bounds())
+	for i := range inverted.Pix {
+		inverted.Pix[i] = 0
+		if i%4 == 3 {
+			inverted.Pix[i] = 0xff
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		a, b     image.Image
+		min, max [3]float64
+	}{
+		{
+			name: "identical",
+			a:    hard, b: hard,
+		},
+		{
+			name: "size",
+			a:    hard, b: small,
+			min: [3]float64{1, 1, 1}, max: [3]float64{1, 1, 1},
+		},
+		{
+			name: "antialiased",
+			a:    hard, b: aliased,
+			min: [3]float64{0.4, 0, 0}, max: [3]float64{0.6, 0.1, 0},
+		},
+		{
+			name: "shifted",
+			a:    hard, b: shifted,
+			min: [3]float64{0.9, 0.1, 32.0 / 1024}, max: [3]float64{1, 0.5, 32.0 / 1024},
+		},
+		{
+			name: "inverted",
+			a:    blank, b: inverted,
+			min: [3]float64{0.9, 0.99, 1}, max: [3]float64{1, 1, 1},
+		},
+	} {
+		for i, m := range []Metric{YIQ, SSIM, Antialias} {
+			got := Distance(m, test.a, test.b)
+			if got < test.min[i] || test.max[i] < got {
+				t.Errorf("unexpected %v distance for %s: got:%g want in [%g, %g]", m, test.name, got, test.min[i], test.max[i])
+			}
+			if rev := Distance(m, test.b, test.a); rev != got {
+				t.Errorf("asymmetric %v distance for %s: got:%g and %g", m, test.name, got, rev)
+			}
+		}
+	}
+}
+
+func TestEqualMetric(t *testing.T) {
+	got, err := os.ReadFile("testdata/approx_got_golden.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/approx_want_golden.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		metric    Metric
+		threshold float64
+		want      bool
+	}{
+		{metric: YIQ, threshold: 0.02, want: false},
+		{metric: YIQ, threshold: 0.05, want: true},
+		{metric: SSIM, threshold: 0, want: false},
+		{metric: SSIM, threshold: 1e-3, want: true},
+		{metric: Antialias, threshold: 0, want: true},
+	} {
+		ok, dist, err := EqualMetric("png", got, want, test.metric, test.threshold)
+		if err != nil {
+			t.Fatalf("could not compare images: %+v", err)
+		}
+		if ok != test.want {
+			t.Errorf("unexpected result for %v with threshold %g: got:%v (distance %g) want:%v", test.metric, test.threshold, ok, dist, test.want)
+		}
+		approx, err := EqualApprox("png", got, want, test.threshold)
+		if err != nil {
+			t.Fatalf("could not compare images: %+v", err)
+		}
+		if test.metric == YIQ && ok != approx {
+			t.Errorf("unexpected YIQ result with threshold %g: got:%v EqualApprox:%v", test.threshold, ok, approx)
+		}
+	}
+
+	ok, dist, err := EqualMetric("svg", []byte("<svg/>"), []byte("<svg />"), SSIM, 1)
+	if ok || dist != 1 || err != nil {
+		t.Errorf("unexpected result for different svg: got:%v, %g, %v", ok, dist, err)
+	}
+	ok, dist, err = EqualMetric("svg", []byte("<svg/>"), []byte("<svg/>"), SSIM, 0)
+	if !ok || dist != 0 || err != nil {
+		t.Errorf("unexpected result for identical svg: got:%v, %g, %v", ok, dist, err)
+	}
+}
+
+func TestCheckPlotMetricDiff(t *testing.T) {
+	const name = "check_metric.png"
+	path := filepath.Join("testdata", name)
+	write := func(path string, img image.Image) {
+		var buf bytes.Buffer
+		err := png.Encode(&buf, img)
+		if err != nil {
+			t.Fatalf("could not encode image: %v", err)
+		}
+		err = os.WriteFile(path, buf.Bytes(), 0o644)
+		if err != nil {
+			t.Fatalf("could not write image: %v", err)
+		}
+	}
+	write(goldenPath(path), square(8, color.Black))
+	defer os.Remove(goldenPath(path))
+	defer os.Remove(path)
+	defer os.Remove(diffPath(path))
+
+	for _, ex := range []struct {
+		img    image.Image
+		metric Metric
+		failed bool
+	}{
+		{img: square(8, color.Gray{Y: 0x80}), metric: Antialias, failed: false},
+		{img: square(8, color.Gray{Y: 0x80}), metric: YIQ, failed: true},
+	} {
+		// The failures are reported to mock, which is run
+		// in its own goroutine as CheckPlotMetric may call
+		// its FailNow method.
+		var (
+			mock = new(testing.T)
+			done = make(chan struct{})
+		)
+		go func() {
+			defer close(done)
+			CheckPlotMetric(func() { write(path, ex.img) }, mock, ex.metric, 0, name)
+		}()
+		<-done
+		if mock.Failed() != ex.failed {
+			t.Errorf("unexpected failure for %v: got:%v want:%v", ex.metric, mock.Failed(), ex.failed)
+		}
+		_, err := os.Stat(diffPath(path))
+		if exists := err == nil; exists != ex.failed {
+			t.Errorf("unexpected difference image for %v: got:%v want:%v", ex.metric, exists, ex.failed)
+		}
+	}
+}
@@ -0,0 +1,998 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/emptywe/plot/text"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// version is the version of the serialization format of plots.
+const version = 1
+
+// pngType is the type name of the images encoded as PNG.
+const pngType = "image/png"
+
+// Marshaler is the interface implemented by the types that are
+// serialized by the value returned by their MarshalPlot method,
+// typically because they hold unexported state.
+type Marshaler interface {
+	// MarshalPlot returns the value serialized in place
+	// of the receiver. The type of the value must differ
+	// from the type of the receiver.
+	MarshalPlot() (interface{}, error)
+}
+
+// Unmarshaler is the interface implemented by the types that
+// are deserialized from the value returned by their MarshalPlot
+// method.
+type Unmarshaler interface {
+	// UnmarshalPlot sets the receiver from the value
+	// returned by MarshalPlot, which it decodes by
+	// calling unmarshal with a pointer to a value of
+	// the same type.
+	UnmarshalPlot(unmarshal func(interface{}) error) error
+}
+
+var (
+	marshalerType       = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	imageType           = reflect.TypeOf((*image.Image)(nil)).Elem()
+)
+
+var registry = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+	funcs map[string]reflect.Value
+	code  map[uintptr]string
+}{
+	types: make(map[string]reflect.Type),
+	names: make(map[reflect.Type]string),
+	funcs: make(map[string]reflect.Value),
+	code:  make(map[uintptr]string),
+}
+
+// Register records the concrete type of v, under the name of
+// its type such as "*plotter.Line", for the serialization of
+// the plots holding values of this type in fields of interface
+// type, such as the plotters of a plot or the Marker of an axis.
+//
+// The types of the plot, plotter, text and vg/draw packages and
+// the colors of the image/color and vg packages are registered
+// by these packages. The types of the palette/brewer and
+// palette/moreland packages are registered by the plot/gob
+// package.
+func Register(v interface{}) {
+	RegisterName(reflect.TypeOf(v).String(), v)
+}
+
+// RegisterName is like Register, but uses the provided name
+// rather than the name of the type. RegisterName panics if the
+// type or the name are already registered with another name or
+// type.
+func RegisterName(name string, v interface{}) {
+	if name == "" {
+		panic("plot: attempt to register empty name")
+	}
+	typ := reflect.TypeOf(v)
+	registry.Lock()
+	defer registry.Unlock()
+	if t, ok := registry.types[name]; ok && t != typ {
+		panic(fmt.Sprintf("plot: registering duplicate types for %q: %v != %v", name, t, typ))
+	}
+	if n, ok := registry.names[typ]; ok && n != name {
+		panic(fmt.Sprintf("plot: registering duplicate names for %v: %q != %q", typ, n, name))
+	}
+	registry.types[name] = typ
+	registry.names[typ] = name
+}
+
+// RegisterFunc records the function fn under name, for the
+// serialization of the plots holding fn in fields of function
+// type, such as the F field of a plotter.Function.
+//
+// Functions are identified by their code, so that fn must not
+// be a closure: all the closures created by a function literal
+// would be serialized as fn.
+func RegisterFunc(name string, fn interface{}) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		panic(fmt.Sprintf("plot: attempt to register %T as a function", fn))
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.funcs[name]; ok {
+		panic(fmt.Sprintf("plot: registering duplicate functions for %q", name))
+	}
+	if n, ok := registry.code[v.Pointer()]; ok {
+		panic(fmt.Sprintf("plot: registering function %q as %q", n, name))
+	}
+	registry.funcs[name] = v
+	registry.code[v.Pointer()] = name
+}
+
+func typeName(typ reflect.Type) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	name, ok := registry.names[typ]
+	return name, ok
+}
+
+func typeByName(name string) (reflect.Type, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	typ, ok := registry.types[name]
+	return typ, ok
+}
+
+func funcName(fn reflect.Value) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	name, ok := registry.code[fn.Pointer()]
+	return name, ok
+}
+
+func funcByName(name string) (reflect.Value, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	fn, ok := registry.funcs[name]
+	return fn, ok
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+//
+// The plot is encoded as a versioned JSON document holding
+// its exported fields, its plotters and its legend entries.
+// The values of interface type are encoded with the name of
+// their type, which must be registered with Register, and the
+// functions with their name, which must be registered with
+// RegisterFunc. The images are encoded as PNG, the non-finite
+// numbers as strings, and the fonts of the font caches by
+// their descriptors. The values referenced by several pointers,
+// such as the bar charts stacked on each other, are encoded
+// once.
+func (p *Plot) MarshalJSON() ([]byte, error) {
+	doc, err := p.document()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// setting the plot to the plot encoded by MarshalJSON.
+// The faces of the fonts of the font caches are looked up in
+// font.DefaultCache.
+func (p *Plot) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc document
+	err := dec.Decode(&doc)
+	if err != nil {
+		return err
+	}
+	return p.setDocument(doc)
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+// The plot is encoded as with MarshalJSON, in the compact
+// binary format of the encoding/gob package.
+func (p *Plot) GobEncode() ([]byte, error) {
+	doc, err := p.document()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface,
+// setting the plot to the plot encoded by GobEncode.
+func (p *Plot) GobDecode(b []byte) error {
+	var doc document
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&doc)
+	if err != nil {
+		return err
+	}
+	return p.setDocument(doc)
+}
+
+// document is the serialized form of a Plot.
+type document struct {
+	Version int
+	Plot    interface{}
+}
+
+func (p *Plot) document() (document, error) {
+	v, err := marshal(reflect.ValueOf(p))
+	if err != nil {
+		return document{}, err
+	}
+	return document{Version: version, Plot: v}, nil
+}
+
+func (p *Plot) setDocument(doc document) error {
+	if doc.Version != version {
+		return fmt.Errorf("plot: unsupported format version %d", doc.Version)
+	}
+	var dec decoder
+	return dec.decode(doc.Plot, reflect.ValueOf(p).Elem())
+}
+
+// plotValue is a Plot without its methods, serialized
+// with its exported fields.
+type plotValue Plot
+
+// plotState is the serialized form of a Plot.
+type plotState struct {
+	Plot     plotValue
+	Plotters []Plotter
+}
+
+// MarshalPlot implements the Marshaler interface.
+func (p *Plot) MarshalPlot() (interface{}, error) {
+	return plotState{Plot: plotValue(*p), Plotters: p.plotters}, nil
+}
+
+// UnmarshalPlot implements the Unmarshaler interface.
+func (p *Plot) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st plotState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*p = Plot(st.Plot)
+	p.plotters = st.Plotters
+	return nil
+}
+
+// legendValue is a Legend without its methods, serialized
+// with its exported fields.
+type legendValue Legend
+
+// legendState is the serialized form of a Legend.
+type legendState struct {
+	Legend  legendValue
+	Entries []legendEntryState
+}
+
+// legendEntryState is the serialized form of a legendEntry.
+type legendEntryState struct {
+	Text   string
+	Thumbs []Thumbnailer
+}
+
+// MarshalPlot implements the Marshaler interface.
+func (l *Legend) MarshalPlot() (interface{}, error) {
+	st := legendState{Legend: legendValue(*l)}
+	for _, e := range l.entries {
+		st.Entries = append(st.Entries, legendEntryState{Text: e.text, Thumbs: e.thumbs})
+	}
+	return st, nil
+}
+
+// UnmarshalPlot implements the Unmarshaler interface.
+func (l *Legend) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st legendState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*l = Legend(st.Legend)
+	for _, e := range st.Entries {
+		l.entries = append(l.entries, legendEntry{text: e.Text, thumbs: e.Thumbs})
+	}
+	return nil
+}
+
+// groupPlotterState is the serialized form of a groupPlotter.
+type groupPlotterState struct {
+	Plotter Plotter
+	Group   vg.Group
+}
+
+// MarshalPlot implements the Marshaler interface.
+func (gp groupPlotter) MarshalPlot() (interface{}, error) {
+	return groupPlotterState{Plotter: gp.Plotter, Group: gp.group}, nil
+}
+
+// UnmarshalPlot implements the Unmarshaler interface.
+func (gp *groupPlotter) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st groupPlotterState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*gp = groupPlotter{Plotter: st.Plotter, group: st.Group}
+	return nil
+}
+
+// ptrKey identifies the value pointed to by a pointer.
+type ptrKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// encoder encodes values into trees of maps, slices and
+// basic values.
+type encoder struct {
+	// counting indicates whether the encoder
+	// only counts the references to the pointers.
+	counting bool
+	refs     map[ptrKey]int
+	ids      map[ptrKey]int
+}
+
+// marshal returns the encoded form of v, encoded by a first
+// pass counting the references to the pointers and a second
+// pass encoding the pointers referenced several times once.
+func marshal(v reflect.Value) (interface{}, error) {
+	e := encoder{counting: true, refs: make(map[ptrKey]int)}
+	_, err := e.encode(v)
+	if err != nil {
+		return nil, err
+	}
+	e.counting = false
+	e.ids = make(map[ptrKey]int)
+	return e.encode(v)
+}
+
+func (e *encoder) encode(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	typ := v.Type()
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return e.encodePtr(v)
+	case reflect.Interface:
+		return e.encodeInterface(v)
+	}
+
+	if typ.Implements(marshalerType) || reflect.PtrTo(typ).Implements(marshalerType) {
+		m, err := addr(v).Interface().(Marshaler).MarshalPlot()
+		if err != nil {
+			return nil, err
+		}
+		if reflect.TypeOf(m) == typ {
+			return nil, fmt.Errorf("plot: %v.MarshalPlot returned a value of the same type", typ)
+		}
+		return e.encode(reflect.ValueOf(m))
+	}
+	if typ.Implements(jsonMarshalerType) || reflect.PtrTo(typ).Implements(jsonMarshalerType) {
+		if e.counting {
+			return nil, nil
+		}
+		b, err := addr(v).Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var tree interface{}
+		err = json.Unmarshal(b, &tree)
+		return tree, err
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return strconv.FormatFloat(f, 'g', -1, 64), nil
+		}
+		return f, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), v.Bytes()...), nil
+		}
+		return e.encodeElems(v)
+	case reflect.Array:
+		return e.encodeElems(v)
+	case reflect.Map:
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	case reflect.Func:
+		if v.IsNil() {
+			return nil, nil
+		}
+		name, ok := funcName(v)
+		if !ok {
+			fn := runtime.FuncForPC(v.Pointer())
+			return nil, fmt.Errorf("plot: function %s of type %v is not registered", fn.Name(), typ)
+		}
+		return name, nil
+	default:
+		return nil, fmt.Errorf("plot: cannot encode value of type %v", typ)
+	}
+}
+
+// addr returns a pointer to the value of v.
+func addr(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
+
+func (e *encoder) encodePtr(v reflect.Value) (interface{}, error) {
+	if v.IsNil() {
+		return nil, nil
+	}
+	key := ptrKey{typ: v.Type(), ptr: v.Pointer()}
+	if e.counting {
+		e.refs[key]++
+		if e.refs[key] > 1 {
+			return nil, nil
+		}
+		return e.encode(v.Elem())
+	}
+	if id, ok := e.ids[key]; ok {
+		return map[string]interface{}{"$ref": int64(id)}, nil
+	}
+	if e.refs[key] < 2 {
+		return e.encode(v.Elem())
+	}
+	id := len(e.ids) + 1
+	e.ids[key] = id
+	elem, err := e.encode(v.Elem())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"$id": int64(id), "$value": elem}, nil
+}
+
+func (e *encoder) encodeInterface(v reflect.Value) (interface{}, error) {
+	if v.IsNil() {
+		return nil, nil
+	}
+	elem := v.Elem()
+	name, ok := typeName(elem.Type())
+	if !ok {
+		if img, ok := elem.Interface().(image.Image); ok {
+			if e.counting {
+				return nil, nil
+			}
+			var buf bytes.Buffer
+			err := png.Encode(&buf, img)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"Type": pngType, "Value": buf.Bytes()}, nil
+		}
+		return nil, fmt.Errorf("plot: type %v is not registered", elem.Type())
+	}
+	val, err := e.encode(elem)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"Type": name, "Value": val}, nil
+}
+
+func (e *encoder) encodeElems(v reflect.Value) (interface{}, error) {
+	elems := make([]interface{}, v.Len())
+	for i := range elems {
+		var err error
+		elems[i], err = e.encode(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return elems, nil
+}
+
+// encodeMap encodes the map v as a list of key and value pairs,
+// sorted by key.
+func (e *encoder) encodeMap(v reflect.Value) (interface{}, error) {
+	if v.IsNil() {
+		return nil, nil
+	}
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	pairs := make([]interface{}, len(keys))
+	for i, k := range keys {
+		key, err := e.encode(k)
+		if err != nil {
+			return nil, err
+		}
+		val, err := e.encode(v.MapIndex(k))
+		if err != nil {
+			return nil, err
+		}
+		pairs[i] = []interface{}{key, val}
+	}
+	return pairs, nil
+}
+
+// encodeStruct encodes the exported fields of the struct v
+// that do not hold their zero value.
+func (e *encoder) encodeStruct(v reflect.Value) (interface{}, error) {
+	typ := v.Type()
+	fields := make(map[string]interface{})
+	for _, f := range structFields(typ) {
+		fv := v.FieldByIndex(f.index)
+		if fv.IsZero() {
+			continue
+		}
+		val, err := e.encode(fv)
+		if err != nil {
+			return nil, fmt.Errorf("%w (field %s of %v)", err, f.name, typ)
+		}
+		fields[f.name] = val
+	}
+	return fields, nil
+}
+
+// field is an exported field of a struct.
+type field struct {
+	name  string
+	index []int
+}
+
+// structFields returns the exported fields of the struct type typ,
+// including the exported fields of its embedded structs of unexported
+// type, which are promoted as with encoding/json.
+func structFields(typ reflect.Type) []field {
+	var fields []field
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		switch {
+		case f.PkgPath == "":
+			fields = append(fields, field{name: f.Name, index: []int{i}})
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			for _, sub := range structFields(f.Type) {
+				fields = append(fields, field{name: sub.name, index: append([]int{i}, sub.index...)})
+			}
+		}
+	}
+
+	// Remove the promoted fields hidden by shallower fields.
+	depth := make(map[string]int)
+	for _, f := range fields {
+		if d, ok := depth[f.name]; !ok || len(f.index) < d {
+			depth[f.name] = len(f.index)
+		}
+	}
+	visible := fields[:0]
+	for _, f := range fields {
+		if len(f.index) == depth[f.name] {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
+
+// decoder decodes the values encoded by an encoder.
+type decoder struct {
+	ptrs map[int64]reflect.Value
+}
+
+// decode sets the settable value v from its encoded form.
+func (d *decoder) decode(tree interface{}, v reflect.Value) error {
+	typ := v.Type()
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return d.decodePtr(tree, v)
+	case reflect.Interface:
+		return d.decodeInterface(tree, v)
+	}
+
+	if reflect.PtrTo(typ).Implements(unmarshalerType) {
+		u := v.Addr().Interface().(Unmarshaler)
+		return u.UnmarshalPlot(func(dst interface{}) error {
+			p := reflect.ValueOf(dst)
+			if p.Kind() != reflect.Ptr || p.IsNil() {
+				return fmt.Errorf("plot: cannot unmarshal into non-pointer %T", dst)
+			}
+			return d.decode(tree, p.Elem())
+		})
+	}
+	if reflect.PtrTo(typ).Implements(jsonUnmarshalerType) {
+		b, err := json.Marshal(tree)
+		if err != nil {
+			return err
+		}
+		return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(b)
+	}
+
+	if tree == nil {
+		v.Set(reflect.Zero(typ))
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, ok := tree.(bool)
+		if !ok {
+			return typeError(tree, typ)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt(tree)
+		if err != nil || v.OverflowInt(i) {
+			return typeError(tree, typ)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := toUint(tree)
+		if err != nil || v.OverflowUint(u) {
+			return typeError(tree, typ)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(tree)
+		if err != nil {
+			return typeError(tree, typ)
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		s, ok := tree.(string)
+		if !ok {
+			return typeError(tree, typ)
+		}
+		v.SetString(s)
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			b, err := toBytes(tree)
+			if err != nil {
+				return typeError(tree, typ)
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		elems, ok := tree.([]interface{})
+		if !ok {
+			return typeError(tree, typ)
+		}
+		v.Set(reflect.MakeSlice(typ, len(elems), len(elems)))
+		return d.decodeElems(elems, v)
+	case reflect.Array:
+		elems, ok := tree.([]interface{})
+		if !ok || len(elems) != v.Len() {
+			return typeError(tree, typ)
+		}
+		return d.decodeElems(elems, v)
+	case reflect.Map:
+		return d.decodeMap(tree, v)
+	case reflect.Struct:
+		return d.decodeStruct(tree, v)
+	case reflect.Func:
+		name, ok := tree.(string)
+		if !ok {
+			return typeError(tree, typ)
+		}
+		fn, ok := funcByName(name)
+		if !ok {
+			return fmt.Errorf("plot: function %q is not registered", name)
+		}
+		if !fn.Type().ConvertibleTo(typ) {
+			return fmt.Errorf("plot: cannot use function %q of type %v as %v", name, fn.Type(), typ)
+		}
+		v.Set(fn.Convert(typ))
+	default:
+		return fmt.Errorf("plot: cannot decode value of type %v", typ)
+	}
+	return nil
+}
+
+func (d *decoder) decodePtr(tree interface{}, v reflect.Value) error {
+	if tree == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if m, ok := tree.(map[string]interface{}); ok {
+		if ref, ok := m["$ref"]; ok {
+			id, err := toInt(ref)
+			if err != nil {
+				return typeError(ref, v.Type())
+			}
+			p, ok := d.ptrs[id]
+			if !ok {
+				return fmt.Errorf("plot: invalid reference %d", id)
+			}
+			if p.Type() != v.Type() {
+				return fmt.Errorf("plot: cannot use reference %d of type %v as %v", id, p.Type(), v.Type())
+			}
+			v.Set(p)
+			return nil
+		}
+		if ref, ok := m["$id"]; ok {
+			id, err := toInt(ref)
+			if err != nil {
+				return typeError(ref, v.Type())
+			}
+			p := reflect.New(v.Type().Elem())
+			if d.ptrs == nil {
+				d.ptrs = make(map[int64]reflect.Value)
+			}
+			d.ptrs[id] = p
+			v.Set(p)
+			return d.decode(m["$value"], p.Elem())
+		}
+	}
+	p := reflect.New(v.Type().Elem())
+	v.Set(p)
+	return d.decode(tree, p.Elem())
+}
+
+func (d *decoder) decodeInterface(tree interface{}, v reflect.Value) error {
+	if tree == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	m, ok := tree.(map[string]interface{})
+	if !ok {
+		return typeError(tree, v.Type())
+	}
+	name, ok := m["Type"].(string)
+	if !ok {
+		return typeError(tree, v.Type())
+	}
+	var val reflect.Value
+	switch typ, ok := typeByName(name); {
+	case ok:
+		val = reflect.New(typ).Elem()
+		err := d.decode(m["Value"], val)
+		if err != nil {
+			return err
+		}
+	case name == pngType:
+		b, err := toBytes(m["Value"])
+		if err != nil {
+			return typeError(m["Value"], imageType)
+		}
+		img, err := png.Decode(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		val = reflect.ValueOf(img)
+	default:
+		return fmt.Errorf("plot: type %q is not registered", name)
+	}
+	if !val.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("plot: cannot use value of type %v as %v", val.Type(), v.Type())
+	}
+	v.Set(val)
+	return nil
+}
+
+func (d *decoder) decodeElems(elems []interface{}, v reflect.Value) error {
+	for i, elem := range elems {
+		err := d.decode(elem, v.Index(i))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decodeMap(tree interface{}, v reflect.Value) error {
+	pairs, ok := tree.([]interface{})
+	if !ok {
+		return typeError(tree, v.Type())
+	}
+	typ := v.Type()
+	m := reflect.MakeMapWithSize(typ, len(pairs))
+	for _, pair := range pairs {
+		kv, ok := pair.([]interface{})
+		if !ok || len(kv) != 2 {
+			return typeError(pair, typ)
+		}
+		key := reflect.New(typ.Key()).Elem()
+		err := d.decode(kv[0], key)
+		if err != nil {
+			return err
+		}
+		val := reflect.New(typ.Elem()).Elem()
+		err = d.decode(kv[1], val)
+		if err != nil {
+			return err
+		}
+		m.SetMapIndex(key, val)
+	}
+	v.Set(m)
+	return nil
+}
+
+func (d *decoder) decodeStruct(tree interface{}, v reflect.Value) error {
+	values, ok := tree.(map[string]interface{})
+	if !ok {
+		return typeError(tree, v.Type())
+	}
+	typ := v.Type()
+	fields := structFields(typ)
+	for name := range values {
+		known := false
+		for _, f := range fields {
+			if f.name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("plot: unknown field %s of %v", name, typ)
+		}
+	}
+
+	// The fields are decoded in the order of their encoding,
+	// which references the shared pointers after their first
+	// occurrence.
+	v.Set(reflect.Zero(typ))
+	for _, f := range fields {
+		val, ok := values[f.name]
+		if !ok {
+			continue
+		}
+		err := d.decode(val, v.FieldByIndex(f.index))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func typeError(tree interface{}, typ reflect.Type) error {
+	return fmt.Errorf("plot: cannot decode %T into value of type %v", tree, typ)
+}
+
+var errNumber = errors.New("plot: invalid number")
+
+func toInt(tree interface{}) (int64, error) {
+	switch v := tree.(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, errNumber
+		}
+		return int64(v), nil
+	case json.Number:
+		return strconv.ParseInt(string(v), 10, 64)
+	}
+	return 0, errNumber
+}
+
+func toUint(tree interface{}) (uint64, error) {
+	switch v := tree.(type) {
+	case uint64:
+		return v, nil
+	case int64:
+		if v < 0 {
+			return 0, errNumber
+		}
+		return uint64(v), nil
+	case json.Number:
+		return strconv.ParseUint(string(v), 10, 64)
+	}
+	return 0, errNumber
+}
+
+func toFloat(tree interface{}) (float64, error) {
+	switch v := tree.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		// Non-finite numbers are encoded as strings.
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || !(math.IsInf(f, 0) || math.IsNaN(f)) {
+			return 0, errNumber
+		}
+		return f, nil
+	}
+	return 0, errNumber
+}
+
+// toBytes returns the bytes of tree, which are encoded
+// as a base64 string by JSON.
+func toBytes(tree interface{}) ([]byte, error) {
+	switch v := tree.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return base64.StdEncoding.DecodeString(v)
+	}
+	return nil, errNumber
+}
+
+func init() {
+	// The serialized plots are encoded by gob as trees
+	// of these types.
+	gob.Register(map[string]interface{}(nil))
+	gob.Register([]interface{}(nil))
+
+	for _, v := range []interface{}{
+		// Ticker
+		ConstantTicks{},
+		DefaultTicks{},
+		LogTicks{},
+		TimeTicks{},
+		TickerFunc(nil),
+
+		// Normalizer
+		LinearScale{},
+		LogScale{},
+		InvertedScale{},
+
+		// Plotter
+		groupPlotter{},
+
+		// text.Handler
+		text.Plain{},
+		&text.Plain{},
+		text.Latex{},
+		&text.Latex{},
+
+		// draw.GlyphDrawer
+		draw.CircleGlyph{},
+		draw.RingGlyph{},
+		draw.SquareGlyph{},
+		draw.BoxGlyph{},
+		draw.TriangleGlyph{},
+		draw.PyramidGlyph{},
+		draw.PlusGlyph{},
+		draw.CrossGlyph{},
+
+		// color.Color
+		color.RGBA{},
+		color.RGBA64{},
+		color.NRGBA{},
+		color.NRGBA64{},
+		color.Alpha{},
+		color.Alpha16{},
+		color.Gray{},
+		color.Gray16{},
+		color.CMYK{},
+		color.YCbCr{},
+		color.NYCbCrA{},
+		&image.Uniform{},
+		&vg.LinearGradient{},
+		&vg.RadialGradient{},
+		&vg.Hatch{},
+	} {
+		Register(v)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plot_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/text"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+func init() {
+	plot.Register(plotFunc(nil))
+	plot.RegisterFunc("plot_test.drawDiagonal", drawDiagonal)
+	plot.Register(evenTicks{})
+}
+
+// drawDiagonal draws the diagonal of the data area.
+func drawDiagonal(c draw.Canvas, p *plot.Plot) {
+	c.StrokeLine2(draw.LineStyle{Color: color.Black, Width: 1}, c.Min.X, c.Min.Y, c.Max.X, c.Max.Y)
+}
+
+// evenTicks is a plot.Ticker placing the ticks at even integers.
+type evenTicks struct {
+	Prefix string
+}
+
+func (t evenTicks) Ticks(min, max float64) []plot.Tick {
+	var ticks []plot.Tick
+	for v := float64(2 * int(min/2)); v <= max; v += 2 {
+		ticks = append(ticks, plot.Tick{Value: v, Label: t.Prefix + strconv.FormatFloat(v, 'g', -1, 64)})
+	}
+	return ticks
+}
+
+func TestEncoding(t *testing.T) {
+	p := plot.New()
+	p.Title.Text = `$\sqrt{\alpha}$`
+	p.Title.TextStyle.Handler = text.Latex{Fonts: font.DefaultCache, DPI: 96}
+	p.X.Label.Text = "X"
+	p.X.Tick.Marker = evenTicks{Prefix: "x="}
+	p.X.Scale = plot.InvertedScale{Normalizer: plot.LinearScale{}}
+	p.Y.Tick.Marker = plot.TimeTicks{Ticker: plot.ConstantTicks{{Value: 1, Label: "1"}, {Value: 5}}, Format: "2006"}
+	p.Y.Scale = plot.LogScale{}
+	p.Y.Min = 1
+	p.BackgroundColor = color.NRGBA{R: 0xf0, G: 0xf0, B: 0xff, A: 0xff}
+	p.Legend.Top = true
+
+	line, err := plotter.NewLine(plotter.XYs{{X: 0, Y: 1}, {X: 4, Y: 3}, {X: 8, Y: 100}})
+	if err != nil {
+		t.Fatalf("could not create line: %v", err)
+	}
+	p.Add(line, plotFunc(drawDiagonal))
+	p.AddGroup(vg.Group{Opacity: 0.5, Blend: vg.BlendMultiply}, line)
+	p.Legend.Add("line", line)
+
+	want := renderPNG(t, p)
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("could not encode plot: %+v", err)
+	}
+
+	var got plot.Plot
+	err = json.Unmarshal(b, &got)
+	if err != nil {
+		t.Fatalf("could not decode plot: %+v", err)
+	}
+	if !bytes.Equal(renderPNG(t, &got), want) {
+		t.Errorf("decoded plot does not render as the encoded plot")
+	}
+	b2, err := json.Marshal(&got)
+	if err != nil {
+		t.Fatalf("could not encode decoded plot: %+v", err)
+	}
+	if !bytes.Equal(b, b2) {
+		t.Errorf("unexpected encoding of decoded plot:\ngot: %s\nwant:%s", b2, b)
+	}
+	if n := bytes.Count(b, []byte(`"$id"`)); n != 2 {
+		// The line added three times, and the font cache
+		// shared by the text styles, are encoded once.
+		t.Errorf("unexpected number of shared values: got:%d want:2", n)
+	}
+
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(p)
+	if err != nil {
+		t.Fatalf("could not gob-encode plot: %+v", err)
+	}
+	var gobbed plot.Plot
+	err = gob.NewDecoder(&buf).Decode(&gobbed)
+	if err != nil {
+		t.Fatalf("could not gob-decode plot: %+v", err)
+	}
+	if !bytes.Equal(renderPNG(t, &gobbed), want) {
+		t.Errorf("gob-decoded plot does not render as the encoded plot")
+	}
+}
+
+func TestEncodingNonFinite(t *testing.T) {
+	b, err := json.Marshal(plot.New())
+	if err != nil {
+		t.Fatalf("could not encode plot: %+v", err)
+	}
+	if !bytes.Contains(b, []byte(`"Min":"+Inf"`)) || !bytes.Contains(b, []byte(`"Max":"-Inf"`)) {
+		t.Errorf("unexpected encoding of axis ranges: %s", b)
+	}
+	var p plot.Plot
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		t.Fatalf("could not decode plot: %+v", err)
+	}
+	if !math.IsInf(p.X.Min, +1) || !math.IsInf(p.Y.Max, -1) {
+		t.Errorf("unexpected axis ranges: got:[%g, %g] and [%g, %g]", p.X.Min, p.X.Max, p.Y.Min, p.Y.Max)
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	type unregistered struct{ plot.DefaultTicks }
+
+	for _, test := range []struct {
+		name string
+		plot func(p *plot.Plot)
+		want string
+	}{
+		{
+			name: "type",
+			plot: func(p *plot.Plot) { p.X.Tick.Marker = unregistered{} },
+			want: "plot: type plot_test.unregistered is not registered",
+		},
+		{
+			name: "func",
+			plot: func(p *plot.Plot) {
+				p.Add(plotFunc(func(draw.Canvas, *plot.Plot) {}))
+			},
+			want: "of type plot_test.plotFunc is not registered",
+		},
+	} {
+		p := plot.New()
+		test.plot(p)
+		_, err := json.Marshal(p)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("unexpected error for unregistered %s: got:%v want:%s", test.name, err, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		doc  string
+		want string
+	}{
+		{
+			doc:  `{"Version":2,"Plot":{}}`,
+			want: "plot: unsupported format version 2",
+		},
+		{
+			doc:  `{"Version":1,"Plot":{"Plot":{"Title":{"Txt":"title"}}}}`,
+			want: "plot: unknown field Txt",
+		},
+		{
+			doc:  `{"Version":1,"Plot":{"Plotters":[{"Type":"*plotter.Unknown","Value":{}}]}}`,
+			want: `plot: type "*plotter.Unknown" is not registered`,
+		},
+		{
+			doc:  `{"Version":1,"Plot":{"Plot":{"X":{"Min":"1"}}}}`,
+			want: "plot: cannot decode string into value of type float64",
+		},
+	} {
+		var p plot.Plot
+		err := json.Unmarshal([]byte(test.doc), &p)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("unexpected error for %s: got:%v want:%s", test.doc, err, test.want)
+		}
+	}
+}
+
+// renderPNG returns the plot rendered as PNG.
+func renderPNG(t *testing.T, p *plot.Plot) []byte {
+	t.Helper()
+	w, err := p.WriterTo(10*vg.Centimeter, 10*vg.Centimeter, "png")
+	if err != nil {
+		t.Fatalf("could not create PNG writer: %+v", err)
+	}
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("could not render plot: %+v", err)
+	}
+	return buf.Bytes()
+}
@@ -5,8 +5,12 @@
 package font
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"golang.org/x/image/font"
@@ -186,21 +190,81 @@ type Cache struct {
 	faces map[Font]*opentype.Font
 }
 
-// We make Cache implement dummy GobDecoder and GobEncoder interfaces
-// to allow plot.Plot (or any other type holding a Cache) to be (de)serialized
-// with encoding/gob.
-// As Cache holds opentype.Font, the reflect-based gob (de)serialization can not
-// work: gob isn't happy with opentype.Font having no exported field:
-//
-//   error: gob: type font.Cache has no exported fields
-//
-// FIXME(sbinet): perhaps encode/decode Cache.def typeface?
+// cacheValue is the serialized form of a Cache.
+type cacheValue struct {
+	Default Typeface
+	Fonts   []Font
+}
 
-func (c *Cache) GobEncode() ([]byte, error) { return nil, nil }
-func (c *Cache) GobDecode([]byte) error {
-	if c.faces == nil {
-		c.faces = make(map[Font]*opentype.Font)
+// MarshalJSON implements the json.Marshaler interface.
+// The cache is encoded with its default typeface and the
+// descriptors of its fonts, without their font faces.
+func (c *Cache) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.value())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// setting the cache to the cache encoded by MarshalJSON.
+// The faces of the fonts are looked up in DefaultCache.
+func (c *Cache) UnmarshalJSON(b []byte) error {
+	var v cacheValue
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+	return c.setValue(v)
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+// The cache is encoded as with MarshalJSON.
+func (c *Cache) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(c.value())
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface,
+// setting the cache to the cache encoded by GobEncode.
+// The faces of the fonts are looked up in DefaultCache.
+func (c *Cache) GobDecode(b []byte) error {
+	var v cacheValue
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	if err != nil {
+		return err
 	}
+	return c.setValue(v)
+}
+
+func (c *Cache) value() cacheValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v := cacheValue{Default: c.def, Fonts: make([]Font, 0, len(c.faces))}
+	for fnt := range c.faces {
+		v.Fonts = append(v.Fonts, fnt)
+	}
+	sort.Slice(v.Fonts, func(i, j int) bool {
+		return v.Fonts[i].Name() < v.Fonts[j].Name()
+	})
+	return v
+}
+
+func (c *Cache) setValue(v cacheValue) error {
+	faces := make(map[Font]*opentype.Font, len(v.Fonts))
+	for _, fnt := range v.Fonts {
+		if !DefaultCache.Has(fnt) {
+			return fmt.Errorf("font: no face for font %s in the default cache", fnt.Name())
+		}
+		faces[fnt] = DefaultCache.Lookup(fnt, 0).Face
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.def = v.Default
+	c.faces = faces
 	return nil
 }
 
@@ -2,6 +2,11 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+// Package gob registers the types of the plot packages for the
+// serialization of plots with the encoding/gob package, and the
+// types of the palette/brewer and palette/moreland packages for
+// the serialization of plots by plot.Plot's GobEncode and
+// MarshalJSON methods.
 package gob // import "github.com/emptywe/plot/gob"
 
 import (
@@ -9,6 +14,8 @@ import (
 	"image/color"
 
 	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette/brewer"
+	"github.com/emptywe/plot/palette/moreland"
 	"github.com/emptywe/plot/plotter"
 	"github.com/emptywe/plot/text"
 )
@@ -47,4 +54,15 @@ func init() {
 	// text.Style
 	gob.Register(&text.Plain{})
 	gob.Register(&text.Latex{})
+
+	// palette.Palette and palette.ColorMap
+	plot.Register(brewer.DivergingPalette{})
+	plot.Register(brewer.NonDivergingPalette{})
+	plot.Register(brewer.Color{})
+
+	lum := moreland.Kindlmann()
+	plot.Register(lum)
+	plot.Register(lum.Palette(2))
+	plot.Register(lum.Palette(2).Colors()[0])
+	plot.Register(moreland.SmoothBlueRed())
 }
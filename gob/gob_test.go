@@ -8,7 +8,6 @@ import (
 	"bytes"
 	"encoding/gob"
 	"image/color"
-	"os"
 	"testing"
 
 	"golang.org/x/exp/rand"
@@ -21,7 +20,7 @@ import (
 )
 
 func init() {
-	gob.Register(commaTicks{})
+	plot.Register(commaTicks{})
 }
 
 func TestPersistency(t *testing.T) {
@@ -75,12 +74,7 @@ func TestPersistency(t *testing.T) {
 	p.Legend.Add("line", l)
 	p.Legend.Add("line points", lpLine, lpPoints)
 
-	// Save the plot to a PNG file.
-	err = p.Save(4, 4, "test-persistency.png")
-	if err != nil {
-		t.Fatalf("error saving to PNG: %v\n", err)
-	}
-	defer os.Remove("test-persistency.png")
+	want := render(t, p)
 
 	buf := new(bytes.Buffer)
 	enc := gob.NewEncoder(buf)
@@ -89,22 +83,30 @@ func TestPersistency(t *testing.T) {
 		t.Fatalf("error gob-encoding plot: %v\n", err)
 	}
 
-	// TODO(sbinet): impl. BinaryMarshal for plot.Plot and vg.Font
-	// {
-	// 	dec := gob.NewDecoder(buf)
-	// 	var p plot.Plot
-	// 	err = dec.Decode(&p)
-	// 	if err != nil {
-	// 		t.Fatalf("error gob-decoding plot: %v\n", err)
-	// 	}
-	// 	// Save the plot to a PNG file.
-	// 	err = p.Save(4, 4, "test-persistency-readback.png")
-	// 	if err != nil {
-	// 		t.Fatalf("error saving to PNG: %v\n", err)
-	// 	}
-	//  defer os.Remove("test-persistency-readback.png")
-	// }
+	dec := gob.NewDecoder(buf)
+	var got plot.Plot
+	err = dec.Decode(&got)
+	if err != nil {
+		t.Fatalf("error gob-decoding plot: %v\n", err)
+	}
+	if !bytes.Equal(render(t, &got), want) {
+		t.Errorf("decoded plot does not render as the encoded plot")
+	}
+}
 
+// render returns the plot rendered as PNG.
+func render(t *testing.T, p *plot.Plot) []byte {
+	t.Helper()
+	w, err := p.WriterTo(4*vg.Inch, 4*vg.Inch, "png")
+	if err != nil {
+		t.Fatalf("error creating PNG writer: %v", err)
+	}
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("error rendering plot: %v", err)
+	}
+	return buf.Bytes()
 }
 
 // randomPoints returns some random x, y points.
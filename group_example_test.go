@@ -0,0 +1,64 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plot_test
+
+import (
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// An example of drawing translucent plotters as groups, so that
+// the overlapping glyphs of a scatter plot are not darker than
+// the rest of the plotter.
+func ExamplePlot_AddGroup() {
+	ring := func(x float64) plotter.XYs {
+		pts := make(plotter.XYs, 24)
+		for i := range pts {
+			sin, cos := math.Sincos(float64(i) * 2 * math.Pi / float64(len(pts)))
+			pts[i] = plotter.XY{X: x + cos, Y: sin}
+		}
+		return pts
+	}
+
+	p := plot.New()
+	p.Title.Text = "Translucent groups"
+	p.X.Min, p.X.Max = -3, 3
+	p.Y.Min, p.Y.Max = -2, 2
+
+	plain, err := plotter.NewScatter(ring(-1.5))
+	if err != nil {
+		log.Panic(err)
+	}
+	plain.GlyphStyle = draw.GlyphStyle{
+		Color:  color.NRGBA{R: 255, A: 128},
+		Radius: vg.Points(8),
+		Shape:  draw.CircleGlyph{},
+	}
+	p.Add(plain)
+
+	group, err := plotter.NewScatter(ring(1.5))
+	if err != nil {
+		log.Panic(err)
+	}
+	group.GlyphStyle = draw.GlyphStyle{
+		Color:  color.NRGBA{R: 255, A: 255},
+		Radius: vg.Points(8),
+		Shape:  draw.CircleGlyph{},
+	}
+	p.AddGroup(vg.Group{Opacity: 0.5}, group)
+
+	for _, ext := range []string{".png", ".svg"} {
+		err = p.Save(12*vg.Centimeter, 8*vg.Centimeter, "testdata/group"+ext)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreland
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// luminanceValue is the serialized form of a luminance.
+type luminanceValue struct {
+	Colors   []cieLAB
+	Scalars  []float64
+	Alpha    float64
+	Min, Max float64
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (l *luminance) MarshalJSON() ([]byte, error) {
+	return json.Marshal(luminanceValue{
+		Colors:  l.colors,
+		Scalars: l.scalars,
+		Alpha:   l.alpha,
+		Min:     l.min,
+		Max:     l.max,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (l *luminance) UnmarshalJSON(b []byte) error {
+	var v luminanceValue
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+	*l = luminance{
+		colors:  v.Colors,
+		scalars: v.Scalars,
+		alpha:   v.Alpha,
+		min:     v.Min,
+		max:     v.Max,
+	}
+	return nil
+}
+
+// smoothDivergingValue is the serialized form of a smoothDiverging.
+// ConvergePoint is nil when the convergence point is not set.
+type smoothDivergingValue struct {
+	Start, End    msh
+	ConvergeM     float64
+	Alpha         float64
+	Min, Max      float64
+	ConvergePoint *float64
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p *smoothDiverging) MarshalJSON() ([]byte, error) {
+	v := smoothDivergingValue{
+		Start:     p.start,
+		End:       p.end,
+		ConvergeM: p.convergeM,
+		Alpha:     p.alpha,
+		Min:       p.min,
+		Max:       p.max,
+	}
+	if !math.IsNaN(p.convergePoint) {
+		v.ConvergePoint = &p.convergePoint
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *smoothDiverging) UnmarshalJSON(b []byte) error {
+	var v smoothDivergingValue
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+	*p = smoothDiverging{
+		start:         v.Start,
+		end:           v.End,
+		convergeM:     v.ConvergeM,
+		alpha:         v.Alpha,
+		min:           v.Min,
+		max:           v.Max,
+		convergePoint: math.NaN(),
+	}
+	if v.ConvergePoint != nil {
+		p.convergePoint = *v.ConvergePoint
+	}
+	return nil
+}
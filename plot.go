@@ -5,6 +5,7 @@
 package plot
 
 import (
+	"context"
 	"image/color"
 	"io"
 	"math"
@@ -48,7 +49,11 @@ type Plot struct {
 	}
 
 	// BackgroundColor is the background color of the plot.
-	// The default is White.
+	// The default is White. If BackgroundColor is nil or
+	// transparent, the background is not drawn, and images
+	// written by Save and WriterTo are transparent. Images
+	// written with a translucent BackgroundColor keep its
+	// alpha.
 	BackgroundColor color.Color
 
 	// X and Y are the horizontal and vertical axes
@@ -78,6 +83,18 @@ type Plotter interface {
 	Plot(draw.Canvas, *Plot)
 }
 
+// ContextPlotter is a Plotter whose drawing can be
+// cancelled. Plot.DrawContext draws ContextPlotters
+// with their PlotContext method.
+type ContextPlotter interface {
+	Plotter
+
+	// PlotContext draws the data to a draw.Canvas like
+	// Plot. It returns ctx.Err() without completing the
+	// drawing if the context is cancelled.
+	PlotContext(ctx context.Context, c draw.Canvas, plt *Plot) error
+}
+
 // DataRanger wraps the DataRange method.
 type DataRanger interface {
 	// DataRange returns the range of X and Y values.
@@ -135,6 +152,57 @@ func (p *Plot) Add(ps ...Plotter) {
 	p.plotters = append(p.plotters, ps...)
 }
 
+// AddGroup adds Plotters to the plot like Add, each of them
+// being drawn as a group composited with the opacity and blend
+// mode of g, on canvases that implement vg.Grouper.
+// The overlapping parts of a translucent plotter, such as the
+// crossing segments of a line, are thus not darker than the
+// rest of the plotter.
+func (p *Plot) AddGroup(g vg.Group, ps ...Plotter) {
+	p.Add(ps...)
+	for i := len(p.plotters) - len(ps); i < len(p.plotters); i++ {
+		p.plotters[i] = groupPlotter{Plotter: p.plotters[i], group: g}
+	}
+}
+
+// groupPlotter is a Plotter drawn as a group.
+type groupPlotter struct {
+	Plotter
+	group vg.Group
+}
+
+// Plot implements the Plotter interface.
+func (gp groupPlotter) Plot(c draw.Canvas, plt *Plot) {
+	_ = gp.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext implements the ContextPlotter interface.
+func (gp groupPlotter) PlotContext(ctx context.Context, c draw.Canvas, plt *Plot) error {
+	var err error
+	c.DrawGroup(gp.group, func(vc vg.Canvas) {
+		err = plotContext(ctx, gp.Plotter, draw.Canvas{Canvas: vc, Rectangle: c.Rectangle}, plt)
+	})
+	return err
+}
+
+// plotContext draws the plotter pl with its PlotContext method
+// if it is a ContextPlotter, or with its Plot method otherwise.
+func plotContext(ctx context.Context, pl Plotter, c draw.Canvas, plt *Plot) error {
+	if cp, ok := pl.(ContextPlotter); ok {
+		return cp.PlotContext(ctx, c, plt)
+	}
+	pl.Plot(c, plt)
+	return nil
+}
+
+// GlyphBoxes implements the GlyphBoxer interface.
+func (gp groupPlotter) GlyphBoxes(plt *Plot) []GlyphBox {
+	if gb, ok := gp.Plotter.(GlyphBoxer); ok {
+		return gb.GlyphBoxes(plt)
+	}
+	return nil
+}
+
 // Draw draws a plot to a draw.Canvas.
 //
 // Plotters are drawn in the order in which they were
@@ -143,6 +211,19 @@ func (p *Plot) Add(ps ...Plotter) {
 // taken into account when padding the plot so that
 // none of their glyphs are clipped.
 func (p *Plot) Draw(c draw.Canvas) {
+	_ = p.DrawContext(context.Background(), c)
+}
+
+// DrawContext draws a plot to a draw.Canvas like Draw.
+// The context is checked for cancellation between the
+// plotters, and within the plotters implementing the
+// ContextPlotter interface. If the context is cancelled,
+// DrawContext returns ctx.Err() and the drawing is left
+// incomplete.
+func (p *Plot) DrawContext(ctx context.Context, c draw.Canvas) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if p.BackgroundColor != nil {
 		c.SetColor(p.BackgroundColor)
 		c.Fill(c.Rectangle.Path())
@@ -168,12 +249,36 @@ func (p *Plot) Draw(c draw.Canvas) {
 	x.draw(padX(p, draw.Crop(c, ywidth, 0, 0, 0)))
 	y.draw(padY(p, draw.Crop(c, 0, 0, xheight, 0)))
 
-	dataC := padY(p, padX(p, draw.Crop(c, ywidth, 0, xheight, 0)))
-	for _, data := range p.plotters {
-		data.Plot(dataC, p)
+	dataArea := draw.Crop(c, ywidth, 0, xheight, 0)
+	err := p.drawPlotters(ctx, padY(p, padX(p, dataArea)), dataArea)
+	if err != nil {
+		return err
 	}
 
-	p.Legend.Draw(draw.Crop(c, ywidth, 0, xheight, 0))
+	p.Legend.Draw(dataArea)
+	return nil
+}
+
+// drawPlotters draws the plotters to the canvas c of the
+// data area da, checking for the cancellation of the context
+// before each plotter.
+func (p *Plot) drawPlotters(ctx context.Context, c, da draw.Canvas) error {
+	c.Push()
+	defer c.Pop()
+	// Clip everything drawn by the plotters, including text
+	// and images, to the data area. The padding of the data
+	// area is kept so that glyphs are not clipped.
+	c.Clip(da.Rectangle.Path())
+	for _, data := range p.plotters {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := plotContext(ctx, data, c, p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DataCanvas returns a new draw.Canvas that
@@ -491,26 +596,74 @@ func (p *Plot) NominalY(names ...string) {
 
 // WriterTo returns an io.WriterTo that will write the plot as
 // the specified image format.
+// Raster formats supporting transparency have a transparent
+// background if the background color of the plot is nil or
+// transparent.
 //
 // Supported formats are:
 //
-//  eps, jpg|jpeg, pdf, png, svg, tex and tif|tiff.
+//  ansi, eps, html, jpg|jpeg, kitty, pdf, png, sixel, svg, tex, tif|tiff and txt.
 func (p *Plot) WriterTo(w, h vg.Length, format string) (io.WriterTo, error) {
+	return p.WriterToContext(context.Background(), w, h, format)
+}
+
+// WriterToContext returns an io.WriterTo that will write the plot
+// as the specified image format, like WriterTo. The plot is drawn
+// with DrawContext, and WriterToContext returns ctx.Err() if the
+// context is cancelled before the drawing is complete.
+func (p *Plot) WriterToContext(ctx context.Context, w, h vg.Length, format string) (io.WriterTo, error) {
 	c, err := draw.NewFormattedCanvas(w, h, format)
 	if err != nil {
 		return nil, err
 	}
-	p.Draw(draw.New(c))
+	if cl, ok := c.(vg.Clearer); ok && !opaque(p.BackgroundColor) {
+		// Raster canvases are created with a white background,
+		// that translucent backgrounds would be drawn over.
+		cl.Clear(vg.Rectangle{Max: vg.Point{X: w, Y: h}}, color.Transparent)
+	}
+	err = p.DrawContext(ctx, draw.New(c))
+	if err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
+// opaque returns whether the color is not nil
+// and fully opaque.
+func opaque(c color.Color) bool {
+	if c == nil {
+		return false
+	}
+	_, _, _, a := c.RGBA()
+	return a == 0xffff
+}
+
 // Save saves the plot to an image file.  The file format is determined
 // by the extension.
 //
 // Supported extensions are:
 //
-//  .eps, .jpg, .jpeg, .pdf, .png, .svg, .tex, .tif and .tiff.
-func (p *Plot) Save(w, h vg.Length, file string) (err error) {
+//  .ansi, .eps, .html, .jpg, .jpeg, .kitty, .pdf, .png, .sixel, .svg, .tex,
+//  .tif, .tiff and .txt.
+func (p *Plot) Save(w, h vg.Length, file string) error {
+	return p.SaveContext(context.Background(), w, h, file)
+}
+
+// SaveContext saves the plot to an image file like Save.
+// The plot is drawn with DrawContext before the file is
+// created, and SaveContext returns ctx.Err() without
+// creating the file if the context is cancelled before
+// the drawing is complete.
+func (p *Plot) SaveContext(ctx context.Context, w, h vg.Length, file string) (err error) {
+	format := strings.ToLower(filepath.Ext(file))
+	if len(format) != 0 {
+		format = format[1:]
+	}
+	c, err := p.WriterToContext(ctx, w, h, format)
+	if err != nil {
+		return err
+	}
+
 	f, err := os.Create(file)
 	if err != nil {
 		return err
@@ -522,15 +675,6 @@ func (p *Plot) Save(w, h vg.Length, file string) (err error) {
 		}
 	}()
 
-	format := strings.ToLower(filepath.Ext(file))
-	if len(format) != 0 {
-		format = format[1:]
-	}
-	c, err := p.WriterTo(w, h, format)
-	if err != nil {
-		return err
-	}
-
 	_, err = c.WriteTo(f)
 	return err
 }
@@ -6,13 +6,22 @@ package plot_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"image"
 	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
 	"math"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
+	_ "golang.org/x/image/tiff"
+
 	"github.com/emptywe/plot"
 	"github.com/emptywe/plot/cmpimg"
 	"github.com/emptywe/plot/font"
@@ -129,6 +138,10 @@ func TestIssue514(t *testing.T) {
 	}
 }
 
+func TestPlotAddGroup(t *testing.T) {
+	cmpimg.CheckPlot(ExamplePlot_AddGroup, t, "group.png", "group.svg")
+}
+
 func TestDrawGlyphBoxes(t *testing.T) {
 	cmpimg.CheckPlot(func() {
 		p := plot.New()
@@ -188,3 +201,101 @@ func TestDrawGlyphBoxes(t *testing.T) {
 		}
 	}, t, "glyphbox.png")
 }
+
+func TestTransparentBackground(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format string
+		bkg    color.Color
+		want   color.Color
+	}{
+		{name: "white", format: "png", bkg: color.White, want: color.White},
+		{name: "nil", format: "png", bkg: nil, want: color.Transparent},
+		{name: "transparent", format: "tiff", bkg: color.Transparent, want: color.Transparent},
+		{name: "translucent", format: "png", bkg: color.NRGBA{R: 255, A: 0x80}, want: color.NRGBA{R: 255, A: 0x80}},
+		{name: "jpeg", format: "jpeg", bkg: nil, want: color.White},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := plot.New()
+			p.BackgroundColor = tc.bkg
+			p.HideAxes()
+
+			c, err := p.WriterTo(1*vg.Inch, 1*vg.Inch, tc.format)
+			if err != nil {
+				t.Fatalf("could not create canvas: %v", err)
+			}
+			var buf bytes.Buffer
+			if _, err := c.WriteTo(&buf); err != nil {
+				t.Fatalf("could not write canvas: %v", err)
+			}
+			img, _, err := image.Decode(&buf)
+			if err != nil {
+				t.Fatalf("could not decode image: %v", err)
+			}
+			if got, want := color.NRGBAModel.Convert(img.At(10, 10)), color.NRGBAModel.Convert(tc.want); got != want {
+				t.Errorf("invalid background color: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestDrawContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var drawn []int
+	newPlotter := func(i int) plot.Plotter {
+		return plotFunc(func(c draw.Canvas, p *plot.Plot) {
+			drawn = append(drawn, i)
+			if i == 1 {
+				cancel()
+			}
+		})
+	}
+	p := plot.New()
+	p.Add(newPlotter(0), newPlotter(1))
+	p.AddGroup(vg.Group{Opacity: 1}, newPlotter(2))
+
+	c := vgimg.New(100, 100)
+	err := p.DrawContext(ctx, draw.New(c))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.Canceled)
+	}
+	if want := []int{0, 1}; !reflect.DeepEqual(drawn, want) {
+		t.Errorf("unexpected plotters drawn: got:%v want:%v", drawn, want)
+	}
+
+	// Errors of context plotters drawn in groups
+	// are returned.
+	p = plot.New()
+	p.AddGroup(vg.Group{Opacity: 0.5}, cancelledPlotter{})
+	err = p.DrawContext(context.Background(), draw.New(c))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.DeadlineExceeded)
+	}
+
+	name := filepath.Join(t.TempDir(), "plot.png")
+	err = p.SaveContext(ctx, 10*vg.Centimeter, 10*vg.Centimeter, name)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.Canceled)
+	}
+	_, err = os.Stat(name)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unexpected file for cancelled plot: %v", err)
+	}
+}
+
+// plotFunc is a plot.Plotter drawing with a function.
+type plotFunc func(draw.Canvas, *plot.Plot)
+
+func (f plotFunc) Plot(c draw.Canvas, p *plot.Plot) { f(c, p) }
+
+// cancelledPlotter is a plot.ContextPlotter always
+// failing with context.DeadlineExceeded.
+type cancelledPlotter struct{}
+
+func (cancelledPlotter) Plot(draw.Canvas, *plot.Plot) {}
+
+func (cancelledPlotter) PlotContext(context.Context, draw.Canvas, *plot.Plot) error {
+	return context.DeadlineExceeded
+}
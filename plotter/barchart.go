@@ -23,6 +23,8 @@ type BarChart struct {
 	Width vg.Length
 
 	// Color is the fill color of the bars.
+	// It may be a vg.Gradient, relative to each bar,
+	// or a vg.Hatch pattern.
 	Color color.Color
 
 	// LineStyle is the style of the outline of the bars.
@@ -57,7 +59,7 @@ func NewBarChart(vs Valuer, width vg.Length) (*BarChart, error) {
 	if width <= 0 {
 		return nil, errors.New("plotter: width parameter was not positive")
 	}
-	values, err := CopyValues(vs)
+	values, err := copyMissingValues(vs)
 	if err != nil {
 		return nil, err
 	}
@@ -71,13 +73,14 @@ func NewBarChart(vs Valuer, width vg.Length) (*BarChart, error) {
 
 // BarHeight returns the maximum y value of the
 // ith bar, taking into account any bars upon
-// which it is stacked.
+// which it is stacked. Missing values have no
+// height.
 func (b *BarChart) BarHeight(i int) float64 {
 	ht := 0.0
 	if b == nil {
 		return 0
 	}
-	if i >= 0 && i < len(b.Values) {
+	if i >= 0 && i < len(b.Values) && !math.IsNaN(b.Values[i]) {
 		ht += b.Values[i]
 	}
 	if b.stackedOn != nil {
@@ -96,6 +99,7 @@ func (b *BarChart) StackOn(on *BarChart) {
 }
 
 // Plot implements the plot.Plotter interface.
+// The bars of missing values are skipped.
 func (b *BarChart) Plot(c draw.Canvas, plt *plot.Plot) {
 	trCat, trVal := plt.Transforms(&c)
 	if b.Horizontal {
@@ -103,6 +107,9 @@ func (b *BarChart) Plot(c draw.Canvas, plt *plot.Plot) {
 	}
 
 	for i, ht := range b.Values {
+		if math.IsNaN(ht) {
+			continue
+		}
 		catVal := b.XMin + float64(i)
 		catMin := trCat(float64(catVal))
 		if !b.Horizontal {
@@ -161,6 +168,9 @@ func (b *BarChart) DataRange() (xmin, xmax, ymin, ymax float64) {
 	valMin := math.Inf(1)
 	valMax := math.Inf(-1)
 	for i, val := range b.Values {
+		if math.IsNaN(val) {
+			continue
+		}
 		valBot := b.stackedOn.BarHeight(i)
 		valTop := valBot + val
 		valMin = math.Min(valMin, math.Min(valBot, valTop))
@@ -247,3 +247,83 @@ func ExampleBarChart_positiveNegative() {
 		log.Panic(err)
 	}
 }
+
+// This example shows a bar chart filled with a color gradient.
+func ExampleBarChart_gradient() {
+	values := plotter.Values{5, 10, 20, 15}
+
+	p := plot.New()
+	p.Title.Text = "Gradient bar chart"
+
+	bars, err := plotter.NewBarChart(values, 0.75*vg.Centimeter)
+	if err != nil {
+		log.Panic(err)
+	}
+	bars.LineStyle.Width = 0
+
+	// The gradient goes from the bottom to the top of each bar.
+	bars.Color = &vg.LinearGradient{
+		X1: 0, Y1: 0, X2: 0, Y2: 1,
+		Stops: []vg.GradientStop{
+			{Offset: 0, Color: color.NRGBA{R: 255, G: 215, A: 255}},
+			{Offset: 0.5, Color: color.NRGBA{R: 230, G: 80, A: 255}},
+			{Offset: 1, Color: color.NRGBA{R: 120, B: 90, A: 255}},
+		},
+	}
+	p.Add(bars)
+	p.NominalX("A", "B", "C", "D")
+
+	for _, name := range []string{
+		"testdata/barChart_gradient.png",
+		"testdata/barChart_gradient.svg",
+	} {
+		err = p.Save(200, 200, name)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+}
+
+// This example shows a grouped bar chart where the groups are
+// distinguished by hatch patterns rather than by colors.
+func ExampleBarChart_hatch() {
+	groupA := plotter.Values{20, 35, 30, 35}
+	groupB := plotter.Values{25, 32, 34, 20}
+	groupC := plotter.Values{12, 28, 15, 21}
+
+	p := plot.New()
+	p.Title.Text = "Hatched bar chart"
+	p.Y.Label.Text = "Heights"
+
+	w := vg.Points(15)
+	for i, g := range []struct {
+		name   string
+		values plotter.Values
+		hatch  *vg.Hatch
+	}{
+		{"Group A", groupA, &vg.Hatch{Style: vg.HatchDiagonal}},
+		{"Group B", groupB, &vg.Hatch{Style: vg.HatchCross, Spacing: 3}},
+		{"Group C", groupC, &vg.Hatch{Style: vg.HatchDots, Width: 1.5, Background: color.Gray{Y: 220}}},
+	} {
+		bars, err := plotter.NewBarChart(g.values, w)
+		if err != nil {
+			log.Panic(err)
+		}
+		bars.Color = g.hatch
+		bars.Offset = vg.Length(i-1) * w
+		p.Add(bars)
+		p.Legend.Add(g.name, bars)
+	}
+	p.Legend.Top = true
+	p.NominalX("One", "Two", "Three", "Four")
+
+	for _, name := range []string{
+		"testdata/barChart_hatch.png",
+		"testdata/barChart_hatch.svg",
+	} {
+		err := p.Save(250, 250, name)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+}
@@ -19,3 +19,11 @@ func TestBarChart(t *testing.T) {
 func TestBarChart_positiveNegative(t *testing.T) {
 	cmpimg.CheckPlot(ExampleBarChart_positiveNegative, t, "barChart_positiveNegative.png")
 }
+
+func TestBarChart_gradient(t *testing.T) {
+	cmpimg.CheckPlot(ExampleBarChart_gradient, t, "barChart_gradient.png", "barChart_gradient.svg")
+}
+
+func TestBarChart_hatch(t *testing.T) {
+	cmpimg.CheckPlot(ExampleBarChart_hatch, t, "barChart_hatch.png", "barChart_hatch.svg")
+}
@@ -5,6 +5,7 @@
 package plotter
 
 import (
+	"context"
 	"math"
 )
 
@@ -28,11 +29,12 @@ type conrecLine func(i, j int, l line, height float64)
 // http://paulbourke.net/papers/conrec/conrec.c
 //
 // conrec takes g, an m×n grid function, a sorted slice of contour heights
-// and a conrecLine function.
+// and a conrecLine function. It returns ctx.Err() if the context is
+// cancelled before the grid is traversed, checking it for each column.
 //
 // For full details of the algorithm, see the paper at
 // http://paulbourke.net/papers/conrec/
-func conrec(g GridXYZ, heights []float64, fn conrecLine) {
+func conrec(ctx context.Context, g GridXYZ, heights []float64, fn conrecLine) error {
 	var (
 		p1, p2 point
 
@@ -77,6 +79,9 @@ func conrec(g GridXYZ, heights []float64, fn conrecLine) {
 
 	c, r := g.Dims()
 	for i := 0; i < c-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		for j := 0; j < r-1; j++ {
 			dmin := math.Min(
 				math.Min(g.Z(i, j), g.Z(i, j+1)),
@@ -200,4 +205,5 @@ func conrec(g GridXYZ, heights []float64, fn conrecLine) {
 			}
 		}
 	}
+	return nil
 }
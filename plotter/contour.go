@@ -5,6 +5,7 @@
 package plotter
 
 import (
+	"context"
 	"image/color"
 	"math"
 	"sort"
@@ -124,13 +125,20 @@ const naive = false
 
 // Plot implements the Plot method of the plot.Plotter interface.
 func (h *Contour) Plot(c draw.Canvas, plt *plot.Plot) {
+	_ = h.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext implements the PlotContext method of the
+// plot.ContextPlotter interface. The context is checked
+// for cancellation while the contour paths are computed
+// and drawn.
+func (h *Contour) PlotContext(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
 	if h.Min > h.Max {
 		panic("contour: invalid Z range: min greater than max")
 	}
 
 	if naive {
-		h.naivePlot(c, plt)
-		return
+		return h.naivePlot(ctx, c, plt)
 	}
 
 	var pal []color.Color
@@ -145,7 +153,10 @@ func (h *Contour) Plot(c draw.Canvas, plt *plot.Plot) {
 	// The alternative naive approach is to draw each line segment as
 	// conrec returns it. The integrated path approach allows graphical
 	// optimisations and is necessary for contour fill shading.
-	cp := contourPaths(h.GridXYZ, h.Levels, trX, trY)
+	cp, err := contourPaths(ctx, h.GridXYZ, h.Levels, trX, trY)
+	if err != nil {
+		return err
+	}
 
 	// ps is a palette scaling factor to scale the palette uniformly
 	// across the given levels. This enables a discordance between the
@@ -156,11 +167,18 @@ func (h *Contour) Plot(c draw.Canvas, plt *plot.Plot) {
 		ps = 0
 	}
 
+	var n int
 	for i, z := range h.Levels {
 		if math.IsNaN(z) {
 			continue
 		}
 		for _, pa := range cp[z] {
+			if n%cancelCheck == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			n++
 			if isLoop(pa) {
 				pa.Close()
 			}
@@ -184,12 +202,13 @@ func (h *Contour) Plot(c draw.Canvas, plt *plot.Plot) {
 			}
 		}
 	}
+	return nil
 }
 
 // naivePlot implements a naive rendering approach for contours.
 // It is here as a debugging mode since it simply draws line segments
 // generated by conrec without further computation.
-func (h *Contour) naivePlot(c draw.Canvas, plt *plot.Plot) {
+func (h *Contour) naivePlot(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
 	var pal []color.Color
 	if h.Palette != nil {
 		pal = h.Palette.Colors()
@@ -215,7 +234,7 @@ func (h *Contour) naivePlot(c draw.Canvas, plt *plot.Plot) {
 
 	// Draw each line segment as conrec generates it.
 	var pa vg.Path
-	conrec(h.GridXYZ, h.Levels, func(_, _ int, l line, z float64) {
+	return conrec(ctx, h.GridXYZ, h.Levels, func(_, _ int, l line, z float64) {
 		if math.IsNaN(z) {
 			return
 		}
@@ -293,15 +312,19 @@ func isLoop(p vg.Path) bool {
 // on the input data in m cut at the given levels. The trX and trY function
 // are coordinate transforms. The returned map contains slices of paths keyed
 // on the value of the contour level. contouPaths sorts levels ascending as a
-// side effect.
-func contourPaths(m GridXYZ, levels []float64, trX, trY func(float64) vg.Length) map[float64][]vg.Path {
+// side effect. It returns ctx.Err() if the context is cancelled before the
+// paths are built.
+func contourPaths(ctx context.Context, m GridXYZ, levels []float64, trX, trY func(float64) vg.Length) (map[float64][]vg.Path, error) {
 	sort.Float64s(levels)
 
 	ends := make(map[float64]endMap)
 	conts := make(contourSet)
-	conrec(m, levels, func(_, _ int, l line, z float64) {
+	err := conrec(ctx, m, levels, func(_, _ int, l line, z float64) {
 		paths(l, z, ends, conts)
 	})
+	if err != nil {
+		return nil, err
+	}
 	ends = nil
 
 	// TODO(kortschak): Check that all non-loop paths have
@@ -317,6 +340,9 @@ func contourPaths(m GridXYZ, levels []float64, trX, trY func(float64) vg.Length)
 
 	// Excise loops from crossed paths.
 	for c := range conts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// Always try to do quick excision in production if possible.
 		c.exciseLoops(conts, true)
 	}
@@ -327,7 +353,7 @@ func contourPaths(m GridXYZ, levels []float64, trX, trY func(float64) vg.Length)
 		paths[c.z] = append(paths[c.z], c.path(trX, trY))
 	}
 
-	return paths
+	return paths, nil
 }
 
 // contourSet hold a working collection of contours.
@@ -5,6 +5,7 @@
 package plotter
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math"
@@ -138,7 +139,7 @@ func complexContourBench(noise float64, b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		p = contourPaths(m, levels, unity, unity)
+		p, _ = contourPaths(context.Background(), m, levels, unity, unity)
 	}
 
 	cp = p
@@ -158,7 +159,10 @@ func TestContourPaths(t *testing.T) {
 		gotClosed  int
 	)
 
-	got := contourPaths(m, levels, unity, unity)
+	got, err := contourPaths(context.Background(), m, levels, unity, unity)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	for l, p := range got {
 		sort.Sort(byLength(p))
 		for i, c := range p {
@@ -452,6 +456,22 @@ func (c testContour) String() string {
 	return s
 }
 
+func TestContourPathsContext(t *testing.T) {
+	m := unitGrid{mat.NewDense(3, 4, []float64{
+		2, 1, 4, 3,
+		6, 7, 2, 5,
+		9, 10, 11, 12,
+	})}
+	levels := []float64{1.5, 2.5, 3.5}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := contourPaths(ctx, m, levels, unity, unity)
+	if err != context.Canceled {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.Canceled)
+	}
+}
+
 func TestExciseLoops(t *testing.T) {
 	for _, quick := range []bool{true, false} {
 		for i, test := range loopTests {
@@ -0,0 +1,370 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// Aggregation specifies the aggregation of the points
+// of a Density falling in the same pixel.
+type Aggregation int
+
+const (
+	// AggregateCount counts the points.
+	AggregateCount Aggregation = iota
+
+	// AggregateSum sums the values of the points.
+	AggregateSum
+
+	// AggregateMean averages the values of the points.
+	AggregateMean
+)
+
+// Normalization specifies the mapping of the aggregates
+// of a Density to the range of its ColorMap.
+type Normalization int
+
+const (
+	// NormLinear maps the aggregates linearly.
+	NormLinear Normalization = iota
+
+	// NormLog maps the logarithms of the aggregates linearly.
+	// The non-positive aggregates are mapped to the minimum
+	// of the ColorMap.
+	NormLog
+
+	// NormEqHist maps the aggregates by their cumulative
+	// distribution over the pixels, equalizing the histogram
+	// of the colors of the pixels.
+	NormEqHist
+)
+
+// Density implements the Plotter interface, drawing the density
+// of a large set of points as a single image. The points are
+// aggregated in a grid with a cell per pixel of the data canvas,
+// or per point on the canvases without a resolution, and the
+// aggregates are colored with a ColorMap.
+type Density struct {
+	// XYer holds the points of the density. The points are
+	// not copied, and are read concurrently when plotting.
+	// The points holding a NaN or infinite value are skipped.
+	XYer XYer
+
+	// Values holds the values of the points aggregated by
+	// AggregateSum and AggregateMean. Values must have the
+	// same length as XYer, and may be nil when the points
+	// are counted.
+	Values Valuer
+
+	// Aggregation is the aggregation of the points
+	// in each pixel.
+	Aggregation Aggregation
+
+	// ColorMap is the color map of the aggregates,
+	// whose whole range is used.
+	ColorMap palette.ColorMap
+
+	// Normalization is the mapping of the aggregates
+	// to the ColorMap.
+	Normalization Normalization
+
+	// Background is the color of the pixels without
+	// points. The pixels are transparent if Background
+	// is nil.
+	Background color.Color
+
+	// Workers is the number of concurrent workers
+	// aggregating the points. If Workers is not
+	// positive, runtime.GOMAXPROCS(0) workers are used.
+	Workers int
+
+	xmin, xmax, ymin, ymax float64
+}
+
+// NewDensity returns a Density of the points of xys, counted
+// and colored with cm, or an error if xys has no finite points.
+func NewDensity(xys XYer, cm palette.ColorMap) (*Density, error) {
+	d := &Density{
+		XYer:     xys,
+		ColorMap: cm,
+	}
+	d.xmin, d.ymin = math.Inf(1), math.Inf(1)
+	d.xmax, d.ymax = math.Inf(-1), math.Inf(-1)
+	for i := 0; i < xys.Len(); i++ {
+		x, y := xys.XY(i)
+		if !finite(x) || !finite(y) {
+			continue
+		}
+		d.xmin = math.Min(d.xmin, x)
+		d.xmax = math.Max(d.xmax, x)
+		d.ymin = math.Min(d.ymin, y)
+		d.ymax = math.Max(d.ymax, y)
+	}
+	if d.xmin > d.xmax {
+		return nil, ErrNoData
+	}
+	return d, nil
+}
+
+// finite returns whether v is neither NaN nor infinite.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (d *Density) Plot(c draw.Canvas, plt *plot.Plot) {
+	_ = d.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext implements the PlotContext method of the
+// plot.ContextPlotter interface. The workers check the
+// context for cancellation while aggregating the points.
+func (d *Density) PlotContext(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
+	if d.ColorMap == nil {
+		panic("plotter: nil ColorMap in Density")
+	}
+	if d.ColorMap.Max() == d.ColorMap.Min() {
+		panic("plotter: ColorMap Max==Min")
+	}
+	if d.Aggregation != AggregateCount {
+		switch {
+		case d.Values == nil:
+			panic("plotter: nil Values in Density")
+		case d.Values.Len() != d.XYer.Len():
+			panic(fmt.Sprintf("plotter: Density Values length %d does not match XYer length %d", d.Values.Len(), d.XYer.Len()))
+		}
+	}
+
+	w := columnWidth(c)
+	cols := int(math.Ceil(float64((c.Max.X - c.Min.X) / w)))
+	rows := int(math.Ceil(float64((c.Max.Y - c.Min.Y) / w)))
+	if cols <= 0 || rows <= 0 {
+		return nil
+	}
+	g, err := d.aggregate(ctx, c, plt, w, cols, rows)
+	if err != nil {
+		return err
+	}
+	img := d.image(g)
+	c.DrawImage(vg.Rectangle{
+		Min: vg.Point{X: c.Min.X, Y: c.Max.Y - vg.Length(rows)*w},
+		Max: vg.Point{X: c.Min.X + vg.Length(cols)*w, Y: c.Max.Y},
+	}, img)
+	return nil
+}
+
+// densityGrid holds the aggregation of the points
+// of a Density in the pixels of the data canvas.
+type densityGrid struct {
+	cols, rows int
+	count      []float64
+	sum        []float64
+}
+
+func newDensityGrid(cols, rows int, sums bool) *densityGrid {
+	g := &densityGrid{cols: cols, rows: rows, count: make([]float64, cols*rows)}
+	if sums {
+		g.sum = make([]float64, cols*rows)
+	}
+	return g
+}
+
+// add adds the counts and sums of h to g.
+func (g *densityGrid) add(h *densityGrid) {
+	for i, n := range h.count {
+		g.count[i] += n
+	}
+	for i, s := range h.sum {
+		g.sum[i] += s
+	}
+}
+
+// aggregate returns the aggregation of the points of d in the
+// cols×rows pixels of width w, starting at the top left of c.
+// The points are aggregated concurrently in chunks, in grids
+// summed once all the chunks are aggregated.
+func (d *Density) aggregate(ctx context.Context, c draw.Canvas, plt *plot.Plot, w vg.Length, cols, rows int) (*densityGrid, error) {
+	trX, trY := plt.Transforms(&c)
+	sums := d.Aggregation != AggregateCount
+
+	n := d.XYer.Len()
+	workers := d.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunk := (n + workers - 1) / workers
+	if chunk == 0 {
+		return newDensityGrid(cols, rows, sums), nil
+	}
+
+	var (
+		wg    sync.WaitGroup
+		grids = make([]*densityGrid, workers)
+		errs  = make([]error, workers)
+	)
+	for i := 0; i < workers && i*chunk < n; i++ {
+		lo, hi := i*chunk, (i+1)*chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(i, lo, hi int) {
+			defer wg.Done()
+			g := newDensityGrid(cols, rows, sums)
+			grids[i] = g
+			for j := lo; j < hi; j++ {
+				if (j-lo)%cancelCheck == 0 {
+					if err := ctx.Err(); err != nil {
+						errs[i] = err
+						return
+					}
+				}
+				x, y := d.XYer.XY(j)
+				if !finite(x) || !finite(y) {
+					continue
+				}
+				col := math.Floor(float64((trX(x) - c.Min.X) / w))
+				row := math.Floor(float64((c.Max.Y - trY(y)) / w))
+				if col < 0 || col >= float64(cols) || row < 0 || row >= float64(rows) {
+					continue
+				}
+				k := int(row)*cols + int(col)
+				if sums {
+					v := d.Values.Value(j)
+					if !finite(v) {
+						continue
+					}
+					g.sum[k] += v
+				}
+				g.count[k]++
+			}
+		}(i, lo, hi)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	g := grids[0]
+	for _, h := range grids[1:] {
+		if h != nil {
+			g.add(h)
+		}
+	}
+	return g, nil
+}
+
+// image returns the image of the aggregates of the grid g.
+func (d *Density) image(g *densityGrid) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, g.cols, g.rows))
+	if d.Background != nil {
+		bg := color.NRGBAModel.Convert(d.Background).(color.NRGBA)
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+		}
+	}
+
+	vals := make([]float64, len(g.count))
+	var filled []float64
+	for k, n := range g.count {
+		if n == 0 {
+			continue
+		}
+		switch d.Aggregation {
+		case AggregateCount:
+			vals[k] = n
+		case AggregateSum:
+			vals[k] = g.sum[k]
+		case AggregateMean:
+			vals[k] = g.sum[k] / n
+		}
+		filled = append(filled, vals[k])
+	}
+	if len(filled) == 0 {
+		return img
+	}
+
+	norm := d.normalizer(filled)
+	cmMin, cmMax := d.ColorMap.Min(), d.ColorMap.Max()
+	for k, n := range g.count {
+		if n == 0 {
+			continue
+		}
+		t := norm(vals[k])
+		col, err := d.ColorMap.At(cmMin + t*(cmMax-cmMin))
+		if err != nil {
+			continue
+		}
+		img.Set(k%g.cols, k/g.cols, col)
+	}
+	return img
+}
+
+// normalizer returns the function normalizing the
+// aggregates of the non-empty pixels vals to [0, 1].
+// normalizer sorts vals.
+func (d *Density) normalizer(vals []float64) func(float64) float64 {
+	sort.Float64s(vals)
+	min, max := vals[0], vals[len(vals)-1]
+	clamp := func(t float64) float64 {
+		return math.Max(0, math.Min(1, t))
+	}
+
+	switch d.Normalization {
+	case NormLog:
+		i := sort.Search(len(vals), func(i int) bool { return vals[i] > 0 })
+		if i == len(vals) {
+			return func(float64) float64 { return 0 }
+		}
+		lmin, lmax := math.Log(vals[i]), math.Log(max)
+		return func(v float64) float64 {
+			if v <= 0 || lmax == lmin {
+				return 0
+			}
+			return clamp((math.Log(v) - lmin) / (lmax - lmin))
+		}
+	case NormEqHist:
+		// The cumulative distribution of the aggregates,
+		// from zero at the minimum to one at the maximum.
+		le := func(v float64) int {
+			return sort.Search(len(vals), func(i int) bool { return vals[i] > v })
+		}
+		base := le(min)
+		if base == len(vals) {
+			return func(float64) float64 { return 1 }
+		}
+		return func(v float64) float64 {
+			return float64(le(v)-base) / float64(len(vals)-base)
+		}
+	default:
+		if max == min {
+			return func(float64) float64 { return 1 }
+		}
+		return func(v float64) float64 {
+			return clamp((v - min) / (max - min))
+		}
+	}
+}
+
+// DataRange implements the DataRange method
+// of the plot.DataRanger interface.
+func (d *Density) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return d.xmin, d.xmax, d.ymin, d.ymax
+}
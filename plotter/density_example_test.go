@@ -0,0 +1,56 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"log"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette/moreland"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+)
+
+// ExampleDensity draws the density of a million points,
+// too many to be drawn as glyphs.
+func ExampleDensity() {
+	rnd := rand.New(rand.NewSource(1))
+
+	// Three clusters of points of different spreads.
+	const n = 1000000
+	pts := make(plotter.XYs, n)
+	for i := range pts {
+		switch i % 3 {
+		case 0:
+			pts[i] = plotter.XY{X: rnd.NormFloat64(), Y: rnd.NormFloat64()}
+		case 1:
+			pts[i] = plotter.XY{X: 3 + 0.5*rnd.NormFloat64(), Y: 2 + 0.25*rnd.NormFloat64()}
+		case 2:
+			x := 6 * rnd.Float64()
+			pts[i] = plotter.XY{X: x - 2, Y: x - 3 + 0.1*rnd.NormFloat64()}
+		}
+	}
+
+	cm := moreland.Kindlmann()
+	cm.SetMax(1)
+	d, err := plotter.NewDensity(pts, cm)
+	if err != nil {
+		log.Panic(err)
+	}
+	d.Normalization = plotter.NormEqHist
+
+	p := plot.New()
+	p.Title.Text = "Density"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+	p.Add(d)
+
+	err = p.Save(10*vg.Centimeter, 10*vg.Centimeter, "testdata/density.png")
+	if err != nil {
+		log.Panic(err)
+	}
+}
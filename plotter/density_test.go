@@ -0,0 +1,126 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/palette/moreland"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/recorder"
+)
+
+func TestDensity(t *testing.T) {
+	cmpimg.CheckPlot(ExampleDensity, t, "density.png")
+}
+
+func TestDensityAggregation(t *testing.T) {
+	// Three points in the bottom left pixel, with values
+	// 1, 2 and 3, and one point in the center, with value 6.
+	pts := plotter.XYs{{X: 0.5, Y: 0.5}, {X: 5.5, Y: 5.5}, {X: 0.2, Y: 0.7}, {X: 0.9, Y: 0.1}}
+	vals := plotter.Values{1, 6, 2, 3}
+
+	cm := moreland.BlackBody()
+	cm.SetMax(1)
+	lo, err := cm.At(cm.Min())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hi, err := cm.At(cm.Max())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		agg           plotter.Aggregation
+		corner, mid   color.Color
+		normalization plotter.Normalization
+	}{
+		{agg: plotter.AggregateCount, corner: hi, mid: lo},
+		{agg: plotter.AggregateCount, normalization: plotter.NormLog, corner: hi, mid: lo},
+		{agg: plotter.AggregateCount, normalization: plotter.NormEqHist, corner: hi, mid: lo},
+		{agg: plotter.AggregateMean, corner: lo, mid: hi},
+		{agg: plotter.AggregateSum, corner: hi, mid: hi},
+	} {
+		d, err := plotter.NewDensity(pts, cm)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d.Values = vals
+		d.Aggregation = test.agg
+		d.Normalization = test.normalization
+		d.Workers = 3
+
+		p := plot.New()
+		p.X.Min, p.X.Max = 0, 10
+		p.Y.Min, p.Y.Max = 0, 10
+		var c recorder.Canvas
+		d.Plot(draw.NewCanvas(&c, 10, 10), p)
+
+		if len(c.Actions) != 1 {
+			t.Fatalf("unexpected number of actions: got:%d want:1", len(c.Actions))
+		}
+		img := c.Actions[0].(*recorder.DrawImage).Image
+		if b := img.Bounds(); b != image.Rect(0, 0, 10, 10) {
+			t.Fatalf("unexpected image bounds: got:%v want:%v", b, image.Rect(0, 0, 10, 10))
+		}
+		for _, px := range []struct {
+			x, y int
+			want color.Color
+		}{
+			{x: 0, y: 9, want: test.corner},
+			{x: 5, y: 4, want: test.mid},
+			{x: 9, y: 0, want: color.Transparent},
+		} {
+			got := color.NRGBAModel.Convert(img.At(px.x, px.y))
+			want := color.NRGBAModel.Convert(px.want)
+			if got != want {
+				t.Errorf("unexpected color of pixel (%d, %d) for aggregation %d and normalization %d: got:%v want:%v",
+					px.x, px.y, test.agg, test.normalization, got, want)
+			}
+		}
+	}
+}
+
+func TestDensityPlotContext(t *testing.T) {
+	cm := moreland.BlackBody()
+	cm.SetMax(1)
+	d, err := plotter.NewDensity(plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 1}}, cm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var c recorder.Canvas
+	err = d.PlotContext(ctx, draw.NewCanvas(&c, 10, 10), plot.New())
+	if err != context.Canceled {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.Canceled)
+	}
+	if len(c.Actions) != 0 {
+		t.Errorf("unexpected actions for canceled context: %d", len(c.Actions))
+	}
+
+	_, err = plotter.NewDensity(plotter.XYs{}, cm)
+	if err != plotter.ErrNoData {
+		t.Errorf("unexpected error for empty data: got:%v want:%v", err, plotter.ErrNoData)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected panic for mismatched Values length")
+			}
+		}()
+		d.Values = plotter.Values{1}
+		d.Aggregation = plotter.AggregateSum
+		_ = d.PlotContext(context.Background(), draw.NewCanvas(&c, 10, 10), plot.New())
+	}()
+}
@@ -0,0 +1,175 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"math"
+
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// DownsampleKind specifies the downsampling of the points of a
+// line to the columns of the data canvas it is drawn on, which are
+// a pixel wide on raster canvases and a point wide otherwise.
+type DownsampleKind int
+
+const (
+	// NoDownsample draws all the points of the line.
+	NoDownsample DownsampleKind = iota
+
+	// MinMax keeps, in each column, the first and last points
+	// of the line and its points with the minimum and maximum
+	// y values, preserving the peaks of the line.
+	MinMax
+
+	// LTTB keeps one point per column of the points in the
+	// data canvas, selected with the largest-triangle-three-buckets
+	// algorithm, which preserves the visual shape of the line.
+	// LTTB requires the points to be sorted by x.
+	LTTB
+)
+
+// downsample returns the points ps of a line drawn on c,
+// downsampled as specified by kind.
+func downsample(kind DownsampleKind, c draw.Canvas, ps []vg.Point) []vg.Point {
+	w := columnWidth(c)
+	n := int(math.Ceil(float64((c.Max.X - c.Min.X) / w)))
+	switch kind {
+	case MinMax:
+		return minMax(ps, c.Min.X, w, n)
+	case LTTB:
+		return lttb(visible(ps, c.Min.X, c.Max.X), n)
+	default:
+		return ps
+	}
+}
+
+// columnWidth returns the width of the columns of c:
+// a pixel of the canvases with a resolution, and a
+// point otherwise.
+func columnWidth(c draw.Canvas) vg.Length {
+	if r, ok := c.Canvas.(interface{ DPI() float64 }); ok && r.DPI() > 0 {
+		return vg.Inch / vg.Length(r.DPI())
+	}
+	return 1
+}
+
+// minMax returns the first and last points, and the points with
+// the minimum and maximum y values, of the runs of consecutive
+// points of ps in the same of the n columns of width w starting
+// at min. The points left and right of the columns are grouped
+// in two more columns.
+func minMax(ps []vg.Point, min, w vg.Length, n int) []vg.Point {
+	column := func(x vg.Length) int {
+		i := math.Floor(float64((x - min) / w))
+		switch {
+		case i < 0:
+			return -1
+		case i >= float64(n):
+			return n
+		}
+		return int(i)
+	}
+
+	var out []vg.Point
+	for start := 0; start < len(ps); {
+		col := column(ps[start].X)
+		end := start + 1
+		for end < len(ps) && column(ps[end].X) == col {
+			end++
+		}
+		run := ps[start:end]
+		lo, hi := 0, 0
+		for i, p := range run {
+			if p.Y < run[lo].Y {
+				lo = i
+			}
+			if p.Y > run[hi].Y {
+				hi = i
+			}
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		prev := -1
+		for _, i := range [...]int{0, lo, hi, len(run) - 1} {
+			if i != prev {
+				out = append(out, run[i])
+				prev = i
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+// visible returns the points of ps with x in [min, max],
+// with their neighbours outside of this range. visible
+// returns ps if none of its points is in the range.
+func visible(ps []vg.Point, min, max vg.Length) []vg.Point {
+	first, last := -1, -1
+	for i, p := range ps {
+		if min <= p.X && p.X <= max {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return ps
+	}
+	if first > 0 {
+		first--
+	}
+	if last < len(ps)-1 {
+		last++
+	}
+	return ps[first : last+1]
+}
+
+// lttb returns n points of ps, selected with the
+// largest-triangle-three-buckets algorithm.
+// lttb returns ps if it has no more than n points.
+func lttb(ps []vg.Point, n int) []vg.Point {
+	if n < 3 {
+		n = 3
+	}
+	if len(ps) <= n {
+		return ps
+	}
+
+	out := make([]vg.Point, 0, n)
+	out = append(out, ps[0])
+	size := float64(len(ps)-2) / float64(n-2)
+	a := 0
+	for i := 0; i < n-2; i++ {
+		// The average of the points of the next bucket.
+		start := int(float64(i+1)*size) + 1
+		end := int(float64(i+2)*size) + 1
+		if end > len(ps) {
+			end = len(ps)
+		}
+		var avg vg.Point
+		for _, p := range ps[start:end] {
+			avg = avg.Add(p)
+		}
+		avg = avg.Scale(1 / vg.Length(end-start))
+
+		// The point of this bucket forming the largest
+		// triangle with the previous point and the average.
+		best, area := -1, -1.0
+		for j := int(float64(i)*size) + 1; j < start; j++ {
+			ar := math.Abs(float64((ps[a].X-avg.X)*(ps[j].Y-ps[a].Y) - (ps[a].X-ps[j].X)*(avg.Y-ps[a].Y)))
+			if ar > area {
+				best, area = j, ar
+			}
+		}
+		out = append(out, ps[best])
+		a = best
+	}
+	return append(out, ps[len(ps)-1])
+}
@@ -0,0 +1,70 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"image/color"
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+)
+
+// ExampleLine_downsample draws a long time series downsampled
+// to the columns of the data canvas, keeping the peaks with
+// MinMax and the shape of the series with LTTB.
+func ExampleLine_downsample() {
+	rnd := rand.New(rand.NewSource(1))
+
+	const n = 200000
+	series := make(plotter.XYs, n)
+	for i := range series {
+		x := float64(i) / n
+		series[i].X = x
+		series[i].Y = math.Sin(4*math.Pi*x) + 0.2*rnd.NormFloat64()
+		if i%50000 == 25000 {
+			// Spikes, kept by MinMax.
+			series[i].Y += 3
+		}
+	}
+
+	p := plot.New()
+	p.Title.Text = "Downsampling"
+	p.X.Label.Text = "Time"
+	p.Add(plotter.NewGrid())
+
+	minMax, err := plotter.NewLine(series)
+	if err != nil {
+		log.Panic(err)
+	}
+	minMax.Downsample = plotter.MinMax
+	minMax.Color = color.RGBA{R: 196, B: 128, A: 255}
+
+	shifted := make(plotter.XYs, n)
+	for i, v := range series {
+		shifted[i] = plotter.XY{X: v.X, Y: v.Y - 5}
+	}
+	lttb, err := plotter.NewLine(shifted)
+	if err != nil {
+		log.Panic(err)
+	}
+	lttb.Downsample = plotter.LTTB
+	lttb.Color = color.RGBA{G: 128, B: 196, A: 255}
+
+	p.Add(minMax, lttb)
+	p.Legend.Add("MinMax", minMax)
+	p.Legend.Add("LTTB", lttb)
+	p.Legend.Top = true
+	p.Y.Max = 8
+
+	err = p.Save(10*vg.Centimeter, 6*vg.Centimeter, "testdata/downsample.png")
+	if err != nil {
+		log.Panic(err)
+	}
+}
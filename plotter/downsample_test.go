@@ -0,0 +1,78 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/recorder"
+)
+
+func TestDownsample(t *testing.T) {
+	cmpimg.CheckPlot(ExampleLine_downsample, t, "downsample.png")
+}
+
+func TestLineDownsample(t *testing.T) {
+	const n = 100000
+	xys := make(plotter.XYs, n)
+	for i := range xys {
+		xys[i] = plotter.XY{X: float64(i), Y: math.Sin(float64(i) / 100)}
+	}
+	xys[n/3].Y = 10
+
+	for _, test := range []struct {
+		kind      plotter.DownsampleKind
+		maxPoints int
+		peak      bool
+	}{
+		{kind: plotter.NoDownsample, maxPoints: n, peak: true},
+		// The first and last points, and the extrema, of the
+		// 100 columns and of the two columns out of the canvas.
+		{kind: plotter.MinMax, maxPoints: 4 * 102, peak: true},
+		// One point per column.
+		{kind: plotter.LTTB, maxPoints: 100},
+	} {
+		l, err := plotter.NewLine(xys)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		l.Downsample = test.kind
+		p := plot.New()
+		p.X.Min, p.X.Max = 0, n-1
+		p.Y.Min, p.Y.Max = -1, 10
+
+		var c recorder.Canvas
+		l.Plot(draw.NewCanvas(&c, 100, 100), p)
+
+		var strokes []recorder.Stroke
+		for _, a := range c.Actions {
+			if s, ok := a.(*recorder.Stroke); ok {
+				strokes = append(strokes, *s)
+			}
+		}
+		if len(strokes) != 1 {
+			t.Fatalf("unexpected number of strokes for kind %d: got:%d want:1", test.kind, len(strokes))
+		}
+		path := strokes[0].Path
+		if len(path) < 3 || len(path) > test.maxPoints {
+			t.Errorf("unexpected number of points for kind %d: got:%d want:[3, %d]", test.kind, len(path), test.maxPoints)
+		}
+		var top vg.Length
+		for _, comp := range path {
+			if comp.Pos.Y > top {
+				top = comp.Pos.Y
+			}
+		}
+		if test.peak && top != 100 {
+			t.Errorf("unexpected peak for kind %d: got:%v want:100", test.kind, top)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/text"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// barChartValue is a BarChart without its methods,
+// serialized with its exported fields.
+type barChartValue BarChart
+
+// barChartState is the serialized form of a BarChart.
+type barChartState struct {
+	BarChart  barChartValue
+	StackedOn *BarChart
+}
+
+// MarshalPlot implements the plot.Marshaler interface.
+func (b *BarChart) MarshalPlot() (interface{}, error) {
+	return barChartState{BarChart: barChartValue(*b), StackedOn: b.stackedOn}, nil
+}
+
+// UnmarshalPlot implements the plot.Unmarshaler interface.
+func (b *BarChart) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st barChartState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*b = BarChart(st.BarChart)
+	b.stackedOn = st.StackedOn
+	return nil
+}
+
+// densityValue is a Density without its methods,
+// serialized with its exported fields.
+type densityValue Density
+
+// densityState is the serialized form of a Density.
+type densityState struct {
+	Density                densityValue
+	XMin, XMax, YMin, YMax float64
+}
+
+// MarshalPlot implements the plot.Marshaler interface.
+func (d *Density) MarshalPlot() (interface{}, error) {
+	return densityState{
+		Density: densityValue(*d),
+		XMin:    d.xmin,
+		XMax:    d.xmax,
+		YMin:    d.ymin,
+		YMax:    d.ymax,
+	}, nil
+}
+
+// UnmarshalPlot implements the plot.Unmarshaler interface.
+func (d *Density) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st densityState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*d = Density(st.Density)
+	d.xmin, d.xmax, d.ymin, d.ymax = st.XMin, st.XMax, st.YMin, st.YMax
+	return nil
+}
+
+// fieldValue is a Field without its methods,
+// serialized with its exported fields.
+type fieldValue Field
+
+// fieldState is the serialized form of a Field.
+type fieldState struct {
+	Field fieldValue
+	Max   float64
+}
+
+// MarshalPlot implements the plot.Marshaler interface.
+func (f *Field) MarshalPlot() (interface{}, error) {
+	return fieldState{Field: fieldValue(*f), Max: f.max}, nil
+}
+
+// UnmarshalPlot implements the plot.Unmarshaler interface.
+func (f *Field) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st fieldState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*f = Field(st.Field)
+	f.max = st.Max
+	return nil
+}
+
+// imageState is the serialized form of an Image.
+type imageState struct {
+	Image                  image.Image
+	XMin, YMin, XMax, YMax float64
+}
+
+// MarshalPlot implements the plot.Marshaler interface.
+func (img *Image) MarshalPlot() (interface{}, error) {
+	return imageState{
+		Image: img.img,
+		XMin:  img.xmin,
+		YMin:  img.ymin,
+		XMax:  img.xmax,
+		YMax:  img.ymax,
+	}, nil
+}
+
+// UnmarshalPlot implements the plot.Unmarshaler interface.
+func (img *Image) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st imageState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	*img = *NewImage(st.Image, st.XMin, st.YMin, st.XMax, st.YMax)
+	return nil
+}
+
+// sankeyState is the serialized form of a Sankey.
+// FlowStyle and StockStyle are nil when they are the
+// default styles of the Sankey.
+type sankeyState struct {
+	Color         color.Color
+	StockBarWidth vg.Length
+	LineStyle     draw.LineStyle
+	TextStyle     text.Style
+	Flows         []Flow
+	FlowStyle     func(group string) (color.Color, draw.LineStyle)
+	StockStyle    func(label string, category int) (lbl string, ts text.Style, xOff, yOff vg.Length, c color.Color, ls draw.LineStyle)
+}
+
+var (
+	defaultFlowStyle  = reflect.ValueOf(new(Sankey).defaultFlowStyle).Pointer()
+	defaultStockStyle = reflect.ValueOf(new(Sankey).defaultStockStyle).Pointer()
+)
+
+// MarshalPlot implements the plot.Marshaler interface.
+func (s *Sankey) MarshalPlot() (interface{}, error) {
+	st := sankeyState{
+		Color:         s.Color,
+		StockBarWidth: s.StockBarWidth,
+		LineStyle:     s.LineStyle,
+		TextStyle:     s.TextStyle,
+		Flows:         s.flows,
+		FlowStyle:     s.FlowStyle,
+		StockStyle:    s.StockStyle,
+	}
+	if st.FlowStyle != nil && reflect.ValueOf(st.FlowStyle).Pointer() == defaultFlowStyle {
+		st.FlowStyle = nil
+	}
+	if st.StockStyle != nil && reflect.ValueOf(st.StockStyle).Pointer() == defaultStockStyle {
+		st.StockStyle = nil
+	}
+	return st, nil
+}
+
+// UnmarshalPlot implements the plot.Unmarshaler interface.
+func (s *Sankey) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st sankeyState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	v, err := NewSankey(st.Flows...)
+	if err != nil {
+		return err
+	}
+	*s = *v
+	s.Color = st.Color
+	s.StockBarWidth = st.StockBarWidth
+	s.LineStyle = st.LineStyle
+	s.TextStyle = st.TextStyle
+	s.FlowStyle = s.defaultFlowStyle
+	if st.FlowStyle != nil {
+		s.FlowStyle = st.FlowStyle
+	}
+	s.StockStyle = s.defaultStockStyle
+	if st.StockStyle != nil {
+		s.StockStyle = st.StockStyle
+	}
+	return nil
+}
+
+// paletteThumbnailerState is the serialized form of a
+// paletteThumbnailer.
+type paletteThumbnailerState struct {
+	Color color.Color
+}
+
+// MarshalPlot implements the plot.Marshaler interface.
+func (t paletteThumbnailer) MarshalPlot() (interface{}, error) {
+	return paletteThumbnailerState{Color: t.color}, nil
+}
+
+// UnmarshalPlot implements the plot.Unmarshaler interface.
+func (t *paletteThumbnailer) UnmarshalPlot(unmarshal func(interface{}) error) error {
+	var st paletteThumbnailerState
+	err := unmarshal(&st)
+	if err != nil {
+		return err
+	}
+	t.color = st.Color
+	return nil
+}
+
+func init() {
+	// Plotters and Thumbnailers, registered with
+	// their pointer types.
+	for _, v := range []interface{}{
+		BarChart{},
+		BoxPlot{},
+		ColorBar{},
+		Contour{},
+		Density{},
+		YErrorBars{},
+		XErrorBars{},
+		Field{},
+		Function{},
+		GlyphBoxes{},
+		Grid{},
+		HeatMap{},
+		HexBin{},
+		Histogram{},
+		Histogram2D{},
+		Image{},
+		Labels{},
+		Line{},
+		Polygon{},
+		QuartPlot{},
+		Sankey{},
+		Scatter{},
+		paletteThumbnailer{},
+		sankeyFlowThumbnailer{},
+	} {
+		plot.Register(v)
+		plot.Register(reflect.New(reflect.TypeOf(v)).Interface())
+	}
+
+	for _, v := range []interface{}{
+		// Data
+		Values(nil),
+		XYs(nil),
+		XValues{},
+		YValues{},
+		XYZs(nil),
+		XYValues{},
+		XYLabels{},
+		ValueLabels(nil),
+		Errors(nil),
+		XErrors(nil),
+		YErrors(nil),
+
+		// palette.Palette and palette.ColorMap
+		palette.Heat(1, 1),
+		palette.Radial(2, palette.Red, palette.Blue, 1),
+		palette.Reverse(nil),
+
+		// color.Color
+		palette.HSVA{},
+	} {
+		plot.Register(v)
+	}
+}
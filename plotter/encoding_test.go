@@ -0,0 +1,260 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot"
+	_ "github.com/emptywe/plot/gob"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/palette/moreland"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+func init() {
+	plot.Register(sliceGrid{})
+	plot.Register(swirl{})
+	plot.Register(errorPoints{})
+	plot.RegisterFunc("math.Sin", math.Sin)
+	plot.RegisterFunc("plotter_test.alternateGlyphs", alternateGlyphs)
+}
+
+// sliceGrid is a plotter.GridXYZ of the values of a slice.
+type sliceGrid struct {
+	Data [][]float64
+}
+
+func (g sliceGrid) Dims() (c, r int)   { return len(g.Data[0]), len(g.Data) }
+func (g sliceGrid) Z(c, r int) float64 { return g.Data[r][c] }
+func (g sliceGrid) X(c int) float64    { return float64(c) }
+func (g sliceGrid) Y(r int) float64    { return float64(r) }
+
+// swirl is a rotational plotter.FieldXY.
+type swirl struct {
+	N int
+}
+
+func (f swirl) Dims() (c, r int)           { return f.N, f.N }
+func (f swirl) Vector(c, r int) plotter.XY { return plotter.XY{X: -f.Y(r), Y: f.X(c)} }
+func (f swirl) X(c int) float64            { return float64(c - f.N/2) }
+func (f swirl) Y(r int) float64            { return float64(r - f.N/2) }
+
+// errorPoints are points with vertical error bars.
+type errorPoints struct {
+	plotter.XYs
+	plotter.YErrors
+}
+
+// alternateGlyphs is a plotter.Scatter GlyphStyleFunc.
+func alternateGlyphs(i int) draw.GlyphStyle {
+	sty := draw.GlyphStyle{Color: color.Black, Radius: 3, Shape: draw.RingGlyph{}}
+	if i%2 == 0 {
+		sty.Shape = draw.CrossGlyph{}
+	}
+	return sty
+}
+
+func TestEncoding(t *testing.T) {
+	xys := plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: 3}, {X: 2, Y: 2}, {X: 3, Y: 4}}
+	grid := sliceGrid{Data: [][]float64{{1, 2, 3}, {2, 4, 6}, {3, 6, math.NaN()}}}
+	// The contours of a grid with NaN values are not
+	// drawn in a deterministic order.
+	full := sliceGrid{Data: [][]float64{{1, 2, 3}, {2, 4, 6}, {3, 6, 9}}}
+
+	for _, test := range []struct {
+		name string
+		plot func() (*plot.Plot, error)
+	}{
+		{
+			name: "lines",
+			plot: func() (*plot.Plot, error) {
+				p := plot.New()
+				p.Add(plotter.NewGrid())
+				l, s, err := plotter.NewLinePoints(xys)
+				if err != nil {
+					return nil, err
+				}
+				l.FillColor = &vg.Hatch{Style: vg.HatchDiagonal, Color: color.Black, Spacing: 4, Width: 0.5}
+				l.StepStyle = plotter.MidStep
+				s.GlyphStyleFunc = alternateGlyphs
+				poly, err := plotter.NewPolygon(plotter.XYs{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}})
+				if err != nil {
+					return nil, err
+				}
+				poly.Color = &vg.LinearGradient{X2: 1, Stops: []vg.GradientStop{
+					{Offset: 0, Color: color.RGBA{R: 255, A: 255}},
+					{Offset: 1, Color: color.RGBA{B: 255, A: 255}},
+				}}
+				f := plotter.NewFunction(math.Sin)
+				f.Samples = 20
+				lbl, err := plotter.NewLabels(plotter.XYLabels{XYs: xys, Labels: []string{"a", "b", "c", "d"}})
+				if err != nil {
+					return nil, err
+				}
+				errs, err := plotter.NewYErrorBars(errorPoints{XYs: xys, YErrors: plotter.YErrors{{0.5, 0.5}, {0.2, 1}, {1, 1}, {0, 0.5}}})
+				if err != nil {
+					return nil, err
+				}
+				p.Add(l, s, poly, f, lbl, errs, plotter.NewGlyphBoxes())
+				p.Legend.Add("line", l, s)
+				p.Legend.Add("sin", f)
+				return p, nil
+			},
+		},
+		{
+			name: "bars",
+			plot: func() (*plot.Plot, error) {
+				p := plot.New()
+				b1, err := plotter.NewBarChart(plotter.Values{1, 2, 3}, 10)
+				if err != nil {
+					return nil, err
+				}
+				b1.Color = color.Gray{Y: 0x80}
+				b2, err := plotter.NewBarChart(plotter.Values{3, 2, 1}, 10)
+				if err != nil {
+					return nil, err
+				}
+				b2.Color = palette.HSVA{H: 0.5, S: 1, V: 1, A: 1}
+				b2.StackOn(b1)
+				h, err := plotter.NewHist(plotter.Values{1, 1, 2, 3, 3, 3, 4}, 4)
+				if err != nil {
+					return nil, err
+				}
+				box, err := plotter.NewBoxPlot(10, 5, plotter.Values{1, 2, 3, 4, 10})
+				if err != nil {
+					return nil, err
+				}
+				box.Horizontal = true
+				quart, err := plotter.NewQuartPlot(6, plotter.Values{1, 2, 3, 4, 10})
+				if err != nil {
+					return nil, err
+				}
+				p.Add(b1, b2, h, box, quart)
+				p.Legend.Add("b1", b1)
+				p.Legend.Add("b2", b2)
+				return p, nil
+			},
+		},
+		{
+			name: "grids",
+			plot: func() (*plot.Plot, error) {
+				p := plot.New()
+				p.Y.Scale = plot.InvertedScale{Normalizer: plot.LinearScale{}}
+				heat := plotter.NewHeatMap(grid, palette.Heat(8, 1))
+				heat.NaN = color.Transparent
+				cm := moreland.SmoothBlueRed()
+				cm.SetMax(6)
+				cont := plotter.NewContour(full, []float64{2, 4}, cm.Palette(4))
+				img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+				for i := range img.Pix {
+					img.Pix[i] = uint8(i * 4)
+				}
+				p.Add(heat, cont, plotter.NewImage(img, 3, 3, 5, 5), plotter.NewField(swirl{N: 4}))
+				for i, thumb := range plotter.PaletteThumbnailers(palette.Reverse(cm).Palette(3)) {
+					p.Legend.Add(string(rune('A'+i)), thumb)
+				}
+				return p, nil
+			},
+		},
+		{
+			name: "colorbar",
+			plot: func() (*plot.Plot, error) {
+				p := plot.New()
+				cm := moreland.Kindlmann()
+				cm.SetMax(1)
+				p.Add(&plotter.ColorBar{ColorMap: cm})
+				p.HideY()
+				return p, nil
+			},
+		},
+		{
+			name: "sankey",
+			plot: func() (*plot.Plot, error) {
+				p := plot.New()
+				s, err := plotter.NewSankey(
+					plotter.Flow{SourceLabel: "a", ReceptorLabel: "b", SourceCategory: 0, ReceptorCategory: 1, Value: 2, Group: "x"},
+					plotter.Flow{SourceLabel: "a", ReceptorLabel: "c", SourceCategory: 0, ReceptorCategory: 1, Value: 1, Group: "y"},
+				)
+				if err != nil {
+					return nil, err
+				}
+				s.Color = color.RGBA{G: 128, A: 255}
+				p.Add(s)
+				labels, thumbs := s.Thumbnailers()
+				for i, l := range labels {
+					p.Legend.Add(l, thumbs[i])
+				}
+				p.HideAxes()
+				return p, nil
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := test.plot()
+			if err != nil {
+				t.Fatalf("could not create plot: %+v", err)
+			}
+			want := render(t, p)
+
+			b, err := json.Marshal(p)
+			if err != nil {
+				t.Fatalf("could not encode plot: %+v", err)
+			}
+			var got plot.Plot
+			err = json.Unmarshal(b, &got)
+			if err != nil {
+				t.Fatalf("could not decode plot: %+v", err)
+			}
+			if !bytes.Equal(render(t, &got), want) {
+				t.Errorf("decoded plot does not render as the encoded plot")
+			}
+			b2, err := json.Marshal(&got)
+			if err != nil {
+				t.Fatalf("could not encode decoded plot: %+v", err)
+			}
+			if !bytes.Equal(b, b2) {
+				t.Errorf("unexpected encoding of decoded plot:\ngot: %s\nwant:%s", b2, b)
+			}
+
+			var buf bytes.Buffer
+			err = gob.NewEncoder(&buf).Encode(p)
+			if err != nil {
+				t.Fatalf("could not gob-encode plot: %+v", err)
+			}
+			var gobbed plot.Plot
+			err = gob.NewDecoder(&buf).Decode(&gobbed)
+			if err != nil {
+				t.Fatalf("could not gob-decode plot: %+v", err)
+			}
+			if !bytes.Equal(render(t, &gobbed), want) {
+				t.Errorf("gob-decoded plot does not render as the encoded plot")
+			}
+		})
+	}
+}
+
+// render returns the plot rendered as PNG.
+func render(t *testing.T, p *plot.Plot) []byte {
+	t.Helper()
+	w, err := p.WriterTo(10*vg.Centimeter, 10*vg.Centimeter, "png")
+	if err != nil {
+		t.Fatalf("could not create PNG writer: %+v", err)
+	}
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("could not render plot: %+v", err)
+	}
+	return buf.Bytes()
+}
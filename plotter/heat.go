@@ -5,6 +5,7 @@
 package plotter
 
 import (
+	"context"
 	"image"
 	"image/color"
 	"math"
@@ -103,15 +104,21 @@ func NewHeatMap(g GridXYZ, p palette.Palette) *HeatMap {
 
 // Plot implements the Plot method of the plot.Plotter interface.
 func (h *HeatMap) Plot(c draw.Canvas, plt *plot.Plot) {
+	_ = h.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext implements the PlotContext method of the
+// plot.ContextPlotter interface. The context is checked
+// for cancellation before each column of the grid.
+func (h *HeatMap) PlotContext(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
 	if h.Rasterized {
-		h.plotRasterized(c, plt)
-	} else {
-		h.plotVectorized(c, plt)
+		return h.plotRasterized(ctx, c, plt)
 	}
+	return h.plotVectorized(ctx, c, plt)
 }
 
 // plotRasterized plots the heatmap using raster-based drawing.
-func (h *HeatMap) plotRasterized(c draw.Canvas, plt *plot.Plot) {
+func (h *HeatMap) plotRasterized(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
 	cols, rows := h.GridXYZ.Dims()
 	img := image.NewRGBA64(image.Rectangle{
 		Min: image.Point{X: 0, Y: 0},
@@ -121,6 +128,9 @@ func (h *HeatMap) plotRasterized(c draw.Canvas, plt *plot.Plot) {
 	pal := h.Palette.Colors()
 	ps := float64(len(pal)-1) / (h.Max - h.Min)
 	for i := 0; i < cols; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		for j := 0; j < rows; j++ {
 			var col color.Color
 			switch v := h.GridXYZ.Z(i, j); {
@@ -143,10 +153,11 @@ func (h *HeatMap) plotRasterized(c draw.Canvas, plt *plot.Plot) {
 	xmin, xmax, ymin, ymax := h.DataRange()
 	pImg := NewImage(img, xmin, ymin, xmax, ymax)
 	pImg.Plot(c, plt)
+	return nil
 }
 
 //  plotVectorized plots the heatmap using vector-based drawing.
-func (h *HeatMap) plotVectorized(c draw.Canvas, plt *plot.Plot) {
+func (h *HeatMap) plotVectorized(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
 	if h.Min > h.Max {
 		panic("contour: invalid Z range: min greater than max")
 	}
@@ -162,43 +173,14 @@ func (h *HeatMap) plotVectorized(c draw.Canvas, plt *plot.Plot) {
 	var pa vg.Path
 	cols, rows := h.GridXYZ.Dims()
 	for i := 0; i < cols; i++ {
-		var right, left float64
-		switch i {
-		case 0:
-			if cols == 1 {
-				right = 0.5
-			} else {
-				right = (h.GridXYZ.X(1) - h.GridXYZ.X(0)) / 2
-			}
-			left = -right
-		case cols - 1:
-			right = (h.GridXYZ.X(cols-1) - h.GridXYZ.X(cols-2)) / 2
-			left = -right
-		default:
-			right = (h.GridXYZ.X(i+1) - h.GridXYZ.X(i)) / 2
-			left = -(h.GridXYZ.X(i) - h.GridXYZ.X(i-1)) / 2
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-
+		left, right := cellX(h.GridXYZ, i)
 		for j := 0; j < rows; j++ {
-			var up, down float64
-			switch j {
-			case 0:
-				if rows == 1 {
-					up = 0.5
-				} else {
-					up = (h.GridXYZ.Y(1) - h.GridXYZ.Y(0)) / 2
-				}
-				down = -up
-			case rows - 1:
-				up = (h.GridXYZ.Y(rows-1) - h.GridXYZ.Y(rows-2)) / 2
-				down = -up
-			default:
-				up = (h.GridXYZ.Y(j+1) - h.GridXYZ.Y(j)) / 2
-				down = -(h.GridXYZ.Y(j) - h.GridXYZ.Y(j-1)) / 2
-			}
-
-			x, y := trX(h.GridXYZ.X(i)+left), trY(h.GridXYZ.Y(j)+down)
-			dx, dy := trX(h.GridXYZ.X(i)+right), trY(h.GridXYZ.Y(j)+up)
+			down, up := cellY(h.GridXYZ, j)
+			x, y := trX(left), trY(down)
+			dx, dy := trX(right), trY(up)
 
 			if !c.Contains(vg.Point{X: x, Y: y}) || !c.Contains(vg.Point{X: dx, Y: dy}) {
 				continue
@@ -228,29 +210,67 @@ func (h *HeatMap) plotVectorized(c draw.Canvas, plt *plot.Plot) {
 			}
 		}
 	}
+	return nil
 }
 
 // DataRange implements the DataRange method
 // of the plot.DataRanger interface.
 func (h *HeatMap) DataRange() (xmin, xmax, ymin, ymax float64) {
 	c, r := h.GridXYZ.Dims()
-	switch c {
-	case 1: // Make a unit length when there is no neighbour.
-		xmax = h.GridXYZ.X(0) + 0.5
-		xmin = h.GridXYZ.X(0) - 0.5
-	default:
-		xmax = h.GridXYZ.X(c-1) + (h.GridXYZ.X(c-1)-h.GridXYZ.X(c-2))/2
-		xmin = h.GridXYZ.X(0) - (h.GridXYZ.X(1)-h.GridXYZ.X(0))/2
+	xmin, _ = cellX(h.GridXYZ, 0)
+	_, xmax = cellX(h.GridXYZ, c-1)
+	ymin, _ = cellY(h.GridXYZ, 0)
+	_, ymax = cellY(h.GridXYZ, r-1)
+	return xmin, xmax, ymin, ymax
+}
+
+// cellBounder is a GridXYZ whose cells have explicit
+// bounds, rather than bounds halfway between the
+// coordinates of the neighbouring cells.
+type cellBounder interface {
+	cellX(c int) (min, max float64)
+	cellY(r int) (min, max float64)
+}
+
+// cellX returns the bounds of the column c of g.
+func cellX(g GridXYZ, c int) (min, max float64) {
+	if g, ok := g.(cellBounder); ok {
+		return g.cellX(c)
 	}
-	switch r {
-	case 1: // Make a unit length when there is no neighbour.
-		ymax = h.GridXYZ.Y(0) + 0.5
-		ymin = h.GridXYZ.Y(0) - 0.5
+	cols, _ := g.Dims()
+	return cellBounds(g.X, c, cols)
+}
+
+// cellY returns the bounds of the row r of g.
+func cellY(g GridXYZ, r int) (min, max float64) {
+	if g, ok := g.(cellBounder); ok {
+		return g.cellY(r)
+	}
+	_, rows := g.Dims()
+	return cellBounds(g.Y, r, rows)
+}
+
+// cellBounds returns the bounds of the cell i of the n
+// cells at the coordinates returned by at, halfway between
+// the cell and its neighbours.
+func cellBounds(at func(int) float64, i, n int) (min, max float64) {
+	var lo, hi float64
+	switch i {
+	case 0:
+		if n == 1 {
+			hi = 0.5 // Make a unit length when there is no neighbour.
+		} else {
+			hi = (at(1) - at(0)) / 2
+		}
+		lo = -hi
+	case n - 1:
+		hi = (at(n-1) - at(n-2)) / 2
+		lo = -hi
 	default:
-		ymax = h.GridXYZ.Y(r-1) + (h.GridXYZ.Y(r-1)-h.GridXYZ.Y(r-2))/2
-		ymin = h.GridXYZ.Y(0) - (h.GridXYZ.Y(1)-h.GridXYZ.Y(0))/2
+		hi = (at(i+1) - at(i)) / 2
+		lo = -(at(i) - at(i-1)) / 2
 	}
-	return xmin, xmax, ymin, ymax
+	return at(i) + lo, at(i) + hi
 }
 
 // GlyphBoxes implements the GlyphBoxes method
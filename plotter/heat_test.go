@@ -5,6 +5,8 @@
 package plotter_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
 	"testing"
@@ -13,7 +15,9 @@ import (
 	"github.com/emptywe/plot/cmpimg"
 	"github.com/emptywe/plot/palette"
 	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
 	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/recorder"
 	"github.com/emptywe/plot/vg/vgimg"
 	"gonum.org/v1/gonum/mat"
 )
@@ -91,3 +95,69 @@ func TestHeatMapDims(t *testing.T) {
 func TestRasterHeatMap(t *testing.T) {
 	cmpimg.CheckPlot(ExampleHeatMap_rasterized, t, "rasterHeatMap.png")
 }
+
+func TestHeatMapPlotContext(t *testing.T) {
+	data := make([]float64, 50*50)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	for _, rasterized := range []bool{false, true} {
+		h := plotter.NewHeatMap(offsetUnitGrid{Data: mat.NewDense(50, 50, data)}, palette.Heat(12, 1))
+		h.Rasterized = rasterized
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c := &cancelCanvas{Canvas: new(recorder.Canvas)}
+		err := h.PlotContext(ctx, draw.Canvas{Canvas: c}, plot.New())
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected error for rasterized=%t: got:%v want:%v", rasterized, err, context.Canceled)
+		}
+		if c.n != 0 {
+			t.Errorf("unexpected drawing for rasterized=%t: %d actions", rasterized, c.n)
+		}
+	}
+
+	// The vectorized heat map is drawn up to the end
+	// of the column being drawn when the context is
+	// cancelled.
+	h := plotter.NewHeatMap(offsetUnitGrid{Data: mat.NewDense(50, 50, data)}, palette.Heat(12, 1))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := &cancelCanvas{Canvas: new(recorder.Canvas), after: 10, cancel: cancel}
+	p := plot.New()
+	p.Add(h)
+	err := h.PlotContext(ctx, draw.Canvas{Canvas: c, Rectangle: vg.Rectangle{Max: vg.Point{X: 100, Y: 100}}}, p)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.Canceled)
+	}
+	if c.n != 50 {
+		t.Errorf("unexpected number of cells drawn: got:%d want:50", c.n)
+	}
+}
+
+// cancelCanvas is a canvas counting the paths it fills
+// and strokes, and cancelling a context after a number
+// of them.
+type cancelCanvas struct {
+	vg.Canvas
+	n      int
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *cancelCanvas) Fill(p vg.Path) {
+	c.count()
+	c.Canvas.Fill(p)
+}
+
+func (c *cancelCanvas) Stroke(p vg.Path) {
+	c.count()
+	c.Canvas.Stroke(p)
+}
+
+func (c *cancelCanvas) count() {
+	c.n++
+	if c.n == c.after {
+		c.cancel()
+	}
+}
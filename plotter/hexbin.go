@@ -0,0 +1,280 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// HexBin implements the Plotter interface, drawing the points
+// of an XYer binned in a grid of hexagons colored by the value
+// of their cell.
+//
+// The grid is made of two rectangular lattices of hexagon
+// centers, the second one offset by half a cell in x and y.
+// The centers of the first lattice are spaced by Width in x
+// and Height in y, and the hexagons span Width in x and
+// Height*2/3 in y.
+type HexBin struct {
+	// Cells are the non-empty cells of the grid.
+	Cells []HexCell
+
+	// Width and Height are the spacing of the
+	// centers of the cells in data coordinates.
+	Width, Height float64
+
+	// ColorMap is the color map of the values of the
+	// cells. The range of ColorMap is set to the range
+	// of the values by NewHexBin and NewHexBinXYZ, so
+	// that the ColorMap can be shared with a ColorBar.
+	ColorMap palette.ColorMap
+
+	// LineStyle is the style of the outline
+	// of the cells. The cells are not outlined
+	// if the width of LineStyle is zero.
+	LineStyle draw.LineStyle
+}
+
+// HexCell is a cell of a HexBin.
+type HexCell struct {
+	// X and Y are the center of the cell.
+	X, Y float64
+
+	// Count is the number of points in the cell.
+	Count int
+
+	// Value is the value of the cell, reduced
+	// from the points in the cell.
+	Value float64
+}
+
+// NewHexBin returns a HexBin of the points of xys, binned
+// in gridsize hexagons along the x range of the points and
+// colored by their count with cm.
+//
+// If xys is MissingXYs, the points holding a NaN are skipped.
+// NewHexBin returns an error if xys has no points or holds an
+// invalid value.
+func NewHexBin(xys XYer, gridsize int, cm palette.ColorMap) (*HexBin, error) {
+	return newHexBin(xys, nil, gridsize, nil, cm)
+}
+
+// NewHexBinXYZ returns a HexBin of the points of xyzs, binned
+// in gridsize hexagons along the x range of the points and
+// colored with cm by the z values of their points, reduced
+// by reduce. If reduce is nil, the mean of the z values is used.
+//
+// NewHexBinXYZ returns an error if xyzs has no points or holds
+// an invalid value.
+func NewHexBinXYZ(xyzs XYZer, gridsize int, reduce func(zs []float64) float64, cm palette.ColorMap) (*HexBin, error) {
+	if reduce == nil {
+		reduce = func(zs []float64) float64 {
+			var sum float64
+			for _, z := range zs {
+				sum += z
+			}
+			return sum / float64(len(zs))
+		}
+	}
+	return newHexBin(xyzs, xyzs, gridsize, reduce, cm)
+}
+
+// hexKey identifies a cell of the lattice of a HexBin,
+// by its lattice and its indices in the lattice.
+type hexKey struct {
+	lattice, i, j int
+}
+
+func newHexBin(xys XYer, xyzs XYZer, gridsize int, reduce func([]float64) float64, cm palette.ColorMap) (*HexBin, error) {
+	if gridsize < 1 {
+		return nil, errors.New("plotter: gridsize < 1")
+	}
+	if cm == nil {
+		panic("plotter: nil ColorMap in HexBin")
+	}
+	_, skip := xys.(MissingXYs)
+
+	xmin, ymin := math.Inf(1), math.Inf(1)
+	xmax, ymax := math.Inf(-1), math.Inf(-1)
+	for i := 0; i < xys.Len(); i++ {
+		x, y := xys.XY(i)
+		if skip && (math.IsNaN(x) || math.IsNaN(y)) {
+			continue
+		}
+		if err := CheckFloats(x, y); err != nil {
+			return nil, err
+		}
+		if xyzs != nil {
+			_, _, z := xyzs.XYZ(i)
+			if err := CheckFloats(z); err != nil {
+				return nil, err
+			}
+		}
+		xmin = math.Min(xmin, x)
+		xmax = math.Max(xmax, x)
+		ymin = math.Min(ymin, y)
+		ymax = math.Max(ymax, y)
+	}
+	if xmin > xmax {
+		return nil, ErrNoData
+	}
+
+	// The rows of the grid are spaced so that the
+	// hexagons are regular when the x and y ranges
+	// of the points are drawn with the same length.
+	nx := float64(gridsize)
+	ny := math.Max(1, math.Floor(nx/math.Sqrt(3)))
+	h := &HexBin{
+		Width:    (xmax - xmin) / nx,
+		Height:   (ymax - ymin) / ny,
+		ColorMap: cm,
+	}
+	if h.Width == 0 {
+		h.Width = 1
+	}
+	if h.Height == 0 {
+		h.Height = 1
+	}
+
+	counts := make(map[hexKey]int)
+	zs := make(map[hexKey][]float64)
+	for i := 0; i < xys.Len(); i++ {
+		x, y := xys.XY(i)
+		if skip && (math.IsNaN(x) || math.IsNaN(y)) {
+			continue
+		}
+		k := h.cell(x-xmin, y-ymin)
+		counts[k]++
+		if xyzs != nil {
+			_, _, z := xyzs.XYZ(i)
+			zs[k] = append(zs[k], z)
+		}
+	}
+
+	h.Cells = make([]HexCell, 0, len(counts))
+	for k, n := range counts {
+		x := (float64(k.i) + 0.5*float64(k.lattice)) * h.Width
+		y := (float64(k.j) + 0.5*float64(k.lattice)) * h.Height
+		cell := HexCell{X: xmin + x, Y: ymin + y, Count: n, Value: float64(n)}
+		if reduce != nil {
+			cell.Value = reduce(zs[k])
+		}
+		h.Cells = append(h.Cells, cell)
+	}
+	sort.Slice(h.Cells, func(i, j int) bool {
+		a, b := h.Cells[i], h.Cells[j]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+
+	min, max := h.Range()
+	if min == max {
+		min, max = min-0.5, max+0.5
+	}
+	cm.SetMin(min)
+	cm.SetMax(max)
+	return h, nil
+}
+
+// cell returns the key of the cell holding the point
+// at x, y from the origin of the first lattice.
+func (h *HexBin) cell(x, y float64) hexKey {
+	x /= h.Width
+	y /= h.Height
+
+	// The nearest centers of the two lattices, compared with
+	// the y distances scaled to the aspect of the hexagons.
+	i1, j1 := math.Round(x), math.Round(y)
+	i2, j2 := math.Floor(x), math.Floor(y)
+	d1 := (x-i1)*(x-i1) + 3*(y-j1)*(y-j1)
+	d2 := (x-i2-0.5)*(x-i2-0.5) + 3*(y-j2-0.5)*(y-j2-0.5)
+	if d1 <= d2 {
+		return hexKey{lattice: 0, i: int(i1), j: int(j1)}
+	}
+	return hexKey{lattice: 1, i: int(i2), j: int(j2)}
+}
+
+// Range returns the minimum and maximum values of the cells.
+func (h *HexBin) Range() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, cell := range h.Cells {
+		min = math.Min(min, cell.Value)
+		max = math.Max(max, cell.Value)
+	}
+	return min, max
+}
+
+// hexagon returns the vertices of the hexagon
+// of the cell centered at x, y.
+func (h *HexBin) hexagon(x, y float64) [6]XY {
+	dx, dy := h.Width/2, h.Height/6
+	return [6]XY{
+		{X: x + dx, Y: y - dy},
+		{X: x + dx, Y: y + dy},
+		{X: x, Y: y + 2*dy},
+		{X: x - dx, Y: y + dy},
+		{X: x - dx, Y: y - dy},
+		{X: x, Y: y - 2*dy},
+	}
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (h *HexBin) Plot(c draw.Canvas, plt *plot.Plot) {
+	_ = h.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext implements the PlotContext method of the
+// plot.ContextPlotter interface.
+func (h *HexBin) PlotContext(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
+	if h.ColorMap == nil {
+		panic("plotter: nil ColorMap in HexBin")
+	}
+	trX, trY := plt.Transforms(&c)
+
+	ps := make([]vg.Point, 6)
+	for i, cell := range h.Cells {
+		if i%cancelCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		for j, p := range h.hexagon(cell.X, cell.Y) {
+			ps[j] = vg.Point{X: trX(p.X), Y: trY(p.Y)}
+		}
+		col, err := h.ColorMap.At(cell.Value)
+		if err == nil {
+			c.FillPolygon(col, c.ClipPolygonXY(ps))
+		}
+		if h.LineStyle.Width != 0 {
+			ring := append(ps[:len(ps):len(ps)], ps[0])
+			c.StrokeLines(h.LineStyle, c.ClipLinesXY(ring)...)
+		}
+	}
+	return nil
+}
+
+// DataRange implements the DataRange method
+// of the plot.DataRanger interface.
+func (h *HexBin) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+	for _, cell := range h.Cells {
+		xmin = math.Min(xmin, cell.X-h.Width/2)
+		xmax = math.Max(xmax, cell.X+h.Width/2)
+		ymin = math.Min(ymin, cell.Y-h.Height/3)
+		ymax = math.Max(ymax, cell.Y+h.Height/3)
+	}
+	return xmin, xmax, ymin, ymax
+}
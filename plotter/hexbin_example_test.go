@@ -0,0 +1,77 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"log"
+	"os"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette/moreland"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/vgimg"
+)
+
+// ExampleHexBin draws the counts of a cloud of points binned
+// in hexagons, with a ColorBar sharing the ColorMap of the cells.
+func ExampleHexBin() {
+	rnd := rand.New(rand.NewSource(1))
+
+	// Two overlapping clusters of points.
+	const n = 20000
+	pts := make(plotter.XYs, n)
+	for i := range pts {
+		if i%4 == 0 {
+			pts[i] = plotter.XY{X: 2 + 0.5*rnd.NormFloat64(), Y: 1 + 0.5*rnd.NormFloat64()}
+			continue
+		}
+		x := rnd.NormFloat64()
+		pts[i] = plotter.XY{X: x, Y: 0.5*x + 0.5*rnd.NormFloat64()}
+	}
+
+	cm := moreland.ExtendedBlackBody()
+	h, err := plotter.NewHexBin(pts, 20, cm)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	p := plot.New()
+	p.Title.Text = "HexBin"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+	p.Add(h)
+
+	cb := plot.New()
+	cb.HideX()
+	cb.Y.Padding = 0
+	cb.Title.Text = "Count"
+	cb.Add(&plotter.ColorBar{ColorMap: cm, Vertical: true})
+
+	// This is the width of the color bar, experimentally determined.
+	const barWidth = 1.5 * vg.Centimeter
+
+	const width, height = 12 * vg.Centimeter, 10 * vg.Centimeter
+	img := vgimg.New(width, height)
+	dc := draw.New(img)
+	p.Draw(draw.Crop(dc, 0, -barWidth, 0, 0))
+	cb.Draw(draw.Crop(dc, width-barWidth, 0, 0, 0))
+
+	w, err := os.Create("testdata/hexbin.png")
+	if err != nil {
+		log.Panic(err)
+	}
+	defer w.Close()
+	png := vgimg.PngCanvas{Canvas: img}
+	if _, err = png.WriteTo(w); err != nil {
+		log.Panic(err)
+	}
+	if err = w.Close(); err != nil {
+		log.Panic(err)
+	}
+}
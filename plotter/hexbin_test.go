@@ -0,0 +1,100 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/palette/moreland"
+	"github.com/emptywe/plot/plotter"
+)
+
+func TestHexBin(t *testing.T) {
+	cmpimg.CheckPlot(ExampleHexBin, t, "hexbin.png")
+}
+
+func TestHexBinCells(t *testing.T) {
+	// Points at the corners of a 2×1 range, binned in two
+	// hexagons per row, and a point at the center of a
+	// cell of the offset lattice.
+	pts := plotter.XYZs{
+		{X: 0, Y: 0, Z: 1},
+		{X: 0.1, Y: 0.1, Z: 3},
+		{X: 2, Y: 1, Z: 5},
+		{X: 0.5, Y: 0.5, Z: 7},
+	}
+
+	cm := moreland.BlackBody()
+	h, err := plotter.NewHexBin(plotter.XYValues{XYZer: pts}, 2, cm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Width != 1 || h.Height != 1 {
+		t.Errorf("unexpected cell size: got:%vx%v want:1x1", h.Width, h.Height)
+	}
+	want := []plotter.HexCell{
+		{X: 0, Y: 0, Count: 2, Value: 2},
+		{X: 0.5, Y: 0.5, Count: 1, Value: 1},
+		{X: 2, Y: 1, Count: 1, Value: 1},
+	}
+	checkHexCells(t, "count", h.Cells, want)
+	if cm.Min() != 1 || cm.Max() != 2 {
+		t.Errorf("unexpected ColorMap range: got:[%v, %v] want:[1, 2]", cm.Min(), cm.Max())
+	}
+
+	h, err = plotter.NewHexBinXYZ(pts, 2, nil, cm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []plotter.HexCell{
+		{X: 0, Y: 0, Count: 2, Value: 2},
+		{X: 0.5, Y: 0.5, Count: 1, Value: 7},
+		{X: 2, Y: 1, Count: 1, Value: 5},
+	}
+	checkHexCells(t, "mean", h.Cells, want)
+	if cm.Min() != 2 || cm.Max() != 7 {
+		t.Errorf("unexpected ColorMap range: got:[%v, %v] want:[2, 7]", cm.Min(), cm.Max())
+	}
+
+	xmin, xmax, ymin, ymax := h.DataRange()
+	if xmin != -0.5 || xmax != 2.5 || ymin != -1.0/3 || ymax != 4.0/3 {
+		t.Errorf("unexpected data range: got:[%v, %v]×[%v, %v]", xmin, xmax, ymin, ymax)
+	}
+}
+
+func checkHexCells(t *testing.T, name string, got, want []plotter.HexCell) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: unexpected cells: got:%v want:%v", name, got, want)
+		return
+	}
+	for i := range got {
+		g, w := got[i], want[i]
+		if math.Abs(g.X-w.X) > 1e-12 || math.Abs(g.Y-w.Y) > 1e-12 || g.Count != w.Count || g.Value != w.Value {
+			t.Errorf("%s: unexpected cell %d: got:%+v want:%+v", name, i, g, w)
+		}
+	}
+}
+
+func TestHexBinErrors(t *testing.T) {
+	if _, err := plotter.NewHexBin(plotter.XYs{}, 10, moreland.BlackBody()); err != plotter.ErrNoData {
+		t.Errorf("unexpected error for no data: got:%v want:%v", err, plotter.ErrNoData)
+	}
+	if _, err := plotter.NewHexBin(plotter.XYs{{X: 1, Y: 1}}, 0, moreland.BlackBody()); err == nil {
+		t.Error("expected error for zero gridsize")
+	}
+	if _, err := plotter.NewHexBin(plotter.XYs{{X: math.NaN(), Y: 1}}, 10, moreland.BlackBody()); err == nil {
+		t.Error("expected error for NaN point")
+	}
+	h, err := plotter.NewHexBin(plotter.MissingXYs{XYer: plotter.XYs{{X: math.NaN(), Y: 1}, {X: 1, Y: 1}}}, 10, moreland.BlackBody())
+	if err != nil {
+		t.Fatalf("unexpected error for missing point: %v", err)
+	}
+	if len(h.Cells) != 1 || h.Cells[0].Count != 1 {
+		t.Errorf("unexpected cells: got:%v", h.Cells)
+	}
+}
@@ -27,6 +27,7 @@ type Histogram struct {
 	// FillColor is the color used to fill each
 	// bar of the histogram.  If the color is nil
 	// then the bars are not filled.
+	// FillColor may be a vg.Hatch pattern.
 	FillColor color.Color
 
 	// LineStyle is the style of the outline of each
@@ -47,7 +48,9 @@ type Histogram struct {
 // using the given number of bins.
 //
 // Each y value is assumed to be the frequency
-// count for the corresponding x.
+// count for the corresponding x. If xy is
+// MissingXYs, the points holding a NaN value
+// are skipped.
 //
 // If the number of bins is non-positive than
 // a reasonable default is used.
@@ -55,7 +58,11 @@ func NewHistogram(xy XYer, n int) (*Histogram, error) {
 	if n <= 0 {
 		return nil, errors.New("Histogram with non-positive number of bins")
 	}
-	bins, width := binPoints(xy, n)
+	xys, err := histogramXYs(xy)
+	if err != nil {
+		return nil, err
+	}
+	bins, width := binPoints(xys, n)
 	return &Histogram{
 		Bins:      bins,
 		Width:     width,
@@ -66,11 +73,40 @@ func NewHistogram(xy XYer, n int) (*Histogram, error) {
 
 // NewHist returns a new histogram, as in
 // NewHistogram, except that it accepts a Valuer
-// instead of an XYer.
+// instead of an XYer. If vs is MissingValues,
+// the NaN values are skipped.
 func NewHist(vs Valuer, n int) (*Histogram, error) {
+	if _, ok := vs.(MissingValues); ok {
+		return NewHistogram(MissingXYs{unitYs{vs}}, n)
+	}
 	return NewHistogram(unitYs{vs}, n)
 }
 
+// histogramXYs returns a copy of the points of xy,
+// without the points holding a NaN value if xy is
+// MissingXYs, or an error if there are no points
+// left, or if one of the points holds a NaN or
+// Infinity.
+func histogramXYs(xy XYer) (XYs, error) {
+	_, skip := xy.(MissingXYs)
+	xys := make(XYs, 0, xy.Len())
+	for i := 0; i < xy.Len(); i++ {
+		var p XY
+		p.X, p.Y = xy.XY(i)
+		if skip && p.missing() {
+			continue
+		}
+		if err := CheckFloats(p.X, p.Y); err != nil {
+			return nil, err
+		}
+		xys = append(xys, p)
+	}
+	if len(xys) == 0 {
+		return nil, ErrNoData
+	}
+	return xys, nil
+}
+
 type unitYs struct {
 	Valuer
 }
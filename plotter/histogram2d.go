@@ -0,0 +1,329 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+// BinRule is a rule choosing the number of bins
+// of an axis of a histogram from its values.
+// A rule chooses at most as many bins as values.
+type BinRule int
+
+const (
+	// SqrtRule uses the square root of the number of values.
+	SqrtRule BinRule = iota
+
+	// SturgesRule uses log2(n)+1 bins for n values.
+	SturgesRule
+
+	// ScottRule uses bins of width 3.49σn^(-1/3)
+	// for n values of standard deviation σ.
+	ScottRule
+
+	// FreedmanDiaconisRule uses bins of width 2×IQR×n^(-1/3)
+	// for n values of interquartile range IQR.
+	FreedmanDiaconisRule
+)
+
+// Bins specifies the bins of an axis of a Histogram2D.
+// The bins are given by Edges if it is not nil, or else
+// are N bins of equal width spanning the range of the
+// values if N is positive, or else are chosen by Rule.
+type Bins struct {
+	// N is the number of bins.
+	N int
+
+	// Edges are the increasing edges of the bins.
+	// The values outside of Edges are not binned.
+	Edges []float64
+
+	// Rule is the rule choosing the number of bins.
+	Rule BinRule
+}
+
+// edges returns the edges of the bins of vs,
+// sorted in increasing order.
+func (b Bins) edges(vs []float64) ([]float64, error) {
+	if b.Edges != nil {
+		if len(b.Edges) < 2 {
+			return nil, errors.New("plotter: fewer than two bin edges")
+		}
+		for i, e := range b.Edges {
+			if err := CheckFloats(e); err != nil {
+				return nil, err
+			}
+			if i > 0 && e <= b.Edges[i-1] {
+				return nil, errors.New("plotter: bin edges not increasing")
+			}
+		}
+		return append([]float64(nil), b.Edges...), nil
+	}
+
+	sort.Float64s(vs)
+	min, max := vs[0], vs[len(vs)-1]
+	if min == max {
+		// Make a unit width bin around the values.
+		return []float64{min - 0.5, max + 0.5}, nil
+	}
+	n := b.N
+	if n <= 0 {
+		n = b.Rule.bins(vs)
+	}
+	edges := make([]float64, n+1)
+	for i := range edges {
+		edges[i] = min + (max-min)*float64(i)/float64(n)
+	}
+	edges[n] = max
+	return edges, nil
+}
+
+// bins returns the number of bins of the sorted values
+// vs, which hold at least two distinct values. The number
+// of bins is at most len(vs), for outliers not to make
+// rules based on the spread of the values choose a huge
+// number of bins.
+func (r BinRule) bins(vs []float64) int {
+	n := float64(len(vs))
+	var width float64
+	switch r {
+	case SturgesRule:
+		return int(math.Ceil(math.Log2(n))) + 1
+	case ScottRule:
+		var mean, ss float64
+		for _, v := range vs {
+			mean += v
+		}
+		mean /= n
+		for _, v := range vs {
+			ss += (v - mean) * (v - mean)
+		}
+		width = 3.49 * math.Sqrt(ss/n) * math.Cbrt(1/n)
+	case FreedmanDiaconisRule:
+		iqr := median(vs[len(vs)/2:]) - median(vs[:len(vs)/2])
+		width = 2 * iqr * math.Cbrt(1/n)
+	default:
+		return int(math.Ceil(math.Sqrt(n)))
+	}
+	if width == 0 {
+		return 1
+	}
+	return int(math.Min(math.Ceil((vs[len(vs)-1]-vs[0])/width), n))
+}
+
+// Histogram2D implements the Plotter interface, drawing
+// a two-dimensional histogram of the points of an XYer,
+// counted in rectangular bins and drawn as a HeatMap.
+//
+// Histogram2D implements the GridXYZ interface, with the
+// centers of the bins and their counts, so that a Contour
+// of the counts can be drawn over the histogram.
+type Histogram2D struct {
+	// XEdges and YEdges are the increasing edges of the bins.
+	// The bins of the column c span [XEdges[c], XEdges[c+1]),
+	// and the last bins of the axes include their upper edges.
+	XEdges, YEdges []float64
+
+	// Counts holds the counts of the points in the bins,
+	// or their densities once normalized. The bin of the
+	// column c and row r is at Counts[r*(len(XEdges)-1)+c].
+	Counts []float64
+
+	// Palette is the color palette used to render
+	// the histogram. Palette must not be nil or
+	// return a zero length []color.Color.
+	Palette palette.Palette
+
+	// Underflow and Overflow are colors used to fill
+	// the bins outside the dynamic range defined by
+	// Min and Max.
+	Underflow color.Color
+	Overflow  color.Color
+
+	// NaN is the color used to fill the bins that do not
+	// map to a unique palette color.
+	NaN color.Color
+
+	// Min and Max define the dynamic range of the
+	// histogram.
+	Min, Max float64
+
+	// Rasterized indicates whether the histogram
+	// should be produced using raster-based drawing.
+	// The rasterized histogram draws its bins with
+	// equal sizes, and so is suited to bins of equal
+	// widths only.
+	Rasterized bool
+}
+
+// NewHistogram2D returns a two-dimensional histogram of the
+// points of xys, binned along x and y as specified by xbins
+// and ybins, and colored with p. The dynamic range of the
+// histogram is set to the range of the counts.
+//
+// If xys is MissingXYs, the points holding a NaN are skipped.
+// NewHistogram2D returns an error if xys has no points or
+// holds an invalid value, or if the bins are invalid.
+func NewHistogram2D(xys XYer, xbins, ybins Bins, p palette.Palette) (*Histogram2D, error) {
+	_, skip := xys.(MissingXYs)
+	var xs, ys []float64
+	for i := 0; i < xys.Len(); i++ {
+		x, y := xys.XY(i)
+		if skip && (math.IsNaN(x) || math.IsNaN(y)) {
+			continue
+		}
+		if err := CheckFloats(x, y); err != nil {
+			return nil, err
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+	if len(xs) == 0 {
+		return nil, ErrNoData
+	}
+
+	h := &Histogram2D{Palette: p}
+	var err error
+	if h.XEdges, err = xbins.edges(append([]float64(nil), xs...)); err != nil {
+		return nil, err
+	}
+	if h.YEdges, err = ybins.edges(append([]float64(nil), ys...)); err != nil {
+		return nil, err
+	}
+
+	cols, rows := h.Dims()
+	h.Counts = make([]float64, cols*rows)
+	for i, x := range xs {
+		c, r := bin(h.XEdges, x), bin(h.YEdges, ys[i])
+		if c < 0 || r < 0 {
+			continue
+		}
+		h.Counts[r*cols+c]++
+	}
+	h.Min, h.Max = h.countRange()
+	return h, nil
+}
+
+// bin returns the index of the bin of v within
+// the edges, or -1 if v is outside of the edges.
+func bin(edges []float64, v float64) int {
+	n := len(edges) - 1
+	switch {
+	case v < edges[0] || v > edges[n]:
+		return -1
+	case v == edges[n]:
+		return n - 1
+	}
+	return sort.Search(n, func(i int) bool { return edges[i+1] > v })
+}
+
+// countRange returns the minimum and maximum counts of h.
+func (h *Histogram2D) countRange() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, v := range h.Counts {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return min, max
+}
+
+// Normalize normalizes the histogram so that the
+// total volume beneath it sums to a given value.
+// Normalize(1) gives the probability density of
+// the points. The dynamic range of the histogram
+// is set to the range of the normalized counts.
+// Normalize does nothing if all counts are zero.
+func (h *Histogram2D) Normalize(sum float64) {
+	cols, rows := h.Dims()
+	mass := 0.0
+	for _, v := range h.Counts {
+		mass += v
+	}
+	if mass == 0 {
+		return
+	}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			area := (h.XEdges[c+1] - h.XEdges[c]) * (h.YEdges[r+1] - h.YEdges[r])
+			h.Counts[r*cols+c] *= sum / (area * mass)
+		}
+	}
+	h.Min, h.Max = h.countRange()
+}
+
+// Dims implements the Dims method of the GridXYZ interface.
+func (h *Histogram2D) Dims() (c, r int) {
+	return len(h.XEdges) - 1, len(h.YEdges) - 1
+}
+
+// Z implements the Z method of the GridXYZ interface.
+func (h *Histogram2D) Z(c, r int) float64 {
+	cols, rows := h.Dims()
+	if c < 0 || c >= cols || r < 0 || r >= rows {
+		panic("plotter: index out of range")
+	}
+	return h.Counts[r*cols+c]
+}
+
+// X implements the X method of the GridXYZ interface,
+// returning the center of the bins of the column c.
+func (h *Histogram2D) X(c int) float64 {
+	return (h.XEdges[c] + h.XEdges[c+1]) / 2
+}
+
+// Y implements the Y method of the GridXYZ interface,
+// returning the center of the bins of the row r.
+func (h *Histogram2D) Y(r int) float64 {
+	return (h.YEdges[r] + h.YEdges[r+1]) / 2
+}
+
+func (h *Histogram2D) cellX(c int) (min, max float64) {
+	return h.XEdges[c], h.XEdges[c+1]
+}
+
+func (h *Histogram2D) cellY(r int) (min, max float64) {
+	return h.YEdges[r], h.YEdges[r+1]
+}
+
+// heatMap returns the HeatMap drawing h.
+func (h *Histogram2D) heatMap() *HeatMap {
+	return &HeatMap{
+		GridXYZ:    h,
+		Palette:    h.Palette,
+		Underflow:  h.Underflow,
+		Overflow:   h.Overflow,
+		NaN:        h.NaN,
+		Min:        h.Min,
+		Max:        h.Max,
+		Rasterized: h.Rasterized,
+	}
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (h *Histogram2D) Plot(c draw.Canvas, plt *plot.Plot) {
+	_ = h.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext implements the PlotContext method of the
+// plot.ContextPlotter interface. The context is checked
+// for cancellation before each column of the bins.
+func (h *Histogram2D) PlotContext(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
+	return h.heatMap().PlotContext(ctx, c, plt)
+}
+
+// DataRange implements the DataRange method
+// of the plot.DataRanger interface.
+func (h *Histogram2D) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return h.XEdges[0], h.XEdges[len(h.XEdges)-1], h.YEdges[0], h.YEdges[len(h.YEdges)-1]
+}
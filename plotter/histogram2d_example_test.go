@@ -0,0 +1,53 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"log"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+)
+
+// ExampleHistogram2D draws the density of correlated normal
+// points, with contours of the density drawn over it.
+func ExampleHistogram2D() {
+	rnd := rand.New(rand.NewSource(1))
+
+	const n = 10000
+	pts := make(plotter.XYs, n)
+	for i := range pts {
+		x := rnd.NormFloat64()
+		pts[i] = plotter.XY{X: x, Y: 0.6*x + 0.8*rnd.NormFloat64()}
+	}
+
+	h, err := plotter.NewHistogram2D(pts,
+		plotter.Bins{Rule: plotter.FreedmanDiaconisRule},
+		plotter.Bins{Edges: []float64{-4, -3, -2, -1.5, -1, -0.5, 0, 0.5, 1, 1.5, 2, 3, 4}},
+		palette.Heat(32, 1),
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	h.Normalize(1)
+
+	levels := []float64{0.02, 0.06, 0.12, 0.18}
+	c := plotter.NewContour(h, levels, palette.Rainbow(len(levels), palette.Blue, palette.Red, 1, 1, 1))
+
+	p := plot.New()
+	p.Title.Text = "Histogram2D"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+	p.Add(h, c)
+
+	err = p.Save(10*vg.Centimeter, 10*vg.Centimeter, "testdata/histogram2d.png")
+	if err != nil {
+		log.Panic(err)
+	}
+}
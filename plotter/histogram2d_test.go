@@ -0,0 +1,178 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"image"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/palette"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/recorder"
+)
+
+func TestHistogram2D(t *testing.T) {
+	cmpimg.CheckPlot(ExampleHistogram2D, t, "histogram2d.png")
+}
+
+func TestHistogram2DCounts(t *testing.T) {
+	pts := plotter.XYs{
+		{X: 0, Y: 0},
+		{X: 0.5, Y: 0.5},
+		{X: 1, Y: 0.2},
+		{X: 4, Y: 3},
+		{X: 2.5, Y: 1},
+		{X: 5, Y: 4}, // Outside of the y edges.
+	}
+	h, err := plotter.NewHistogram2D(pts,
+		plotter.Bins{N: 2},
+		plotter.Bins{Edges: []float64{0, 1, 3}},
+		palette.Heat(4, 1),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []float64{0, 2.5, 5}; !reflect.DeepEqual(h.XEdges, want) {
+		t.Errorf("unexpected x edges: got:%v want:%v", h.XEdges, want)
+	}
+	if c, r := h.Dims(); c != 2 || r != 2 {
+		t.Errorf("unexpected dimensions: got:%dx%d want:2x2", c, r)
+	}
+	want := []float64{
+		3, 0,
+		0, 2,
+	}
+	if !reflect.DeepEqual(h.Counts, want) {
+		t.Errorf("unexpected counts: got:%v want:%v", h.Counts, want)
+	}
+	if h.Z(1, 1) != 2 || h.X(1) != 3.75 || h.Y(1) != 2 {
+		t.Errorf("unexpected grid values: got:Z=%v X=%v Y=%v want:Z=2 X=3.75 Y=2", h.Z(1, 1), h.X(1), h.Y(1))
+	}
+	if h.Min != 0 || h.Max != 3 {
+		t.Errorf("unexpected range: got:[%v, %v] want:[0, 3]", h.Min, h.Max)
+	}
+
+	h.Normalize(1)
+	var volume float64
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			volume += h.Z(c, r) * (h.XEdges[c+1] - h.XEdges[c]) * (h.YEdges[r+1] - h.YEdges[r])
+		}
+	}
+	if math.Abs(volume-1) > 1e-12 {
+		t.Errorf("unexpected volume after normalization: got:%v want:1", volume)
+	}
+	if h.Max != h.Z(0, 0) {
+		t.Errorf("unexpected range after normalization: got:%v want:%v", h.Max, h.Z(0, 0))
+	}
+
+	xmin, xmax, ymin, ymax := h.DataRange()
+	if xmin != 0 || xmax != 5 || ymin != 0 || ymax != 3 {
+		t.Errorf("unexpected data range: got:[%v, %v]×[%v, %v]", xmin, xmax, ymin, ymax)
+	}
+}
+
+func TestHistogram2DRasterized(t *testing.T) {
+	pts := plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}
+	h, err := plotter.NewHistogram2D(pts, plotter.Bins{N: 3}, plotter.Bins{N: 2}, palette.Heat(4, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Rasterized = true
+
+	p := plot.New()
+	p.Add(h)
+	var c recorder.Canvas
+	h.Plot(draw.NewCanvas(&c, 10, 10), p)
+
+	if len(c.Actions) != 1 {
+		t.Fatalf("unexpected number of actions: got:%d want:1", len(c.Actions))
+	}
+	img := c.Actions[0].(*recorder.DrawImage).Image
+	if b := img.Bounds(); b != image.Rect(0, 0, 3, 2) {
+		t.Errorf("unexpected image bounds: got:%v want:%v", b, image.Rect(0, 0, 3, 2))
+	}
+}
+
+func TestHistogram2DRules(t *testing.T) {
+	pts := make(plotter.XYs, 100)
+	for i := range pts {
+		pts[i] = plotter.XY{X: float64(i), Y: float64(i % 10)}
+	}
+	for _, test := range []struct {
+		rule plotter.BinRule
+		want int
+	}{
+		{rule: plotter.SqrtRule, want: 10},
+		{rule: plotter.SturgesRule, want: 8},
+		{rule: plotter.ScottRule, want: 5},
+		{rule: plotter.FreedmanDiaconisRule, want: 5},
+	} {
+		h, err := plotter.NewHistogram2D(pts, plotter.Bins{Rule: test.rule}, plotter.Bins{N: 1}, palette.Heat(4, 1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c, _ := h.Dims(); c != test.want {
+			t.Errorf("unexpected number of bins for rule %d: got:%d want:%d", test.rule, c, test.want)
+		}
+	}
+
+	// An outlier does not make the rules based on the
+	// spread of the values choose a huge number of bins.
+	outlier := append(pts[:len(pts):len(pts)], plotter.XY{X: 1e12, Y: 0})
+	for _, rule := range []plotter.BinRule{plotter.ScottRule, plotter.FreedmanDiaconisRule} {
+		h, err := plotter.NewHistogram2D(outlier, plotter.Bins{Rule: rule}, plotter.Bins{N: 1}, palette.Heat(4, 1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c, _ := h.Dims(); c > len(outlier) {
+			t.Errorf("unexpected number of bins for rule %d: got:%d want at most:%d", rule, c, len(outlier))
+		}
+	}
+}
+
+func TestHistogram2DNormalizeEmpty(t *testing.T) {
+	pts := plotter.XYs{{X: 5, Y: 5}}
+	edges := plotter.Bins{Edges: []float64{0, 1, 2}}
+	h, err := plotter.NewHistogram2D(pts, edges, edges, palette.Heat(4, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Normalize(1)
+	for i, v := range h.Counts {
+		if v != 0 {
+			t.Errorf("unexpected count of bin %d: got:%g want:0", i, v)
+		}
+	}
+}
+
+func TestHistogram2DErrors(t *testing.T) {
+	pal := palette.Heat(4, 1)
+	pts := plotter.XYs{{X: 1, Y: 1}}
+	if _, err := plotter.NewHistogram2D(plotter.XYs{}, plotter.Bins{}, plotter.Bins{}, pal); err != plotter.ErrNoData {
+		t.Errorf("unexpected error for no data: got:%v want:%v", err, plotter.ErrNoData)
+	}
+	if _, err := plotter.NewHistogram2D(pts, plotter.Bins{Edges: []float64{1}}, plotter.Bins{}, pal); err == nil {
+		t.Error("expected error for a single edge")
+	}
+	if _, err := plotter.NewHistogram2D(pts, plotter.Bins{}, plotter.Bins{Edges: []float64{0, 2, 1}}, pal); err == nil {
+		t.Error("expected error for decreasing edges")
+	}
+	if _, err := plotter.NewHistogram2D(plotter.XYs{{X: math.NaN(), Y: 1}}, plotter.Bins{}, plotter.Bins{}, pal); err == nil {
+		t.Error("expected error for NaN point")
+	}
+	h, err := plotter.NewHistogram2D(plotter.MissingXYs{XYer: plotter.XYs{{X: math.NaN(), Y: 1}, {X: 1, Y: 1}}}, plotter.Bins{}, plotter.Bins{}, pal)
+	if err != nil {
+		t.Fatalf("unexpected error for missing point: %v", err)
+	}
+	if !reflect.DeepEqual(h.Counts, []float64{1}) {
+		t.Errorf("unexpected counts: got:%v want:[1]", h.Counts)
+	}
+}
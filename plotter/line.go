@@ -38,11 +38,19 @@ type Line struct {
 	// StepStyle is the kind of the step line.
 	StepStyle StepKind
 
+	// Downsample is the downsampling of the points of
+	// the line to the resolution of the data canvas,
+	// for the lines with many more points than the
+	// canvas has columns.
+	Downsample DownsampleKind
+
 	// LineStyle is the style of the line connecting the points.
 	// Use zero width to disable lines.
 	draw.LineStyle
 
 	// FillColor is the color to fill the area below the plot.
+	// It may be a vg.Gradient, relative to the filled area,
+	// or a vg.Hatch pattern.
 	// Use nil to disable the filling. This is the default.
 	FillColor color.Color
 }
@@ -50,7 +58,7 @@ type Line struct {
 // NewLine returns a Line that uses the default line style and
 // does not draw glyphs.
 func NewLine(xys XYer) (*Line, error) {
-	data, err := CopyXYs(xys)
+	data, err := copyMissingXYs(xys)
 	if err != nil {
 		return nil, err
 	}
@@ -61,17 +69,24 @@ func NewLine(xys XYer) (*Line, error) {
 }
 
 // Plot draws the Line, implementing the plot.Plotter interface.
+// The line and its fill are broken at the missing points.
 func (pts *Line) Plot(c draw.Canvas, plt *plot.Plot) {
 	trX, trY := plt.Transforms(&c)
-	ps := make([]vg.Point, len(pts.XYs))
-
-	for i, p := range pts.XYs {
-		ps[i].X = trX(p.X)
-		ps[i].Y = trY(p.Y)
+	for _, seg := range segments(pts.XYs) {
+		ps := make([]vg.Point, len(seg))
+		for i, p := range seg {
+			ps[i].X = trX(p.X)
+			ps[i].Y = trY(p.Y)
+		}
+		ps = downsample(pts.Downsample, c, ps)
+		pts.plot(c, ps, trY(plt.Y.Min))
 	}
+}
 
+// plot draws the consecutive points ps of the Line,
+// filling the area above minY.
+func (pts *Line) plot(c draw.Canvas, ps []vg.Point, minY vg.Length) {
 	if pts.FillColor != nil && len(ps) > 0 {
-		minY := trY(plt.Y.Min)
 		fillPoly := []vg.Point{{X: ps[0].X, Y: minY}}
 		switch pts.StepStyle {
 		case PreStep:
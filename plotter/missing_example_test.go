@@ -0,0 +1,55 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+)
+
+// ExampleMissingXYs draws sensor readings with missing
+// samples, which are drawn as gaps in the line.
+func ExampleMissingXYs() {
+	nan := math.NaN()
+	readings := plotter.XYs{
+		{X: 0, Y: 2}, {X: 1, Y: 3}, {X: 2, Y: 2.5}, {X: 3, Y: nan},
+		{X: 4, Y: nan}, {X: 5, Y: 4}, {X: 6, Y: 3.5}, {X: 7, Y: 5},
+		{X: 8, Y: nan}, {X: 9, Y: 4.5}, {X: 10, Y: 3}, {X: 11, Y: 3.5},
+	}
+
+	p := plot.New()
+	p.Title.Text = "Missing data"
+	p.X.Label.Text = "Time"
+	p.Y.Label.Text = "Reading"
+	p.Add(plotter.NewGrid())
+
+	counts, err := plotter.NewBarChart(plotter.MissingValues{Valuer: plotter.Values{
+		1, 1.5, 1, nan, nan, 2, 1.5, 2.5, nan, 2, 1.5, 1,
+	}}, vg.Points(10))
+	if err != nil {
+		log.Panic(err)
+	}
+	counts.Color = color.RGBA{R: 196, G: 196, B: 255, A: 255}
+	counts.LineStyle.Width = 0
+
+	line, points, err := plotter.NewLinePoints(plotter.MissingXYs{XYer: readings})
+	if err != nil {
+		log.Panic(err)
+	}
+	line.FillColor = color.NRGBA{R: 128, G: 196, B: 128, A: 128}
+	line.LineStyle.Width = vg.Points(1)
+	points.Radius = vg.Points(3)
+	p.Add(counts, line, points)
+
+	err = p.Save(10*vg.Centimeter, 5*vg.Centimeter, "testdata/missing.png")
+	if err != nil {
+		log.Panic(err)
+	}
+}
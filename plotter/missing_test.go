@@ -0,0 +1,77 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package plotter_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/plotter"
+)
+
+func TestMissing(t *testing.T) {
+	cmpimg.CheckPlot(ExampleMissingXYs, t, "missing.png")
+}
+
+func TestMissingData(t *testing.T) {
+	nan := math.NaN()
+	xys := plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: nan}, {X: 2, Y: 3}}
+
+	_, err := plotter.NewLine(xys)
+	if err != plotter.ErrNaN {
+		t.Errorf("unexpected error for NaN data: got:%v want:%v", err, plotter.ErrNaN)
+	}
+	l, err := plotter.NewLine(plotter.MissingXYs{XYer: xys})
+	if err != nil {
+		t.Fatalf("unexpected error for missing data: %v", err)
+	}
+	xmin, xmax, ymin, ymax := l.DataRange()
+	if xmin != 0 || xmax != 2 || ymin != 1 || ymax != 3 {
+		t.Errorf("unexpected data range: got:[%g, %g]×[%g, %g] want:[0, 2]×[1, 3]", xmin, xmax, ymin, ymax)
+	}
+	_, err = plotter.NewScatter(plotter.MissingXYs{XYer: plotter.XYs{{X: math.Inf(1), Y: 0}}})
+	if err != plotter.ErrInfinity {
+		t.Errorf("unexpected error for infinite data: got:%v want:%v", err, plotter.ErrInfinity)
+	}
+
+	b, err := plotter.NewBarChart(plotter.MissingValues{Valuer: plotter.Values{1, nan, 2}}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error for missing values: %v", err)
+	}
+	_, _, ymin, ymax = b.DataRange()
+	if ymin != 0 || ymax != 2 {
+		t.Errorf("unexpected bar chart range: got:[%g, %g] want:[0, 2]", ymin, ymax)
+	}
+	if ht := b.BarHeight(1); ht != 0 {
+		t.Errorf("unexpected height of missing bar: got:%g want:0", ht)
+	}
+
+	// Only the plotters handling missing data accept it.
+	_, err = plotter.NewBoxPlot(10, 0, plotter.MissingValues{Valuer: plotter.Values{1, nan, 2}})
+	if err != plotter.ErrNaN {
+		t.Errorf("unexpected error for missing box plot values: got:%v want:%v", err, plotter.ErrNaN)
+	}
+	_, err = plotter.NewPolygon(plotter.MissingXYs{XYer: xys})
+	if err != plotter.ErrNaN {
+		t.Errorf("unexpected error for missing polygon points: got:%v want:%v", err, plotter.ErrNaN)
+	}
+
+	_, err = plotter.NewHist(plotter.Values{1, nan, 2, 2}, 2)
+	if err != plotter.ErrNaN {
+		t.Errorf("unexpected error for NaN histogram values: got:%v want:%v", err, plotter.ErrNaN)
+	}
+	_, err = plotter.NewHist(plotter.MissingValues{Valuer: plotter.Values{nan, nan}}, 2)
+	if err != plotter.ErrNoData {
+		t.Errorf("unexpected error for missing histogram values: got:%v want:%v", err, plotter.ErrNoData)
+	}
+	h, err := plotter.NewHist(plotter.MissingValues{Valuer: plotter.Values{1, nan, 2, 2}}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error for histogram: %v", err)
+	}
+	if len(h.Bins) != 2 || h.Bins[0].Weight != 1 || h.Bins[1].Weight != 2 {
+		t.Errorf("unexpected histogram bins: %v", h.Bins)
+	}
+}
@@ -11,7 +11,11 @@
 //
 // New* functions return an error if the data contains Inf, NaN, or is
 // empty. Some of the New* functions return other plotter-specific errors
-// too.
+// too. The NaN values of data wrapped in MissingValues or MissingXYs
+// are accepted as missing data by NewLine, NewScatter, NewLinePoints,
+// NewBarChart, NewHistogram, NewHist, NewHexBin and NewHistogram2D:
+// Line draws them as gaps, and the other plotters skip them. The other
+// New* functions return ErrNaN for missing data.
 package plotter // import "github.com/emptywe/plot/plotter"
 
 import (
@@ -42,6 +46,11 @@ var (
 	}
 )
 
+// cancelCheck is the number of points, cells or paths
+// drawn by plotters between the checks for the
+// cancellation of their context.
+const cancelCheck = 1024
+
 // Valuer wraps the Len and Value methods.
 type Valuer interface {
 	// Len returns the number of values.
@@ -51,12 +60,16 @@ type Valuer interface {
 	Value(int) float64
 }
 
-// Range returns the minimum and maximum values.
+// Range returns the minimum and maximum values,
+// ignoring the NaN values.
 func Range(vs Valuer) (min, max float64) {
 	min = math.Inf(1)
 	max = math.Inf(-1)
 	for i := 0; i < vs.Len(); i++ {
 		v := vs.Value(i)
+		if math.IsNaN(v) {
+			continue
+		}
 		min = math.Min(min, v)
 		max = math.Max(max, v)
 	}
@@ -85,6 +98,17 @@ func CheckFloats(fs ...float64) error {
 	return nil
 }
 
+// checkMissing is like CheckFloats, but accepts
+// the NaN values, which are missing data.
+func checkMissing(fs ...float64) error {
+	for _, f := range fs {
+		if math.IsInf(f, 0) {
+			return ErrInfinity
+		}
+	}
+	return nil
+}
+
 // CopyValues returns a Values that is a copy of the values
 // from a Valuer, or an error if there are no values, or if one of
 // the copied values is a NaN or Infinity.
@@ -102,6 +126,25 @@ func CopyValues(vs Valuer) (Values, error) {
 	return cpy, nil
 }
 
+// copyMissingValues is like CopyValues, but copies
+// the NaN values if vs is a MissingValues.
+func copyMissingValues(vs Valuer) (Values, error) {
+	if _, ok := vs.(MissingValues); !ok {
+		return CopyValues(vs)
+	}
+	if vs.Len() == 0 {
+		return nil, ErrNoData
+	}
+	cpy := make(Values, vs.Len())
+	for i := 0; i < vs.Len(); i++ {
+		cpy[i] = vs.Value(i)
+		if err := checkMissing(cpy[i]); err != nil {
+			return nil, err
+		}
+	}
+	return cpy, nil
+}
+
 func (vs Values) Len() int {
 	return len(vs)
 }
@@ -110,6 +153,12 @@ func (vs Values) Value(i int) float64 {
 	return vs[i]
 }
 
+// MissingValues implements the Valuer interface,
+// marking the NaN values of a Valuer as missing data.
+type MissingValues struct {
+	Valuer
+}
+
 // XYer wraps the Len and XY methods.
 type XYer interface {
 	// Len returns the number of x, y pairs.
@@ -120,10 +169,21 @@ type XYer interface {
 }
 
 // XYRange returns the minimum and maximum
-// x and y values.
+// x and y values, ignoring the pairs holding
+// a NaN value.
 func XYRange(xys XYer) (xmin, xmax, ymin, ymax float64) {
-	xmin, xmax = Range(XValues{xys})
-	ymin, ymax = Range(YValues{xys})
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+	for i := 0; i < xys.Len(); i++ {
+		x, y := xys.XY(i)
+		if math.IsNaN(x) || math.IsNaN(y) {
+			continue
+		}
+		xmin = math.Min(xmin, x)
+		xmax = math.Max(xmax, x)
+		ymin = math.Min(ymin, y)
+		ymax = math.Max(ymax, y)
+	}
 	return
 }
 
@@ -147,6 +207,22 @@ func CopyXYs(data XYer) (XYs, error) {
 	return cpy, nil
 }
 
+// copyMissingXYs is like CopyXYs, but copies the
+// NaN values if data is a MissingXYs.
+func copyMissingXYs(data XYer) (XYs, error) {
+	if _, ok := data.(MissingXYs); !ok {
+		return CopyXYs(data)
+	}
+	cpy := make(XYs, data.Len())
+	for i := range cpy {
+		cpy[i].X, cpy[i].Y = data.XY(i)
+		if err := checkMissing(cpy[i].X, cpy[i].Y); err != nil {
+			return nil, err
+		}
+	}
+	return cpy, nil
+}
+
 func (xys XYs) Len() int {
 	return len(xys)
 }
@@ -155,6 +231,38 @@ func (xys XYs) XY(i int) (float64, float64) {
 	return xys[i].X, xys[i].Y
 }
 
+// MissingXYs implements the XYer interface, marking
+// the points of an XYer holding a NaN value as missing
+// data.
+type MissingXYs struct {
+	XYer
+}
+
+// missing returns whether the point is missing data.
+func (p XY) missing() bool {
+	return math.IsNaN(p.X) || math.IsNaN(p.Y)
+}
+
+// segments returns the runs of consecutive points of
+// xys that are not missing data.
+func segments(xys XYs) []XYs {
+	var segs []XYs
+	start := 0
+	for i, p := range xys {
+		if !p.missing() {
+			continue
+		}
+		if i > start {
+			segs = append(segs, xys[start:i])
+		}
+		start = i + 1
+	}
+	if start < len(xys) {
+		segs = append(segs, xys[start:])
+	}
+	return segs
+}
+
 // XValues implements the Valuer interface,
 // returning the x value from an XYer.
 type XValues struct {
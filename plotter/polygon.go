@@ -25,6 +25,8 @@ type Polygon struct {
 	draw.LineStyle
 
 	// Color is the fill color of the polygon.
+	// It may be a vg.Gradient, relative to the polygon,
+	// or a vg.Hatch pattern.
 	Color color.Color
 }
 
@@ -185,13 +185,8 @@ func NewSankey(flows ...Flow) (*Sankey, error) {
 	}
 	s.StockBarWidth = s.TextStyle.FontExtents().Height * 1.15
 
-	s.FlowStyle = func(_ string) (color.Color, draw.LineStyle) {
-		return s.Color, s.LineStyle
-	}
-
-	s.StockStyle = func(label string, category int) (string, text.Style, vg.Length, vg.Length, color.Color, draw.LineStyle) {
-		return label, s.TextStyle, 0, 0, s.Color, s.LineStyle
-	}
+	s.FlowStyle = s.defaultFlowStyle
+	s.StockStyle = s.defaultStockStyle
 
 	stocks := s.stockList()
 	s.setStockRange(&stocks)
@@ -199,6 +194,16 @@ func NewSankey(flows ...Flow) (*Sankey, error) {
 	return &s, nil
 }
 
+// defaultFlowStyle is the default FlowStyle of a Sankey.
+func (s *Sankey) defaultFlowStyle(_ string) (color.Color, draw.LineStyle) {
+	return s.Color, s.LineStyle
+}
+
+// defaultStockStyle is the default StockStyle of a Sankey.
+func (s *Sankey) defaultStockStyle(label string, category int) (string, text.Style, vg.Length, vg.Length, color.Color, draw.LineStyle) {
+	return label, s.TextStyle, 0, 0, s.Color, s.LineStyle
+}
+
 // Plot implements the plot.Plotter interface.
 func (s *Sankey) Plot(c draw.Canvas, plt *plot.Plot) {
 	trCat, trVal := plt.Transforms(&c)
@@ -5,6 +5,8 @@
 package plotter
 
 import (
+	"context"
+
 	"github.com/emptywe/plot"
 	"github.com/emptywe/plot/vg"
 	"github.com/emptywe/plot/vg/draw"
@@ -28,7 +30,7 @@ type Scatter struct {
 // NewScatter returns a Scatter that uses the
 // default glyph style.
 func NewScatter(xys XYer) (*Scatter, error) {
-	data, err := CopyXYs(xys)
+	data, err := copyMissingXYs(xys)
 	if err != nil {
 		return nil, err
 	}
@@ -41,14 +43,29 @@ func NewScatter(xys XYer) (*Scatter, error) {
 // Plot draws the Scatter, implementing the plot.Plotter
 // interface.
 func (pts *Scatter) Plot(c draw.Canvas, plt *plot.Plot) {
+	_ = pts.PlotContext(context.Background(), c, plt)
+}
+
+// PlotContext draws the Scatter like Plot, implementing the
+// plot.ContextPlotter interface. The missing points are skipped.
+func (pts *Scatter) PlotContext(ctx context.Context, c draw.Canvas, plt *plot.Plot) error {
 	trX, trY := plt.Transforms(&c)
 	glyph := func(i int) draw.GlyphStyle { return pts.GlyphStyle }
 	if pts.GlyphStyleFunc != nil {
 		glyph = pts.GlyphStyleFunc
 	}
 	for i, p := range pts.XYs {
+		if i%cancelCheck == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if p.missing() {
+			continue
+		}
 		c.DrawGlyph(glyph(i), vg.Point{X: trX(p.X), Y: trY(p.Y)})
 	}
+	return nil
 }
 
 // DataRange returns the minimum and maximum
@@ -65,15 +82,20 @@ func (pts *Scatter) GlyphBoxes(plt *plot.Plot) []plot.GlyphBox {
 	if pts.GlyphStyleFunc != nil {
 		glyph = pts.GlyphStyleFunc
 	}
-	bs := make([]plot.GlyphBox, len(pts.XYs))
+	bs := make([]plot.GlyphBox, 0, len(pts.XYs))
 	for i, p := range pts.XYs {
-		bs[i].X = plt.X.Norm(p.X)
-		bs[i].Y = plt.Y.Norm(p.Y)
-		r := glyph(i).Radius
-		bs[i].Rectangle = vg.Rectangle{
-			Min: vg.Point{X: -r, Y: -r},
-			Max: vg.Point{X: +r, Y: +r},
+		if p.missing() {
+			continue
 		}
+		r := glyph(i).Radius
+		bs = append(bs, plot.GlyphBox{
+			X: plt.X.Norm(p.X),
+			Y: plt.Y.Norm(p.Y),
+			Rectangle: vg.Rectangle{
+				Min: vg.Point{X: -r, Y: -r},
+				Max: vg.Point{X: +r, Y: +r},
+			},
+		})
 	}
 	return bs
 }
@@ -5,11 +5,42 @@
 package plotter_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/emptywe/plot"
 	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/recorder"
 )
 
 func TestScatter(t *testing.T) {
 	cmpimg.CheckPlot(ExampleScatter, t, "scatter.png")
 }
+
+func TestScatterPlotContext(t *testing.T) {
+	pts := make(plotter.XYs, 5000)
+	for i := range pts {
+		pts[i] = plotter.XY{X: float64(i), Y: float64(i % 7)}
+	}
+	s, err := plotter.NewScatter(pts)
+	if err != nil {
+		t.Fatalf("could not create scatter: %+v", err)
+	}
+	p := plot.New()
+	p.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := &cancelCanvas{Canvas: new(recorder.Canvas), after: 10, cancel: cancel}
+	err = s.PlotContext(ctx, draw.Canvas{Canvas: c, Rectangle: vg.Rectangle{Max: vg.Point{X: 100, Y: 100}}}, p)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.Canceled)
+	}
+	if c.n >= len(pts) {
+		t.Errorf("unexpected number of glyphs drawn: got:%d want:<%d", c.n, len(pts))
+	}
+}
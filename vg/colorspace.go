@@ -0,0 +1,76 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// ColorSpace is the color space in which a canvas
+// writes colors, for canvases producing documents
+// intended for print.
+type ColorSpace int
+
+const (
+	// DeviceRGB writes colors as red, green and
+	// blue components.
+	DeviceRGB ColorSpace = iota
+
+	// DeviceCMYK writes colors as cyan, magenta,
+	// yellow and black components, for process
+	// color printing.
+	DeviceCMYK
+
+	// DeviceGray writes colors as gray levels,
+	// for black and white printing.
+	DeviceGray
+)
+
+// String returns the name of the color space.
+func (cs ColorSpace) String() string {
+	switch cs {
+	case DeviceRGB:
+		return "DeviceRGB"
+	case DeviceCMYK:
+		return "DeviceCMYK"
+	case DeviceGray:
+		return "DeviceGray"
+	}
+	return fmt.Sprintf("ColorSpace(%d)", int(cs))
+}
+
+// CMYKConverter converts colors to CMYK, for example using
+// the color profile of a printing press.
+// The opacity of colors is handled separately by canvases,
+// and should be ignored by the conversion.
+type CMYKConverter func(color.Color) color.CMYK
+
+// CMYK returns the color converted to CMYK.
+// color.CMYK values are returned unchanged. Other colors are
+// converted by f or, if f is nil, with the naive conversion
+// of the color.RGBToCMYK function.
+func (f CMYKConverter) CMYK(c color.Color) color.CMYK {
+	if c, ok := c.(color.CMYK); ok {
+		return c
+	}
+	if f != nil {
+		return f(c)
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	cc, m, y, k := color.RGBToCMYK(n.R, n.G, n.B)
+	return color.CMYK{C: cc, M: m, Y: y, K: k}
+}
+
+// GrayLevel returns the luminance of the color, between 0
+// for black and 1 for white. The opacity of the color is
+// ignored.
+func GrayLevel(c color.Color) float64 {
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	// The coefficients are the ones of color.GrayModel.
+	y := 0.299*float64(n.R) + 0.587*float64(n.G) + 0.114*float64(n.B)
+	return math.Min(1, y/math.MaxUint16)
+}
@@ -0,0 +1,53 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot/vg"
+)
+
+func TestCMYKConverter(t *testing.T) {
+	profile := func(color.Color) color.CMYK { return color.CMYK{C: 1, M: 2, Y: 3, K: 4} }
+	for _, tc := range []struct {
+		name string
+		conv vg.CMYKConverter
+		c    color.Color
+		want color.CMYK
+	}{
+		{name: "default-red", c: color.RGBA{R: 255, A: 255}, want: color.CMYK{M: 255, Y: 255}},
+		{name: "default-black", c: color.Black, want: color.CMYK{K: 255}},
+		{name: "default-transparent", c: color.NRGBA{B: 255, A: 128}, want: color.CMYK{C: 255, M: 255}},
+		{name: "default-cmyk", c: color.CMYK{C: 10, M: 20, Y: 30, K: 40}, want: color.CMYK{C: 10, M: 20, Y: 30, K: 40}},
+		{name: "profile", conv: profile, c: color.White, want: color.CMYK{C: 1, M: 2, Y: 3, K: 4}},
+		{name: "profile-cmyk", conv: profile, c: color.CMYK{K: 255}, want: color.CMYK{K: 255}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.conv.CMYK(tc.c); got != tc.want {
+				t.Errorf("invalid CMYK color: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGrayLevel(t *testing.T) {
+	for _, tc := range []struct {
+		c    color.Color
+		want float64
+	}{
+		{c: color.Black, want: 0},
+		{c: color.White, want: 1},
+		{c: color.RGBA{R: 255, A: 255}, want: 0.299},
+		{c: color.NRGBA{G: 255, A: 128}, want: 0.587},
+		{c: color.Gray{Y: 128}, want: 128.0 / 255},
+	} {
+		if got := vg.GrayLevel(tc.c); math.Abs(got-tc.want) > 1e-3 {
+			t.Errorf("invalid gray level of %v: got=%v, want=%v", tc.c, got, tc.want)
+		}
+	}
+}
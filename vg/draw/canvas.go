@@ -106,6 +106,19 @@ type LineStyle struct {
 
 	Dashes   []vg.Length
 	DashOffs vg.Length
+
+	// Cap is the shape of the ends of the line.
+	Cap vg.LineCap
+
+	// Join is the shape of the corners of the line.
+	Join vg.LineJoin
+
+	// MiterLimit is the limit on the ratio of the
+	// miter length to the width of the line, for
+	// vg.MiterJoin corners.
+	// If MiterLimit is zero, vg.DefaultMiterLimit
+	// is used.
+	MiterLimit float64
 }
 
 // A GlyphStyle specifies the look of a glyph used to draw
@@ -294,10 +307,12 @@ func New(c vg.CanvasSizer) Canvas {
 // more of the following packages:
 //
 //     github.com/emptywe/plot/vg/vgeps // provides eps
-//     github.com/emptywe/plot/vg/vgimg // provides png, jpg|jpeg, tif|tiff
+//     github.com/emptywe/plot/vg/vghtml // provides html
+//     github.com/emptywe/plot/vg/vgimg // provides png, jpg|jpeg, tif|tiff, sixel, kitty
 //     github.com/emptywe/plot/vg/vgpdf // provides pdf
 //     github.com/emptywe/plot/vg/vgsvg // provides svg
 //     github.com/emptywe/plot/vg/vgtex // provides tex
+//     github.com/emptywe/plot/vg/vgtxt // provides txt, ansi
 func NewFormattedCanvas(w, h vg.Length, format string) (vg.CanvasWriterTo, error) {
 	formats.RLock()
 	defer formats.RUnlock()
@@ -424,6 +439,61 @@ func (c *Canvas) SetLineStyle(sty LineStyle) {
 	c.SetColor(sty.Color)
 	c.SetLineWidth(sty.Width)
 	c.SetLineDash(sty.Dashes, sty.DashOffs)
+	limit := sty.MiterLimit
+	if limit == 0 {
+		limit = vg.DefaultMiterLimit
+	}
+	c.SetLineCap(sty.Cap)
+	c.SetLineJoin(sty.Join)
+	c.SetMiterLimit(limit)
+}
+
+// DrawGroup draws the group of drawing operations performed
+// by fn, if the underlying vg.Canvas implements vg.Grouper.
+// Otherwise fn is called with the underlying vg.Canvas, and
+// the opacity and blend mode of g are ignored.
+func (c Canvas) DrawGroup(g vg.Group, fn func(vg.Canvas)) {
+	if vc, ok := c.Canvas.(vg.Grouper); ok {
+		vc.DrawGroup(g, fn)
+		return
+	}
+	fn(c.Canvas)
+}
+
+// SetLineCap sets the line cap, if the underlying
+// vg.Canvas implements vg.LineStyler. Otherwise
+// SetLineCap is a no-op.
+func (c Canvas) SetLineCap(capStyle vg.LineCap) {
+	if vc, ok := c.Canvas.(vg.LineStyler); ok {
+		vc.SetLineCap(capStyle)
+	}
+}
+
+// SetLineJoin sets the line join, if the underlying
+// vg.Canvas implements vg.LineStyler. Otherwise
+// SetLineJoin is a no-op.
+func (c Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	if vc, ok := c.Canvas.(vg.LineStyler); ok {
+		vc.SetLineJoin(joinStyle)
+	}
+}
+
+// SetMiterLimit sets the miter limit, if the underlying
+// vg.Canvas implements vg.LineStyler. Otherwise
+// SetMiterLimit is a no-op.
+func (c Canvas) SetMiterLimit(limit float64) {
+	if vc, ok := c.Canvas.(vg.LineStyler); ok {
+		vc.SetMiterLimit(limit)
+	}
+}
+
+// Clip restricts subsequent drawing to the path, if the
+// underlying vg.Canvas implements vg.Clipper. Otherwise
+// Clip is a no-op.
+func (c Canvas) Clip(path vg.Path) {
+	if vc, ok := c.Canvas.(vg.Clipper); ok {
+		vc.Clip(path)
+	}
 }
 
 // StrokeLines draws a line connecting a set of points
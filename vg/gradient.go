@@ -0,0 +1,178 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Gradient is a color that varies over the area filled by
+// a call to Canvas.Fill.
+//
+// A Gradient is passed to a canvas with SetColor. Positions
+// within a gradient are given relative to the bounding box of
+// the filled path: (0, 0) is its bottom-left corner and (1, 1)
+// its top-right corner.
+// Canvases that can not fill with gradients, as well as
+// stroke and text operations, use the flat color returned by
+// the RGBA method of the gradient.
+type Gradient interface {
+	color.Color
+
+	// ColorAt returns the color of the gradient at the
+	// point (x, y), relative to the bounding box of the
+	// filled path.
+	ColorAt(x, y float64) color.Color
+}
+
+// GradientStop is a color stop of a gradient.
+type GradientStop struct {
+	// Offset is the position of the stop along the
+	// gradient, between 0 and 1.
+	Offset float64
+
+	// Color is the color of the gradient at Offset.
+	Color color.Color
+}
+
+// LinearGradient is a gradient that varies along the line
+// from (X1, Y1) to (X2, Y2).
+// Colors are padded with the first and last stop colors
+// before and after the line.
+type LinearGradient struct {
+	X1, Y1, X2, Y2 float64
+
+	// Stops are the color stops of the gradient,
+	// sorted by increasing offset.
+	Stops []GradientStop
+}
+
+// RGBA implements the color.Color interface, returning the
+// color in the middle of the gradient.
+func (g *LinearGradient) RGBA() (r, gr, b, a uint32) {
+	return stopColor(g.Stops, 0.5).RGBA()
+}
+
+// ColorAt implements the Gradient interface.
+func (g *LinearGradient) ColorAt(x, y float64) color.Color {
+	dx := g.X2 - g.X1
+	dy := g.Y2 - g.Y1
+	d := dx*dx + dy*dy
+	if d == 0 {
+		return stopColor(g.Stops, 1)
+	}
+	return stopColor(g.Stops, ((x-g.X1)*dx+(y-g.Y1)*dy)/d)
+}
+
+// RadialGradient is a gradient that varies from the center
+// (CX, CY) to the circle of radius R around it.
+// Colors are padded with the last stop color outside of
+// the circle.
+//
+// As positions are relative to the bounding box of the filled
+// path, the circle is stretched into an ellipse when the
+// bounding box is not square.
+type RadialGradient struct {
+	CX, CY, R float64
+
+	// Stops are the color stops of the gradient,
+	// sorted by increasing offset.
+	Stops []GradientStop
+}
+
+// RGBA implements the color.Color interface, returning the
+// color in the middle of the gradient.
+func (g *RadialGradient) RGBA() (r, gr, b, a uint32) {
+	return stopColor(g.Stops, 0.5).RGBA()
+}
+
+// ColorAt implements the Gradient interface.
+func (g *RadialGradient) ColorAt(x, y float64) color.Color {
+	if g.R <= 0 {
+		return stopColor(g.Stops, 1)
+	}
+	return stopColor(g.Stops, math.Hypot(x-g.CX, y-g.CY)/g.R)
+}
+
+// stopColor returns the color at offset t of the gradient
+// defined by the given stops.
+func stopColor(stops []GradientStop, t float64) color.Color {
+	switch {
+	case len(stops) == 0:
+		return color.Black
+	case t <= stops[0].Offset:
+		return stops[0].Color
+	case t >= stops[len(stops)-1].Offset:
+		return stops[len(stops)-1].Color
+	}
+	i := 1
+	for stops[i].Offset < t {
+		i++
+	}
+	s0, s1 := stops[i-1], stops[i]
+	if s1.Offset <= s0.Offset {
+		return s1.Color
+	}
+	f := (t - s0.Offset) / (s1.Offset - s0.Offset)
+	r0, g0, b0, a0 := s0.Color.RGBA()
+	r1, g1, b1, a1 := s1.Color.RGBA()
+	lerp := func(v0, v1 uint32) uint16 {
+		return uint16(math.Round(float64(v0) + f*(float64(v1)-float64(v0))))
+	}
+	return color.RGBA64{
+		R: lerp(r0, r1),
+		G: lerp(g0, g1),
+		B: lerp(b0, b1),
+		A: lerp(a0, a1),
+	}
+}
+
+// GradientImage returns a w×h image of the gradient over
+// the unit square. The bottom row of the image corresponds
+// to y=0 and the top row to y=1.
+func GradientImage(g Gradient, w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		y := 1 - (float64(j)+0.5)/float64(h)
+		for i := 0; i < w; i++ {
+			x := (float64(i) + 0.5) / float64(w)
+			img.Set(i, j, g.ColorAt(x, y))
+		}
+	}
+	return img
+}
+
+// FillGradient fills the path with the gradient by drawing a
+// rasterized image of the gradient clipped to the path.
+// It may be used by canvases that can not natively fill
+// paths with a gradient.
+func FillGradient(c Clipper, path Path, g Gradient) {
+	const (
+		pxPerPt = 2
+		maxPx   = 1024
+	)
+	size := func(l Length) int {
+		n := int(math.Ceil(l.Points() * pxPerPt))
+		switch {
+		case n < 1:
+			return 1
+		case n > maxPx:
+			return maxPx
+		}
+		return n
+	}
+
+	rect := path.Bounds()
+	sz := rect.Size()
+	if sz.X == 0 || sz.Y == 0 {
+		return
+	}
+	c.Push()
+	c.Clip(path)
+	c.DrawImage(rect, GradientImage(g, size(sz.X), size(sz.Y)))
+	c.Pop()
+}
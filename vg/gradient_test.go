@@ -0,0 +1,96 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot/vg"
+)
+
+func TestGradientColorAt(t *testing.T) {
+	stops := []vg.GradientStop{
+		{Offset: 0.25, Color: color.RGBA{R: 255, A: 255}},
+		{Offset: 0.75, Color: color.RGBA{B: 255, A: 255}},
+	}
+	mid := color.RGBA64{R: 0x8000, B: 0x8000, A: 0xffff}
+	for _, tc := range []struct {
+		name string
+		g    vg.Gradient
+		x, y float64
+		want color.Color
+	}{
+		{name: "linear-before", g: &vg.LinearGradient{X2: 1, Stops: stops}, x: 0, y: 0.5, want: stops[0].Color},
+		{name: "linear-mid", g: &vg.LinearGradient{X2: 1, Stops: stops}, x: 0.5, y: 0.2, want: mid},
+		{name: "linear-after", g: &vg.LinearGradient{X2: 1, Stops: stops}, x: 1, y: 0.9, want: stops[1].Color},
+		{name: "linear-vertical", g: &vg.LinearGradient{Y2: 1, Stops: stops}, x: 0.9, y: 0.5, want: mid},
+		{name: "radial-center", g: &vg.RadialGradient{CX: 0.5, CY: 0.5, R: 0.5, Stops: stops}, x: 0.5, y: 0.5, want: stops[0].Color},
+		{name: "radial-mid", g: &vg.RadialGradient{CX: 0.5, CY: 0.5, R: 0.5, Stops: stops}, x: 0.5, y: 0.75, want: mid},
+		{name: "radial-outside", g: &vg.RadialGradient{CX: 0.5, CY: 0.5, R: 0.5, Stops: stops}, x: 1, y: 1, want: stops[1].Color},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := color.RGBA64Model.Convert(tc.g.ColorAt(tc.x, tc.y)); got != color.RGBA64Model.Convert(tc.want) {
+				t.Errorf("invalid color at (%v, %v): got=%v, want=%v", tc.x, tc.y, got, tc.want)
+			}
+			r, g, b, a := tc.g.RGBA()
+			if got := (color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}); got != mid {
+				t.Errorf("invalid flat color: got=%v, want=%v", got, mid)
+			}
+		})
+	}
+}
+
+func TestPathBounds(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		path func(p *vg.Path)
+		want vg.Rectangle
+	}{
+		{
+			name: "empty",
+			path: func(p *vg.Path) {},
+		},
+		{
+			name: "lines",
+			path: func(p *vg.Path) {
+				p.Move(vg.Point{X: 1, Y: 2})
+				p.Line(vg.Point{X: -3, Y: 5})
+				p.Line(vg.Point{X: 4, Y: 0})
+				p.Close()
+			},
+			want: vg.Rectangle{Min: vg.Point{X: -3, Y: 0}, Max: vg.Point{X: 4, Y: 5}},
+		},
+		{
+			name: "quarter-arc",
+			path: func(p *vg.Path) {
+				p.Arc(vg.Point{X: 1, Y: 1}, 2, math.Pi/4, math.Pi/2)
+			},
+			want: vg.Rectangle{
+				Min: vg.Point{X: 1 - vg.Length(math.Sqrt2), Y: 1 + vg.Length(math.Sqrt2)},
+				Max: vg.Point{X: 1 + vg.Length(math.Sqrt2), Y: 3},
+			},
+		},
+		{
+			name: "circle",
+			path: func(p *vg.Path) {
+				p.Arc(vg.Point{X: 0, Y: 0}, 1, 0, -2*math.Pi)
+			},
+			want: vg.Rectangle{Min: vg.Point{X: -1, Y: -1}, Max: vg.Point{X: 1, Y: 1}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var p vg.Path
+			tc.path(&p)
+			got := p.Bounds()
+			const tol = 1e-12
+			if math.Abs(float64(got.Min.X-tc.want.Min.X)) > tol || math.Abs(float64(got.Min.Y-tc.want.Min.Y)) > tol ||
+				math.Abs(float64(got.Max.X-tc.want.Max.X)) > tol || math.Abs(float64(got.Max.Y-tc.want.Max.Y)) > tol {
+				t.Errorf("invalid bounds: got=%+v, want=%+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg
+
+import (
+	"fmt"
+	"math"
+)
+
+// BlendMode is the way the colors of a group are combined
+// with the colors beneath it.
+type BlendMode int
+
+const (
+	// BlendNormal paints the group over its backdrop.
+	BlendNormal BlendMode = iota
+
+	// BlendMultiply multiplies the colors of the group
+	// and its backdrop, which always darkens.
+	BlendMultiply
+
+	// BlendScreen multiplies the complements of the
+	// colors of the group and its backdrop, which
+	// always lightens.
+	BlendScreen
+
+	// BlendOverlay multiplies or screens the colors,
+	// depending on the color of the backdrop.
+	BlendOverlay
+
+	// BlendDarken selects the darker of the colors of
+	// the group and its backdrop.
+	BlendDarken
+
+	// BlendLighten selects the lighter of the colors of
+	// the group and its backdrop.
+	BlendLighten
+)
+
+// String returns the name of the blend mode.
+func (m BlendMode) String() string {
+	switch m {
+	case BlendNormal:
+		return "Normal"
+	case BlendMultiply:
+		return "Multiply"
+	case BlendScreen:
+		return "Screen"
+	case BlendOverlay:
+		return "Overlay"
+	case BlendDarken:
+		return "Darken"
+	case BlendLighten:
+		return "Lighten"
+	}
+	return fmt.Sprintf("BlendMode(%d)", int(m))
+}
+
+// Blend returns the blended value of the color
+// component cs of a group over the color component cb of
+// its backdrop. Both components are in the range [0, 1]
+// and are not premultiplied by alpha.
+func (m BlendMode) Blend(cb, cs float64) float64 {
+	switch m {
+	case BlendMultiply:
+		return cb * cs
+	case BlendScreen:
+		return cb + cs - cb*cs
+	case BlendOverlay:
+		if cb <= 0.5 {
+			return 2 * cb * cs
+		}
+		return 1 - 2*(1-cb)*(1-cs)
+	case BlendDarken:
+		return math.Min(cb, cs)
+	case BlendLighten:
+		return math.Max(cb, cs)
+	default:
+		return cs
+	}
+}
+
+// Group specifies how a group of drawing operations is
+// composited onto a canvas.
+//
+// The zero value of Group is an opaque group drawn with
+// the BlendNormal blend mode.
+type Group struct {
+	// Opacity is the opacity of the whole group, in the
+	// range (0, 1]. If Opacity is not positive, the
+	// group is opaque. Values above 1 are treated as 1.
+	Opacity float64
+
+	// Blend is the blend mode of the group.
+	Blend BlendMode
+}
+
+// Alpha returns the opacity of the group, taking the
+// default value into account.
+func (g Group) Alpha() float64 {
+	if g.Opacity <= 0 || g.Opacity > 1 {
+		return 1
+	}
+	return g.Opacity
+}
+
+// Grouper is a Canvas that can draw a group of drawing
+// operations into an isolated layer, that is composited onto
+// the canvas as a whole. Overlapping parts of a translucent
+// group are thus not darker than the rest of the group.
+type Grouper interface {
+	Canvas
+
+	// DrawGroup calls fn with a canvas drawing into a
+	// new layer, then composites the layer onto the
+	// canvas with the opacity and blend mode of g.
+	// The canvas passed to fn has the state of the
+	// receiver, and must only be used during the call.
+	DrawGroup(g Group, fn func(Canvas))
+}
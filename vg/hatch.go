@@ -0,0 +1,206 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg
+
+import (
+	"image/color"
+	"math"
+)
+
+// HatchStyle is the kind of pattern drawn by a Hatch.
+type HatchStyle int
+
+const (
+	// HatchDiagonal draws lines at 45 degrees.
+	HatchDiagonal HatchStyle = iota
+
+	// HatchCross draws horizontal and vertical lines.
+	HatchCross
+
+	// HatchDots draws a grid of dots.
+	HatchDots
+
+	// HatchHorizontal draws horizontal lines.
+	HatchHorizontal
+
+	// HatchVertical draws vertical lines.
+	HatchVertical
+)
+
+const (
+	// DefaultHatchSpacing is the spacing used by a Hatch
+	// with a zero Spacing.
+	DefaultHatchSpacing = 4
+
+	// DefaultHatchWidth is the line width used by a Hatch
+	// with a zero Width.
+	DefaultHatchWidth = 0.5
+)
+
+// Hatch is a fill pattern made of lines or dots, that can be
+// used to distinguish filled areas without colors.
+//
+// A Hatch is passed to a canvas with SetColor, and is used by
+// subsequent calls to Fill. The pattern is anchored at the
+// origin of the user space, so that adjacent areas filled with
+// the same Hatch line up.
+// Stroke and text operations use the color of the lines.
+type Hatch struct {
+	// Style is the kind of pattern.
+	Style HatchStyle
+
+	// Color is the color of the lines or dots.
+	// If Color is nil, black is used.
+	Color color.Color
+
+	// Background is the color drawn beneath the
+	// pattern. If Background is nil, the background
+	// is left transparent.
+	Background color.Color
+
+	// Spacing is the distance between the lines
+	// or dots.
+	Spacing Length
+
+	// Width is the width of the lines or the
+	// diameter of the dots.
+	Width Length
+
+	// Angle is the rotation of the pattern,
+	// in radians.
+	Angle float64
+}
+
+// RGBA implements the color.Color interface, returning the
+// color of the lines or dots.
+func (h *Hatch) RGBA() (r, g, b, a uint32) {
+	return h.color().RGBA()
+}
+
+func (h *Hatch) color() color.Color {
+	if h.Color == nil {
+		return color.Black
+	}
+	return h.Color
+}
+
+// HatchSpacing returns the spacing of the pattern, taking
+// the default value into account.
+func (h *Hatch) HatchSpacing() Length {
+	if h.Spacing <= 0 {
+		return DefaultHatchSpacing
+	}
+	return h.Spacing
+}
+
+// HatchWidth returns the width of the lines or the diameter
+// of the dots, taking the default value into account.
+func (h *Hatch) HatchWidth() Length {
+	if h.Width <= 0 {
+		return DefaultHatchWidth
+	}
+	return h.Width
+}
+
+// Angles returns the angles of the line families of the
+// pattern, in radians. Dots are laid out on a grid aligned
+// with the first angle.
+func (h *Hatch) Angles() []float64 {
+	switch h.Style {
+	case HatchDiagonal:
+		return []float64{h.Angle + math.Pi/4}
+	case HatchCross:
+		return []float64{h.Angle, h.Angle + math.Pi/2}
+	case HatchVertical:
+		return []float64{h.Angle + math.Pi/2}
+	default:
+		return []float64{h.Angle}
+	}
+}
+
+// FillHatch fills the path with the hatch pattern by drawing
+// lines or dots clipped to the path.
+// It may be used by canvases that can not natively fill
+// paths with a pattern.
+func FillHatch(c Clipper, path Path, h *Hatch) {
+	c.Push()
+	defer c.Pop()
+
+	if h.Background != nil {
+		c.SetColor(h.Background)
+		c.Fill(path)
+	}
+	c.Clip(path)
+	c.SetColor(h.color())
+
+	var (
+		rect    = path.Bounds()
+		spacing = h.HatchSpacing()
+		width   = h.HatchWidth()
+		corners = []Point{
+			rect.Min, rect.Max,
+			{X: rect.Min.X, Y: rect.Max.Y},
+			{X: rect.Max.X, Y: rect.Min.Y},
+		}
+	)
+
+	// span returns the indices of the first and last lines,
+	// spaced along the direction (x, y), that cross the
+	// bounding box of the path.
+	span := func(x, y Length) (int, int) {
+		lo := Length(math.Inf(+1))
+		hi := Length(math.Inf(-1))
+		for _, p := range corners {
+			v := (p.X*x + p.Y*y) / spacing
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		return int(math.Floor(float64(lo) - 1)), int(math.Ceil(float64(hi)))
+	}
+
+	if h.Style == HatchDots {
+		var (
+			sin, cos = math.Sincos(h.Angle)
+			dx       = Point{X: Length(cos), Y: Length(sin)}
+			dy       = Point{X: Length(-sin), Y: Length(cos)}
+			i0, i1   = span(dx.X, dx.Y)
+			j0, j1   = span(dy.X, dy.Y)
+			dot      Path
+		)
+		for i := i0; i <= i1; i++ {
+			for j := j0; j <= j1; j++ {
+				ctr := dx.Scale((Length(i) + 0.5) * spacing).Add(dy.Scale((Length(j) + 0.5) * spacing))
+				dot.Move(Point{X: ctr.X + width/2, Y: ctr.Y})
+				dot.Arc(ctr, width/2, 0, 2*math.Pi)
+				dot.Close()
+			}
+		}
+		c.Fill(dot)
+		return
+	}
+
+	c.SetLineWidth(width)
+	c.SetLineDash(nil, 0)
+	for _, a := range h.Angles() {
+		var (
+			sin, cos = math.Sincos(a)
+			dir      = Point{X: Length(cos), Y: Length(sin)}
+			nrm      = Point{X: Length(-sin), Y: Length(cos)}
+			k0, k1   = span(nrm.X, nrm.Y)
+			t0, t1   = span(dir.X, dir.Y)
+			lines    Path
+		)
+		for k := k0; k <= k1; k++ {
+			off := nrm.Scale((Length(k) + 0.5) * spacing)
+			lines.Move(off.Add(dir.Scale(Length(t0) * spacing)))
+			lines.Line(off.Add(dir.Scale(Length(t1+1) * spacing)))
+		}
+		c.Stroke(lines)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vg_test
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/recorder"
+)
+
+func TestFillHatch(t *testing.T) {
+	path := vg.Rectangle{Max: vg.Point{X: 20, Y: 10}}.Path()
+	for _, tc := range []struct {
+		hatch *vg.Hatch
+		want  []string
+	}{
+		{
+			hatch: &vg.Hatch{Style: vg.HatchDiagonal},
+			want:  []string{"Push", "Clip", "SetColor", "SetLineWidth", "SetLineDash", "Stroke", "Pop"},
+		},
+		{
+			hatch: &vg.Hatch{Style: vg.HatchCross, Background: color.White},
+			want:  []string{"Push", "SetColor", "Fill", "Clip", "SetColor", "SetLineWidth", "SetLineDash", "Stroke", "Stroke", "Pop"},
+		},
+		{
+			hatch: &vg.Hatch{Style: vg.HatchDots},
+			want:  []string{"Push", "Clip", "SetColor", "Fill", "Pop"},
+		},
+	} {
+		t.Run(fmt.Sprint(tc.hatch.Style), func(t *testing.T) {
+			var c recorder.Canvas
+			vg.FillHatch(&c, path, tc.hatch)
+			var got []string
+			for _, a := range c.Actions {
+				got = append(got, reflect.TypeOf(a).Elem().Name())
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("invalid actions:\ngot= %q\nwant=%q", got, tc.want)
+			}
+		})
+	}
+}
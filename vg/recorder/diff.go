@@ -0,0 +1,386 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/emptywe/plot/vg"
+)
+
+// Difference is a difference between an expected and an
+// actual list of actions.
+type Difference struct {
+	// Want and Got are the expected and actual actions.
+	// Got is nil if Want is missing from the actual
+	// actions, and Want is nil if Got is not expected.
+	Want, Got Action
+
+	// WantIndex and GotIndex are the indices of Want and
+	// Got in their lists. The indices of the actions drawn
+	// in groups follow the indices of their DrawGroup
+	// actions. The index of a nil action is nil.
+	WantIndex, GotIndex []int
+
+	// Desc describes the difference.
+	Desc string
+}
+
+// String returns the description of the difference, prefixed
+// with the indices of the actions and with the caller location
+// of the actual action, or of the expected action if the actual
+// action is missing or has no caller location.
+func (d Difference) String() string {
+	var loc callerLocation
+	switch {
+	case d.Got != nil && d.Got.callerLocation().haveCaller:
+		loc = *d.Got.callerLocation()
+	case d.Want != nil:
+		loc = *d.Want.callerLocation()
+	}
+	var idx []string
+	if d.WantIndex != nil {
+		idx = append(idx, "want"+formatIndex(d.WantIndex))
+	}
+	if d.GotIndex != nil {
+		idx = append(idx, "got"+formatIndex(d.GotIndex))
+	}
+	return fmt.Sprintf("%s%s: %s", loc, strings.Join(idx, " "), d.Desc)
+}
+
+func formatIndex(idx []int) string {
+	s := make([]string, len(idx))
+	for i, v := range idx {
+		s[i] = fmt.Sprint(v)
+	}
+	return "[" + strings.Join(s, ".") + "]"
+}
+
+// Diff returns the differences between the expected actions
+// want and the actual actions got.
+//
+// The actions are aligned by their sequence of calls, with a
+// longest common subsequence of the methods called and of the
+// text of the FillString and Comment actions. The unaligned
+// actions are reported as missing or unexpected, and the
+// aligned actions are compared field by field: colors are
+// compared by their RGBA values, except for gradients and
+// hatches which are compared by their parameters, and the
+// coordinates and lengths are compared with the tolerance tol.
+// The actions drawn in aligned groups are compared recursively.
+//
+// The cost of the alignment is quadratic in the number of
+// actions remaining once the common prefix and suffix of the
+// calls are removed.
+func Diff(want, got []Action, tol vg.Length) []Difference {
+	return diff(nil, want, nil, got, tol)
+}
+
+func diff(wantIdx []int, want []Action, gotIdx []int, got []Action, tol vg.Length) []Difference {
+	index := func(prefix []int, i int) []int {
+		return append(append([]int(nil), prefix...), i)
+	}
+
+	var diffs []Difference
+	i, j := 0, 0
+	for _, m := range align(want, got) {
+		for ; i < m[0]; i++ {
+			diffs = append(diffs, Difference{
+				Want:      want[i],
+				WantIndex: index(wantIdx, i),
+				Desc:      "missing " + describe(want[i]),
+			})
+		}
+		for ; j < m[1]; j++ {
+			diffs = append(diffs, Difference{
+				Got:      got[j],
+				GotIndex: index(gotIdx, j),
+				Desc:     "unexpected " + describe(got[j]),
+			})
+		}
+		if i == len(want) {
+			break
+		}
+		wi, gi := index(wantIdx, i), index(gotIdx, j)
+		if g, ok := want[i].(*DrawGroup); ok {
+			diffs = append(diffs, diff(wi, g.Actions, gi, got[j].(*DrawGroup).Actions, tol)...)
+		}
+		for _, desc := range compare(want[i], got[j], tol) {
+			diffs = append(diffs, Difference{
+				Want: want[i], Got: got[j],
+				WantIndex: wi, GotIndex: gi,
+				Desc: desc,
+			})
+		}
+		i++
+		j++
+	}
+	return diffs
+}
+
+// align returns the indices of the aligned actions of want and
+// got, followed by the pair of the lengths of the lists.
+func align(want, got []Action) [][2]int {
+	wk := make([]string, len(want))
+	for i, a := range want {
+		wk[i] = key(a)
+	}
+	gk := make([]string, len(got))
+	for i, a := range got {
+		gk[i] = key(a)
+	}
+
+	var pre int
+	for pre < len(wk) && pre < len(gk) && wk[pre] == gk[pre] {
+		pre++
+	}
+	var suf int
+	for suf < len(wk)-pre && suf < len(gk)-pre && wk[len(wk)-1-suf] == gk[len(gk)-1-suf] {
+		suf++
+	}
+
+	var pairs [][2]int
+	for i := 0; i < pre; i++ {
+		pairs = append(pairs, [2]int{i, i})
+	}
+
+	// Find the longest common subsequence of the remaining
+	// keys, lcs[i][j] being the length of the subsequence
+	// of wk[i:] and gk[j:].
+	w, g := wk[pre:len(wk)-suf], gk[pre:len(gk)-suf]
+	lcs := make([][]int, len(w)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(g)+1)
+	}
+	for i := len(w) - 1; i >= 0; i-- {
+		for j := len(g) - 1; j >= 0; j-- {
+			switch {
+			case w[i] == g[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(w) && j < len(g); {
+		switch {
+		case w[i] == g[j]:
+			pairs = append(pairs, [2]int{pre + i, pre + j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	for k := suf; k > 0; k-- {
+		pairs = append(pairs, [2]int{len(wk) - k, len(gk) - k})
+	}
+	return append(pairs, [2]int{len(wk), len(gk)})
+}
+
+// key returns the key of the action used for the alignment.
+func key(a Action) string {
+	switch a := a.(type) {
+	case *FillString:
+		return "FillString " + a.String
+	case *Comment:
+		return "Comment " + a.Text
+	}
+	return name(a)
+}
+
+// name returns the name of the method of the action.
+func name(a Action) string {
+	return reflect.TypeOf(a).Elem().Name()
+}
+
+// describe returns a short description of the action.
+func describe(a Action) string {
+	switch a := a.(type) {
+	case *FillString:
+		return fmt.Sprintf("text %q", a.String)
+	case *Comment:
+		return fmt.Sprintf("comment %q", a.Text)
+	case *DrawGroup:
+		return fmt.Sprintf("DrawGroup with %d actions", len(a.Actions))
+	case *DrawImage:
+		return fmt.Sprintf("DrawImage of %v", a.Image.Bounds())
+	}
+	call := a.Call()
+	return call[len(a.callerLocation().String()):]
+}
+
+// compare returns the descriptions of the differences
+// between the aligned actions want and got.
+func compare(want, got Action, tol vg.Length) []string {
+	var diffs []string
+	differ := func(format string, args ...interface{}) {
+		diffs = append(diffs, name(want)+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch w := want.(type) {
+	case *SetLineWidth:
+		g := got.(*SetLineWidth)
+		if !near(w.Width, g.Width, tol) {
+			differ("width changed from %v to %v", w.Width, g.Width)
+		}
+	case *SetLineDash:
+		g := got.(*SetLineDash)
+		if !nearLengths(w.Dashes, g.Dashes, tol) || !near(w.Offsets, g.Offsets, tol) {
+			differ("dashes changed from %v, %v to %v, %v", w.Dashes, w.Offsets, g.Dashes, g.Offsets)
+		}
+	case *SetColor:
+		g := got.(*SetColor)
+		if !sameColor(w.Color, g.Color) {
+			differ("color changed from %v to %v", w.Color, g.Color)
+		}
+	case *SetLineCap:
+		if g := got.(*SetLineCap); w.Cap != g.Cap {
+			differ("cap changed from %v to %v", w.Cap, g.Cap)
+		}
+	case *SetLineJoin:
+		if g := got.(*SetLineJoin); w.Join != g.Join {
+			differ("join changed from %v to %v", w.Join, g.Join)
+		}
+	case *SetMiterLimit:
+		if g := got.(*SetMiterLimit); w.Limit != g.Limit {
+			differ("limit changed from %v to %v", w.Limit, g.Limit)
+		}
+	case *Rotate:
+		if g := got.(*Rotate); w.Angle != g.Angle {
+			differ("angle changed from %v to %v", w.Angle, g.Angle)
+		}
+	case *Translate:
+		if g := got.(*Translate); !nearPoint(w.Point, g.Point, tol) {
+			differ("point moved from %v to %v", w.Point, g.Point)
+		}
+	case *Scale:
+		if g := got.(*Scale); w.X != g.X || w.Y != g.Y {
+			differ("scale changed from (%v, %v) to (%v, %v)", w.X, w.Y, g.X, g.Y)
+		}
+	case *Stroke:
+		if desc := comparePaths(w.Path, got.(*Stroke).Path, tol); desc != "" {
+			differ("%s", desc)
+		}
+	case *Fill:
+		if desc := comparePaths(w.Path, got.(*Fill).Path, tol); desc != "" {
+			differ("%s", desc)
+		}
+	case *Clip:
+		if desc := comparePaths(w.Path, got.(*Clip).Path, tol); desc != "" {
+			differ("%s", desc)
+		}
+	case *DrawGroup:
+		if g := got.(*DrawGroup); w.Group != g.Group {
+			differ("group changed from %+v to %+v", w.Group, g.Group)
+		}
+	case *FillString:
+		g := got.(*FillString)
+		if w.Font.Name() != g.Font.Name() || !near(w.Size, g.Size, tol) {
+			differ("font of %q changed from %s %v to %s %v", w.String, w.Font.Name(), w.Size, g.Font.Name(), g.Size)
+		}
+		if !nearPoint(w.Point, g.Point, tol) {
+			differ("text %q moved from %v to %v", w.String, w.Point, g.Point)
+		}
+	case *DrawImage:
+		g := got.(*DrawImage)
+		if !nearPoint(w.Rectangle.Min, g.Rectangle.Min, tol) || !nearPoint(w.Rectangle.Max, g.Rectangle.Max, tol) {
+			differ("rectangle moved from %v to %v", w.Rectangle, g.Rectangle)
+		}
+		if n := diffPixels(w.Image, g.Image); n != 0 {
+			differ("image changed at %d pixels", n)
+		}
+	}
+	return diffs
+}
+
+func near(a, b, tol vg.Length) bool {
+	return math.Abs(float64(a-b)) <= float64(tol)
+}
+
+func nearPoint(a, b vg.Point, tol vg.Length) bool {
+	return near(a.X, b.X, tol) && near(a.Y, b.Y, tol)
+}
+
+func nearLengths(a, b []vg.Length, tol vg.Length) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !near(a[i], b[i], tol) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameColor returns whether the colors a and b are equal, as
+// they are encoded by MarshalJSON.
+func sameColor(a, b color.Color) bool {
+	return reflect.DeepEqual(encodeColor(a), encodeColor(b))
+}
+
+// comparePaths returns a description of the first difference
+// between the paths want and got, or an empty string.
+func comparePaths(want, got vg.Path, tol vg.Length) string {
+	if len(want) != len(got) {
+		return fmt.Sprintf("path has %d components, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		switch {
+		case w.Type != g.Type:
+			return fmt.Sprintf("path component %d changed type from %d to %d", i, w.Type, g.Type)
+		case !nearPoint(w.Pos, g.Pos, tol):
+			return fmt.Sprintf("path component %d moved from %v to %v", i, w.Pos, g.Pos)
+		case len(w.Control) != len(g.Control):
+			return fmt.Sprintf("path component %d has %d control points, want %d", i, len(g.Control), len(w.Control))
+		case !near(w.Radius, g.Radius, tol) || w.Start != g.Start || w.Angle != g.Angle:
+			return fmt.Sprintf("path component %d changed arc from %v, %v, %v to %v, %v, %v", i, w.Radius, w.Start, w.Angle, g.Radius, g.Start, g.Angle)
+		}
+		for j := range w.Control {
+			if !nearPoint(w.Control[j], g.Control[j], tol) {
+				return fmt.Sprintf("path component %d moved control point %d from %v to %v", i, j, w.Control[j], g.Control[j])
+			}
+		}
+	}
+	return ""
+}
+
+// diffPixels returns the number of pixels that differ
+// between the images a and b, or the number of pixels of
+// the largest image if their bounds differ.
+func diffPixels(a, b image.Image) int {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Size() != bb.Size() {
+		return max(ab.Dx()*ab.Dy(), bb.Dx()*bb.Dy())
+	}
+	var n int
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			if !sameColor(a.At(ab.Min.X+x, ab.Min.Y+y), b.At(bb.Min.X+x, bb.Min.Y+y)) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/vg"
+)
+
+func TestDiff(t *testing.T) {
+	face := font.Face{Font: font.Font{Typeface: "Liberation", Variant: "Sans", Size: 12}}
+	line := func(x vg.Length) vg.Path {
+		var p vg.Path
+		p.Move(vg.Point{X: x, Y: 1})
+		p.Line(vg.Point{X: 10, Y: 10})
+		return p
+	}
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+
+	var want Canvas
+	want.SetColor(color.NRGBA{R: 0xff, A: 0xff})
+	want.SetLineWidth(1)
+	want.Stroke(line(1))
+	want.FillString(face, vg.Point{X: 1, Y: 1}, "title")
+	want.FillString(face, vg.Point{X: 1, Y: 2}, "x")
+	want.DrawGroup(vg.Group{Opacity: 0.5}, func(c vg.Canvas) {
+		c.DrawImage(vg.Rectangle{Max: vg.Point{X: 4, Y: 4}}, img)
+	})
+
+	img2 := image.NewGray(image.Rect(0, 0, 4, 4))
+	img2.Pix[5] = 0xff
+
+	var got Canvas
+	got.KeepCaller = true
+	got.SetColor(color.RGBA{R: 0xff, A: 0xff})
+	got.SetLineWidth(1.05)
+	got.Stroke(line(1.5))
+	got.FillString(face, vg.Point{X: 1, Y: 2}, "x")
+	got.Fill(line(2))
+	got.DrawGroup(vg.Group{Opacity: 0.5}, func(c vg.Canvas) {
+		c.DrawImage(vg.Rectangle{Max: vg.Point{X: 4, Y: 4}}, img2)
+	})
+
+	diffs := Diff(want.Actions, got.Actions, 0.1)
+	type result struct {
+		want, got []int
+		desc      string
+	}
+	var results []result
+	for _, d := range diffs {
+		results = append(results, result{want: d.WantIndex, got: d.GotIndex, desc: d.Desc})
+	}
+	wantResults := []result{
+		{want: []int{2}, got: []int{2}, desc: "Stroke: path component 0 moved from {1 1} to {1.5 1}"},
+		{want: []int{3}, desc: `missing text "title"`},
+		{got: []int{4}, desc: `unexpected Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:2, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:10, Y:10}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})`},
+		{want: []int{5, 0}, got: []int{5, 0}, desc: "DrawImage: image changed at 1 pixels"},
+	}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("unexpected differences:\ngot: %+v\nwant:%+v", results, wantResults)
+	}
+
+	if s := diffs[0].String(); !strings.Contains(s, "diff_test.go:") || !strings.HasSuffix(s, "want[2] got[2]: "+wantResults[0].desc) {
+		t.Errorf("unexpected difference string: %q", s)
+	}
+	if s := diffs[1].String(); s != "want[3]: "+wantResults[1].desc {
+		t.Errorf("unexpected difference string: %q", s)
+	}
+
+	diffs = Diff(want.Actions, got.Actions, 1)
+	if len(diffs) != 3 {
+		t.Errorf("unexpected number of differences with large tolerance: got:%d want:3", len(diffs))
+	}
+	if diffs := Diff(want.Actions, want.Actions, 0); len(diffs) != 0 {
+		t.Errorf("unexpected differences between identical actions: %v", diffs)
+	}
+}
+
+func TestDiffChanges(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		want, got func(c *Canvas)
+		desc      string
+	}{
+		{
+			name: "color",
+			want: func(c *Canvas) { c.SetColor(color.Black) },
+			got:  func(c *Canvas) { c.SetColor(color.Gray{Y: 0x10}) },
+			desc: "SetColor: color changed from {0} to {16}",
+		},
+		{
+			name: "cmyk",
+			want: func(c *Canvas) { c.SetColor(color.RGBA{A: 0xff}) },
+			got:  func(c *Canvas) { c.SetColor(color.CMYK{K: 0xff}) },
+			desc: "SetColor: color changed from {0 0 0 255} to {0 0 0 255}",
+		},
+		{
+			name: "gradient",
+			want: func(c *Canvas) { c.SetColor(&vg.LinearGradient{X2: 1}) },
+			got:  func(c *Canvas) { c.SetColor(&vg.LinearGradient{Y2: 1}) },
+			desc: "SetColor: color changed from &{0 0 1 0 []} to &{0 0 0 1 []}",
+		},
+		{
+			name: "dashes",
+			want: func(c *Canvas) { c.SetLineDash([]vg.Length{1, 2}, 0) },
+			got:  func(c *Canvas) { c.SetLineDash([]vg.Length{1}, 0) },
+			desc: "SetLineDash: dashes changed from [1 2], 0 to [1], 0",
+		},
+		{
+			name: "translate",
+			want: func(c *Canvas) { c.Translate(vg.Point{X: 1}) },
+			got:  func(c *Canvas) { c.Translate(vg.Point{X: 2}) },
+			desc: "Translate: point moved from {1 0} to {2 0}",
+		},
+		{
+			name: "path",
+			want: func(c *Canvas) { c.Fill(vg.Path{{Type: vg.MoveComp}, {Type: vg.LineComp}}) },
+			got:  func(c *Canvas) { c.Fill(vg.Path{{Type: vg.MoveComp}}) },
+			desc: "Fill: path has 1 components, want 2",
+		},
+		{
+			name: "text",
+			want: func(c *Canvas) {
+				c.FillString(font.Face{Font: font.Font{Typeface: "Liberation", Size: 12}}, vg.Point{}, "a")
+			},
+			got: func(c *Canvas) {
+				c.FillString(font.Face{Font: font.Font{Typeface: "Liberation", Size: 10}}, vg.Point{Y: 1}, "a")
+			},
+			desc: `FillString: font of "a" changed from Liberation-Regular 12 to Liberation-Regular 10; FillString: text "a" moved from {0 0} to {0 1}`,
+		},
+		{
+			name: "group",
+			want: func(c *Canvas) { c.DrawGroup(vg.Group{Opacity: 1}, func(vg.Canvas) {}) },
+			got:  func(c *Canvas) { c.DrawGroup(vg.Group{Opacity: 0.5}, func(vg.Canvas) {}) },
+			desc: "DrawGroup: group changed from {Opacity:1 Blend:Normal} to {Opacity:0.5 Blend:Normal}",
+		},
+	} {
+		var want, got Canvas
+		test.want(&want)
+		test.got(&got)
+		var descs []string
+		for _, d := range Diff(want.Actions, got.Actions, 0) {
+			descs = append(descs, d.Desc)
+		}
+		if desc := strings.Join(descs, "; "); desc != test.desc {
+			t.Errorf("unexpected difference for %s:\ngot: %s\nwant:%s", test.name, desc, test.desc)
+		}
+	}
+}
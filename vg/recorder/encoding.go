@@ -0,0 +1,391 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"image/png"
+
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/vg"
+)
+
+// version is the version of the serialization format
+// of the actions.
+const version = 1
+
+// MarshalJSON implements the json.Marshaler interface.
+// The actions are encoded as a JSON document, with the fonts
+// of the text identified by their typeface, variant, style,
+// weight and size, and the images encoded as PNG.
+//
+// Colors are encoded with their components, except for the
+// gradients and hatches of the vg package, which are encoded
+// with their parameters. Colors implementing vg.Gradient with
+// other types are encoded as plain colors.
+// The caller locations of the actions are not encoded.
+func (c *Canvas) MarshalJSON() ([]byte, error) {
+	doc, err := c.document()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// replacing the actions of the canvas by the actions encoded
+// by MarshalJSON.
+func (c *Canvas) UnmarshalJSON(b []byte) error {
+	var doc document
+	err := json.Unmarshal(b, &doc)
+	if err != nil {
+		return err
+	}
+	return c.setDocument(doc)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The actions are encoded as with MarshalJSON, in the compact
+// binary format of the encoding/gob package.
+func (c *Canvas) MarshalBinary() ([]byte, error) {
+	doc, err := c.document()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler
+// interface, replacing the actions of the canvas by the actions
+// encoded by MarshalBinary.
+func (c *Canvas) UnmarshalBinary(b []byte) error {
+	var doc document
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&doc)
+	if err != nil {
+		return err
+	}
+	return c.setDocument(doc)
+}
+
+func (c *Canvas) document() (document, error) {
+	actions, err := encodeActions(c.Actions)
+	if err != nil {
+		return document{}, err
+	}
+	return document{Version: version, Actions: actions}, nil
+}
+
+func (c *Canvas) setDocument(doc document) error {
+	if doc.Version != version {
+		return fmt.Errorf("recorder: unsupported format version %d", doc.Version)
+	}
+	actions, err := decodeActions(doc.Actions)
+	if err != nil {
+		return err
+	}
+	c.Actions = actions
+	return nil
+}
+
+// document is the encoded form of the actions of a Canvas.
+type document struct {
+	Version int
+	Actions []action
+}
+
+// action is the encoded form of an Action. Type is the name
+// of the Action type, and the other fields hold the fields of
+// the action of this type.
+type action struct {
+	Type string
+
+	Width     vg.Length     `json:",omitempty"`
+	Dashes    []vg.Length   `json:",omitempty"`
+	Offset    vg.Length     `json:",omitempty"`
+	Color     *colorValue   `json:",omitempty"`
+	Cap       vg.LineCap    `json:",omitempty"`
+	Join      vg.LineJoin   `json:",omitempty"`
+	Limit     float64       `json:",omitempty"`
+	Angle     float64       `json:",omitempty"`
+	X, Y      float64       `json:",omitempty"`
+	Point     *vg.Point     `json:",omitempty"`
+	Path      vg.Path       `json:",omitempty"`
+	Group     *vg.Group     `json:",omitempty"`
+	Actions   []action      `json:",omitempty"`
+	Font      *font.Font    `json:",omitempty"`
+	Text      string        `json:",omitempty"`
+	Rectangle *vg.Rectangle `json:",omitempty"`
+	Image     []byte        `json:",omitempty"`
+}
+
+// colorValue is the encoded form of a color.Color.
+// A single field is set, and a nil colorValue is a nil color.
+type colorValue struct {
+	RGBA           *color.RGBA64   `json:",omitempty"`
+	CMYK           *color.CMYK     `json:",omitempty"`
+	LinearGradient *linearGradient `json:",omitempty"`
+	RadialGradient *radialGradient `json:",omitempty"`
+	Hatch          *hatch          `json:",omitempty"`
+}
+
+type linearGradient struct {
+	X1, Y1, X2, Y2 float64
+	Stops          []gradientStop
+}
+
+type radialGradient struct {
+	CX, CY, R float64
+	Stops     []gradientStop
+}
+
+type gradientStop struct {
+	Offset float64
+	Color  *colorValue `json:",omitempty"`
+}
+
+type hatch struct {
+	Style             vg.HatchStyle
+	Color, Background *colorValue `json:",omitempty"`
+	Spacing, Width    vg.Length
+	Angle             float64
+}
+
+func encodeActions(actions []Action) ([]action, error) {
+	enc := make([]action, len(actions))
+	for i, a := range actions {
+		var err error
+		enc[i], err = encodeAction(a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return enc, nil
+}
+
+func encodeAction(a Action) (action, error) {
+	switch a := a.(type) {
+	case *SetLineWidth:
+		return action{Type: "SetLineWidth", Width: a.Width}, nil
+	case *SetLineDash:
+		return action{Type: "SetLineDash", Dashes: a.Dashes, Offset: a.Offsets}, nil
+	case *SetColor:
+		return action{Type: "SetColor", Color: encodeColor(a.Color)}, nil
+	case *SetLineCap:
+		return action{Type: "SetLineCap", Cap: a.Cap}, nil
+	case *SetLineJoin:
+		return action{Type: "SetLineJoin", Join: a.Join}, nil
+	case *SetMiterLimit:
+		return action{Type: "SetMiterLimit", Limit: a.Limit}, nil
+	case *Rotate:
+		return action{Type: "Rotate", Angle: a.Angle}, nil
+	case *Translate:
+		return action{Type: "Translate", Point: &a.Point}, nil
+	case *Scale:
+		return action{Type: "Scale", X: a.X, Y: a.Y}, nil
+	case *Push:
+		return action{Type: "Push"}, nil
+	case *Pop:
+		return action{Type: "Pop"}, nil
+	case *Stroke:
+		return action{Type: "Stroke", Path: a.Path}, nil
+	case *Fill:
+		return action{Type: "Fill", Path: a.Path}, nil
+	case *Clip:
+		return action{Type: "Clip", Path: a.Path}, nil
+	case *DrawGroup:
+		actions, err := encodeActions(a.Actions)
+		if err != nil {
+			return action{}, err
+		}
+		return action{Type: "DrawGroup", Group: &a.Group, Actions: actions}, nil
+	case *FillString:
+		fnt := a.Font
+		fnt.Size = a.Size
+		return action{Type: "FillString", Font: &fnt, Point: &a.Point, Text: a.String}, nil
+	case *DrawImage:
+		var buf bytes.Buffer
+		err := png.Encode(&buf, a.Image)
+		if err != nil {
+			return action{}, fmt.Errorf("recorder: error encoding image to PNG: %w", err)
+		}
+		return action{Type: "DrawImage", Rectangle: &a.Rectangle, Image: buf.Bytes()}, nil
+	case *Comment:
+		return action{Type: "Comment", Text: a.Text}, nil
+	default:
+		return action{}, fmt.Errorf("recorder: cannot encode action of type %T", a)
+	}
+}
+
+func decodeActions(enc []action) ([]Action, error) {
+	actions := make([]Action, len(enc))
+	for i, a := range enc {
+		var err error
+		actions[i], err = decodeAction(a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return actions, nil
+}
+
+func decodeAction(a action) (Action, error) {
+	switch a.Type {
+	case "SetLineWidth":
+		return &SetLineWidth{Width: a.Width}, nil
+	case "SetLineDash":
+		return &SetLineDash{Dashes: a.Dashes, Offsets: a.Offset}, nil
+	case "SetColor":
+		return &SetColor{Color: decodeColor(a.Color)}, nil
+	case "SetLineCap":
+		return &SetLineCap{Cap: a.Cap}, nil
+	case "SetLineJoin":
+		return &SetLineJoin{Join: a.Join}, nil
+	case "SetMiterLimit":
+		return &SetMiterLimit{Limit: a.Limit}, nil
+	case "Rotate":
+		return &Rotate{Angle: a.Angle}, nil
+	case "Translate":
+		return &Translate{Point: point(a.Point)}, nil
+	case "Scale":
+		return &Scale{X: a.X, Y: a.Y}, nil
+	case "Push":
+		return &Push{}, nil
+	case "Pop":
+		return &Pop{}, nil
+	case "Stroke":
+		return &Stroke{Path: a.Path}, nil
+	case "Fill":
+		return &Fill{Path: a.Path}, nil
+	case "Clip":
+		return &Clip{Path: a.Path}, nil
+	case "DrawGroup":
+		actions, err := decodeActions(a.Actions)
+		if err != nil {
+			return nil, err
+		}
+		var g vg.Group
+		if a.Group != nil {
+			g = *a.Group
+		}
+		return &DrawGroup{Group: g, Actions: actions}, nil
+	case "FillString":
+		if a.Font == nil {
+			return nil, fmt.Errorf("recorder: missing font of FillString action")
+		}
+		return &FillString{Font: *a.Font, Size: a.Font.Size, Point: point(a.Point), String: a.Text}, nil
+	case "DrawImage":
+		img, err := png.Decode(bytes.NewReader(a.Image))
+		if err != nil {
+			return nil, fmt.Errorf("recorder: error decoding PNG image: %w", err)
+		}
+		var r vg.Rectangle
+		if a.Rectangle != nil {
+			r = *a.Rectangle
+		}
+		return &DrawImage{Rectangle: r, Image: img}, nil
+	case "Comment":
+		return &Comment{Text: a.Text}, nil
+	default:
+		return nil, fmt.Errorf("recorder: unknown action type %q", a.Type)
+	}
+}
+
+func point(p *vg.Point) vg.Point {
+	if p == nil {
+		return vg.Point{}
+	}
+	return *p
+}
+
+func encodeColor(c color.Color) *colorValue {
+	switch c := c.(type) {
+	case nil:
+		return nil
+	case color.CMYK:
+		return &colorValue{CMYK: &c}
+	case *vg.LinearGradient:
+		return &colorValue{LinearGradient: &linearGradient{
+			X1: c.X1, Y1: c.Y1, X2: c.X2, Y2: c.Y2,
+			Stops: encodeStops(c.Stops),
+		}}
+	case *vg.RadialGradient:
+		return &colorValue{RadialGradient: &radialGradient{
+			CX: c.CX, CY: c.CY, R: c.R,
+			Stops: encodeStops(c.Stops),
+		}}
+	case *vg.Hatch:
+		return &colorValue{Hatch: &hatch{
+			Style:      c.Style,
+			Color:      encodeColor(c.Color),
+			Background: encodeColor(c.Background),
+			Spacing:    c.Spacing,
+			Width:      c.Width,
+			Angle:      c.Angle,
+		}}
+	default:
+		rgba := color.RGBA64Model.Convert(c).(color.RGBA64)
+		return &colorValue{RGBA: &rgba}
+	}
+}
+
+func encodeStops(stops []vg.GradientStop) []gradientStop {
+	enc := make([]gradientStop, len(stops))
+	for i, s := range stops {
+		enc[i] = gradientStop{Offset: s.Offset, Color: encodeColor(s.Color)}
+	}
+	return enc
+}
+
+func decodeColor(c *colorValue) color.Color {
+	switch {
+	case c == nil:
+		return nil
+	case c.RGBA != nil:
+		return *c.RGBA
+	case c.CMYK != nil:
+		return *c.CMYK
+	case c.LinearGradient != nil:
+		g := c.LinearGradient
+		return &vg.LinearGradient{
+			X1: g.X1, Y1: g.Y1, X2: g.X2, Y2: g.Y2,
+			Stops: decodeStops(g.Stops),
+		}
+	case c.RadialGradient != nil:
+		g := c.RadialGradient
+		return &vg.RadialGradient{
+			CX: g.CX, CY: g.CY, R: g.R,
+			Stops: decodeStops(g.Stops),
+		}
+	case c.Hatch != nil:
+		h := c.Hatch
+		return &vg.Hatch{
+			Style:      h.Style,
+			Color:      decodeColor(h.Color),
+			Background: decodeColor(h.Background),
+			Spacing:    h.Spacing,
+			Width:      h.Width,
+			Angle:      h.Angle,
+		}
+	default:
+		return nil
+	}
+}
+
+func decodeStops(stops []gradientStop) []vg.GradientStop {
+	dec := make([]vg.GradientStop, len(stops))
+	for i, s := range stops {
+		dec[i] = vg.GradientStop{Offset: s.Offset, Color: decodeColor(s.Color)}
+	}
+	return dec
+}
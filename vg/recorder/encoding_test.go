@@ -0,0 +1,142 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/vg"
+)
+
+func TestEncoding(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 11)
+	}
+
+	var rec Canvas
+	rec.FillString(font.Face{Font: font.Font{Typeface: "Liberation", Variant: "Sans", Size: 12}}, vg.Point{X: 1, Y: 10}, "Text")
+	rec.Comment("comment")
+	rec.Scale(1, 2)
+	rec.Rotate(0.72)
+	rec.Push()
+	rec.Translate(vg.Point{X: 3, Y: 4})
+	rec.Pop()
+	rec.SetLineWidth(2)
+	rec.SetLineDash([]vg.Length{2, 5}, 6)
+	rec.SetLineCap(vg.SquareCap)
+	rec.SetLineJoin(vg.MiterJoin)
+	rec.SetMiterLimit(4)
+	rec.SetColor(color.NRGBA{R: 0x65, G: 0x23, B: 0xf2, A: 0x80})
+	rec.SetColor(color.CMYK{C: 0x10, K: 0x20})
+	rec.SetColor(nil)
+	rec.SetColor(&vg.LinearGradient{X2: 1, Stops: []vg.GradientStop{{Color: color.White}, {Offset: 1, Color: color.Black}}})
+	rec.SetColor(&vg.RadialGradient{CX: 0.5, CY: 0.5, R: 0.5, Stops: []vg.GradientStop{{Color: color.Transparent}}})
+	rec.SetColor(&vg.Hatch{Style: vg.HatchCross, Color: color.Black, Spacing: 4, Width: 1, Angle: 0.1})
+	rec.Stroke(vg.Path{{Type: vg.MoveComp, Pos: vg.Point{X: 3, Y: 4}}, {Type: vg.ArcComp, Pos: vg.Point{X: 3, Y: 4}, Radius: 2, Start: 1, Angle: 2}})
+	rec.Fill(vg.Path{{Type: vg.MoveComp}, {Type: vg.CurveComp, Pos: vg.Point{X: 2, Y: 3}, Control: []vg.Point{{X: 1, Y: 1}}}, {Type: vg.CloseComp}})
+	rec.Clip(vg.Path{{Type: vg.MoveComp, Pos: vg.Point{X: 1, Y: 2}}})
+	rec.DrawGroup(vg.Group{Opacity: 0.5, Blend: vg.BlendMultiply}, func(c vg.Canvas) {
+		c.SetLineWidth(3)
+		c.DrawImage(vg.Rectangle{Max: vg.Point{X: 10, Y: 10}}, src)
+	})
+
+	want, err := rec.document()
+	if err != nil {
+		t.Fatalf("unexpected error encoding actions: %v", err)
+	}
+
+	for _, test := range []struct {
+		name      string
+		marshal   func() ([]byte, error)
+		unmarshal func(*Canvas, []byte) error
+	}{
+		{name: "json", marshal: rec.MarshalJSON, unmarshal: (*Canvas).UnmarshalJSON},
+		{name: "binary", marshal: rec.MarshalBinary, unmarshal: (*Canvas).UnmarshalBinary},
+	} {
+		b, err := test.marshal()
+		if err != nil {
+			t.Fatalf("%s: unexpected error marshaling: %v", test.name, err)
+		}
+		var got Canvas
+		err = test.unmarshal(&got, b)
+		if err != nil {
+			t.Fatalf("%s: unexpected error unmarshaling: %v", test.name, err)
+		}
+		if len(got.Actions) != len(rec.Actions) {
+			t.Fatalf("%s: unexpected number of actions: got:%d want:%d", test.name, len(got.Actions), len(rec.Actions))
+		}
+		doc, err := got.document()
+		if err != nil {
+			t.Fatalf("%s: unexpected error encoding decoded actions: %v", test.name, err)
+		}
+		for i := range doc.Actions {
+			if !reflect.DeepEqual(doc.Actions[i], want.Actions[i]) {
+				t.Errorf("%s: unexpected action %d:\ngot: %s\nwant:%s", test.name, i, got.Actions[i].Call(), rec.Actions[i].Call())
+			}
+		}
+
+		var replay Canvas
+		err = got.ReplayOn(&replay)
+		if err != nil {
+			t.Errorf("%s: unexpected error replaying actions: %v", test.name, err)
+		}
+
+		img := got.Actions[len(got.Actions)-1].(*DrawGroup).Actions[1].(*DrawImage).Image
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 3; x++ {
+				if g, w := color.NRGBAModel.Convert(img.At(x, y)), src.At(x, y); g != w {
+					t.Errorf("%s: unexpected image pixel at (%d, %d): got:%v want:%v", test.name, x, y, g, w)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	const doc = `{
+	"Version": 1,
+	"Actions": [
+		{"Type": "SetColor", "Color": {"RGBA": {"R": 65535, "G": 0, "B": 0, "A": 65535}}},
+		{"Type": "Translate", "Point": {"X": 1, "Y": 2}},
+		{"Type": "Fill", "Path": [{"Type": 0, "Pos": {"X": 0, "Y": 0}}, {"Type": 1, "Pos": {"X": 10, "Y": 0}}, {"Type": 4}]},
+		{"Type": "FillString", "Font": {"Typeface": "Liberation", "Variant": "Serif", "Size": 12}, "Point": {"X": 0, "Y": 10}, "Text": "Text"}
+	]
+}`
+	var rec Canvas
+	err := json.Unmarshal([]byte(doc), &rec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Action{
+		&SetColor{Color: color.RGBA64{R: 0xffff, A: 0xffff}},
+		&Translate{Point: vg.Point{X: 1, Y: 2}},
+		&Fill{Path: vg.Path{{Type: vg.MoveComp}, {Type: vg.LineComp, Pos: vg.Point{X: 10}}, {Type: vg.CloseComp}}},
+		&FillString{Font: font.Font{Typeface: "Liberation", Variant: "Serif", Size: 12}, Size: 12, Point: vg.Point{Y: 10}, String: "Text"},
+	}
+	if !reflect.DeepEqual(rec.Actions, want) {
+		t.Errorf("unexpected actions:\ngot: %#v\nwant:%#v", rec.Actions, want)
+	}
+
+	for _, test := range []struct {
+		doc  string
+		want string
+	}{
+		{doc: `{"Version": 2}`, want: "recorder: unsupported format version 2"},
+		{doc: `{"Version": 1, "Actions": [{"Type": "Flood"}]}`, want: `recorder: unknown action type "Flood"`},
+		{doc: `{"Version": 1, "Actions": [{"Type": "FillString"}]}`, want: "recorder: missing font of FillString action"},
+	} {
+		err := json.Unmarshal([]byte(test.doc), &rec)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("unexpected error for %s: got:%v want:%s", test.doc, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"reflect"
+
+	"github.com/emptywe/plot/vg"
+)
+
+// Optimize returns the actions without the actions that do
+// not change the drawing, for a faster replay:
+//   - the style actions setting the color, line width, dashes,
+//     cap, join or miter limit to its current value;
+//   - the style actions whose value is set again, or restored
+//     by a Pop, before it is used by a drawing action;
+//   - the Push and Pop pairs enclosing no drawing action, with
+//     the actions between them.
+//
+// The drawing actions are the Stroke, Fill, FillString,
+// DrawImage, DrawGroup and Comment actions. The actions drawn
+// in groups are optimized recursively. The style of the canvas
+// before the actions is unknown, so that a style action is only
+// dropped as redundant after an action setting the same value.
+// The actions are not modified, and the returned actions share
+// the unchanged actions with the given actions.
+func Optimize(actions []Action) []Action {
+	return optimize(actions, styles{}, false)
+}
+
+// style is a kind of style action.
+type style int
+
+const (
+	colorStyle style = iota
+	widthStyle
+	dashStyle
+	capStyle
+	joinStyle
+	limitStyle
+	numStyles
+)
+
+// styles holds a value for each kind of style.
+type styles [numStyles]interface{}
+
+// styleOf returns the kind and the value of the style action a,
+// and whether a is a style action.
+func styleOf(a Action) (style, interface{}, bool) {
+	switch a := a.(type) {
+	case *SetColor:
+		return colorStyle, encodeColor(a.Color), true
+	case *SetLineWidth:
+		return widthStyle, a.Width, true
+	case *SetLineDash:
+		type dash struct {
+			dashes []vg.Length
+			offs   vg.Length
+		}
+		return dashStyle, dash{a.Dashes, a.Offsets}, true
+	case *SetLineCap:
+		return capStyle, a.Cap, true
+	case *SetLineJoin:
+		return joinStyle, a.Join, true
+	case *SetMiterLimit:
+		return limitStyle, a.Limit, true
+	}
+	return 0, nil, false
+}
+
+// uses returns the kinds of styles used by the action a,
+// and whether a is a drawing action.
+func uses(a Action) (used [numStyles]bool, draws bool) {
+	switch a.(type) {
+	case *Stroke, *DrawGroup:
+		for i := range used {
+			used[i] = true
+		}
+		return used, true
+	case *Fill, *FillString:
+		used[colorStyle] = true
+		return used, true
+	case *DrawImage, *Comment:
+		return used, true
+	}
+	return used, false
+}
+
+// optimize returns the optimized actions, with the known style
+// values before the actions, and whether the styles set by the
+// actions are used after them.
+func optimize(actions []Action, known styles, usedAfter bool) []Action {
+	pop, matched := matchPops(actions)
+	drop := make([]bool, len(actions))
+
+	// Drop the style actions setting the current value.
+	var (
+		cur   = known
+		stack []styles
+		dst   = make([]Action, len(actions))
+	)
+	for i, a := range actions {
+		dst[i] = a
+		if k, v, ok := styleOf(a); ok {
+			if cur[k] != nil && reflect.DeepEqual(cur[k], v) {
+				drop[i] = true
+			}
+			cur[k] = v
+			continue
+		}
+		switch a := a.(type) {
+		case *Push:
+			if pop[i] >= 0 {
+				stack = append(stack, cur)
+			}
+		case *Pop:
+			if !matched[i] {
+				cur = styles{}
+				break
+			}
+			cur = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		case *DrawGroup:
+			sub := optimize(a.Actions, cur, true)
+			if !sameActions(sub, a.Actions) {
+				g := *a
+				g.Actions = sub
+				dst[i] = &g
+			}
+			// The actions of the group change the
+			// state of the canvas if it does not
+			// implement vg.Grouper.
+			cur = styles{}
+		}
+	}
+
+	// Drop the style actions whose value is not used,
+	// skipping the style actions already dropped.
+	var (
+		needed [numStyles]bool
+		saved  [][numStyles]bool
+	)
+	for i := range needed {
+		needed[i] = usedAfter
+	}
+	for i := len(actions) - 1; i >= 0; i-- {
+		a := actions[i]
+		if k, _, ok := styleOf(a); ok {
+			if drop[i] {
+				continue
+			}
+			if !needed[k] {
+				drop[i] = true
+			}
+			needed[k] = false
+			continue
+		}
+		switch a.(type) {
+		case *Pop:
+			if matched[i] {
+				saved = append(saved, needed)
+				needed = [numStyles]bool{}
+			}
+		case *Push:
+			if pop[i] >= 0 {
+				after := saved[len(saved)-1]
+				saved = saved[:len(saved)-1]
+				for k := range needed {
+					needed[k] = needed[k] || after[k]
+				}
+			}
+		default:
+			used, _ := uses(a)
+			for k := range needed {
+				needed[k] = needed[k] || used[k]
+			}
+		}
+	}
+
+	// Drop the Push and Pop pairs enclosing no drawing action.
+	for i, a := range actions {
+		if _, ok := a.(*Push); !ok || pop[i] < 0 || drop[i] {
+			continue
+		}
+		empty := true
+		for _, a := range actions[i+1 : pop[i]] {
+			if _, draws := uses(a); draws {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			for j := i; j <= pop[i]; j++ {
+				drop[j] = true
+			}
+		}
+	}
+
+	n := 0
+	for i, a := range dst {
+		if !drop[i] {
+			dst[n] = a
+			n++
+		}
+	}
+	return dst[:n]
+}
+
+// matchPops returns the index of the Pop action matching each
+// Push action of the actions, or -1 for the other actions and
+// the Push actions without a matching Pop, and whether each
+// action is a Pop action matching a Push action.
+func matchPops(actions []Action) (pop []int, matched []bool) {
+	pop = make([]int, len(actions))
+	matched = make([]bool, len(actions))
+	var stack []int
+	for i, a := range actions {
+		pop[i] = -1
+		switch a.(type) {
+		case *Push:
+			stack = append(stack, i)
+		case *Pop:
+			if len(stack) == 0 {
+				continue
+			}
+			pop[stack[len(stack)-1]] = i
+			matched[i] = true
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return pop, matched
+}
+
+// sameActions returns whether a and b hold the same actions.
+func sameActions(a, b []Action) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recorder
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/emptywe/plot/vg"
+)
+
+func TestOptimize(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	path := vg.Path{{Type: vg.MoveComp}, {Type: vg.LineComp, Pos: vg.Point{X: 1, Y: 1}}}
+
+	for _, test := range []struct {
+		name string
+		draw func(c *Canvas)
+		want []string
+	}{
+		{
+			name: "redundant",
+			draw: func(c *Canvas) {
+				c.SetColor(red)
+				c.SetLineWidth(1)
+				c.Stroke(path)
+				c.SetColor(color.NRGBA{R: 0xff, A: 0xff})
+				c.SetLineWidth(1)
+				c.Stroke(path)
+			},
+			want: []string{
+				"SetColor(color.RGBA{R:0xff, G:0x0, B:0x0, A:0xff})",
+				"SetLineWidth(1)",
+				"Stroke(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+				"Stroke(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+			},
+		},
+		{
+			name: "overwritten",
+			draw: func(c *Canvas) {
+				c.SetColor(red)
+				c.SetLineWidth(2)
+				c.SetColor(blue)
+				c.Fill(path)
+				c.SetLineWidth(3)
+				c.SetLineWidth(4)
+				c.Comment("end")
+				c.SetColor(red)
+			},
+			want: []string{
+				"SetColor(color.RGBA{R:0x0, G:0x0, B:0xff, A:0xff})",
+				"Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+				`Comment("end")`,
+			},
+		},
+		{
+			name: "push pop",
+			draw: func(c *Canvas) {
+				c.SetColor(red)
+				c.Push()
+				c.SetColor(blue)
+				c.Translate(vg.Point{X: 1})
+				c.Push()
+				c.Clip(path)
+				c.Pop()
+				c.Comment("blue")
+				c.Pop()
+				c.SetColor(red)
+				c.Push()
+				c.Rotate(1)
+				c.SetColor(blue)
+				c.Pop()
+				c.Comment("red")
+			},
+			want: []string{
+				"Push()",
+				"Translate(1, 0)",
+				`Comment("blue")`,
+				"Pop()",
+				`Comment("red")`,
+			},
+		},
+		{
+			name: "restored",
+			draw: func(c *Canvas) {
+				c.SetColor(red)
+				c.Push()
+				c.SetColor(blue)
+				c.Fill(path)
+				c.Pop()
+				c.SetColor(red)
+				c.Fill(path)
+				c.Pop()
+				c.SetColor(red)
+				c.Fill(path)
+			},
+			want: []string{
+				"SetColor(color.RGBA{R:0xff, G:0x0, B:0x0, A:0xff})",
+				"Push()",
+				"SetColor(color.RGBA{R:0x0, G:0x0, B:0xff, A:0xff})",
+				"Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+				"Pop()",
+				"Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+				"Pop()",
+				"SetColor(color.RGBA{R:0xff, G:0x0, B:0x0, A:0xff})",
+				"Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+			},
+		},
+		{
+			name: "group",
+			draw: func(c *Canvas) {
+				c.SetColor(red)
+				c.DrawGroup(vg.Group{Opacity: 0.5}, func(c vg.Canvas) {
+					c.SetColor(red)
+					c.SetLineWidth(2)
+				})
+				c.SetColor(red)
+				c.Fill(path)
+			},
+			want: []string{
+				"SetColor(color.RGBA{R:0xff, G:0x0, B:0x0, A:0xff})",
+				"DrawGroup(vg.Group{Opacity:0.5, Blend:0}, {SetLineWidth(2)})",
+				"SetColor(color.RGBA{R:0xff, G:0x0, B:0x0, A:0xff})",
+				"Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:1, Y:1}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})",
+			},
+		},
+	} {
+		var c Canvas
+		test.draw(&c)
+		orig := append([]Action(nil), c.Actions...)
+		var got []string
+		for _, a := range Optimize(c.Actions) {
+			got = append(got, a.Call())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected actions for %s:\ngot: %q\nwant:%q", test.name, got, test.want)
+		}
+		if !reflect.DeepEqual(c.Actions, orig) {
+			t.Errorf("actions modified for %s", test.name)
+		}
+	}
+}
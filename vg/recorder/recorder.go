@@ -13,13 +13,20 @@ import (
 	"image/color"
 	"image/png"
 	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/emptywe/plot/font"
 	"github.com/emptywe/plot/font/liberation"
 	"github.com/emptywe/plot/vg"
 )
 
-var _ vg.Canvas = (*Canvas)(nil)
+var (
+	_ vg.Canvas     = (*Canvas)(nil)
+	_ vg.Clipper    = (*Canvas)(nil)
+	_ vg.LineStyler = (*Canvas)(nil)
+	_ vg.Grouper    = (*Canvas)(nil)
+)
 
 // Canvas implements vg.Canvas operation serialization.
 type Canvas struct {
@@ -38,6 +45,8 @@ type Canvas struct {
 	c vg.Canvas
 
 	// fonts holds a collection of font/size descriptions.
+	// mu protects fonts and cache during replays.
+	mu    sync.Mutex
 	fonts map[fontID]font.Face
 	cache *font.Cache
 }
@@ -80,30 +89,20 @@ func (c *Canvas) Reset() {
 
 // ReplayOn applies the set of Actions recorded by the Recorder onto
 // the destination Canvas.
+// ReplayOn may be called concurrently to replay the actions onto
+// several canvases, provided the actions are not modified.
 func (c *Canvas) ReplayOn(dst vg.Canvas) error {
+	c.mu.Lock()
 	if c.fonts == nil {
 		c.fonts = make(map[fontID]font.Face)
 	}
 	if c.cache == nil {
 		c.cache = font.NewCache(liberation.Collection())
 	}
-	for _, a := range c.Actions {
-		fa, ok := a.(*FillString)
-		if !ok {
-			continue
-		}
-		f := fontID{name: fa.Font.Name(), size: fa.Size}
-		if _, exists := c.fonts[f]; !exists {
-			if !c.cache.Has(fa.Font) {
-				return fmt.Errorf("unknown font: %s", fa.Font.Typeface)
-			}
-			face := c.cache.Lookup(
-				fa.Font,
-				fa.Size,
-			)
-			c.fonts[f] = face
-		}
-		fa.fonts = c.fonts
+	err := c.loadFonts(c.Actions)
+	c.mu.Unlock()
+	if err != nil {
+		return err
 	}
 	for _, a := range c.Actions {
 		a.ApplyTo(dst)
@@ -111,6 +110,36 @@ func (c *Canvas) ReplayOn(dst vg.Canvas) error {
 	return nil
 }
 
+// loadFonts loads the fonts used by the FillString actions,
+// including the actions of groups.
+func (c *Canvas) loadFonts(actions []Action) error {
+	for _, a := range actions {
+		switch a := a.(type) {
+		case *DrawGroup:
+			err := c.loadFonts(a.Actions)
+			if err != nil {
+				return err
+			}
+		case *FillString:
+			f := fontID{name: a.Font.Name(), size: a.Size}
+			if _, exists := c.fonts[f]; !exists {
+				if !c.cache.Has(a.Font) {
+					return fmt.Errorf("unknown font: %s", a.Font.Typeface)
+				}
+				face := c.cache.Lookup(
+					a.Font,
+					a.Size,
+				)
+				c.fonts[f] = face
+			}
+			if a.fonts == nil {
+				a.fonts = c.fonts
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Canvas) append(a Action) {
 	if c.c != nil {
 		a.ApplyTo(c)
@@ -203,6 +232,93 @@ func (a *SetColor) callerLocation() *callerLocation {
 	return &a.l
 }
 
+// SetLineCap corresponds to the vg.LineStyler.SetLineCap method.
+type SetLineCap struct {
+	Cap vg.LineCap
+
+	l callerLocation
+}
+
+// SetLineCap implements the SetLineCap method of the vg.LineStyler interface.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.append(&SetLineCap{Cap: capStyle})
+}
+
+// Call returns the method call that generated the action.
+func (a *SetLineCap) Call() string {
+	return fmt.Sprintf("%sSetLineCap(%v)", a.l, a.Cap)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+// The action is ignored if the canvas is not a vg.LineStyler.
+func (a *SetLineCap) ApplyTo(c vg.Canvas) {
+	if c, ok := c.(vg.LineStyler); ok {
+		c.SetLineCap(a.Cap)
+	}
+}
+
+func (a *SetLineCap) callerLocation() *callerLocation {
+	return &a.l
+}
+
+// SetLineJoin corresponds to the vg.LineStyler.SetLineJoin method.
+type SetLineJoin struct {
+	Join vg.LineJoin
+
+	l callerLocation
+}
+
+// SetLineJoin implements the SetLineJoin method of the vg.LineStyler interface.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.append(&SetLineJoin{Join: joinStyle})
+}
+
+// Call returns the method call that generated the action.
+func (a *SetLineJoin) Call() string {
+	return fmt.Sprintf("%sSetLineJoin(%v)", a.l, a.Join)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+// The action is ignored if the canvas is not a vg.LineStyler.
+func (a *SetLineJoin) ApplyTo(c vg.Canvas) {
+	if c, ok := c.(vg.LineStyler); ok {
+		c.SetLineJoin(a.Join)
+	}
+}
+
+func (a *SetLineJoin) callerLocation() *callerLocation {
+	return &a.l
+}
+
+// SetMiterLimit corresponds to the vg.LineStyler.SetMiterLimit method.
+type SetMiterLimit struct {
+	Limit float64
+
+	l callerLocation
+}
+
+// SetMiterLimit implements the SetMiterLimit method of the vg.LineStyler interface.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.append(&SetMiterLimit{Limit: limit})
+}
+
+// Call returns the method call that generated the action.
+func (a *SetMiterLimit) Call() string {
+	return fmt.Sprintf("%sSetMiterLimit(%v)", a.l, a.Limit)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+// The action is ignored if the canvas is not a vg.LineStyler.
+func (a *SetMiterLimit) ApplyTo(c vg.Canvas) {
+	if c, ok := c.(vg.LineStyler); ok {
+		c.SetMiterLimit(a.Limit)
+	}
+}
+
+func (a *SetMiterLimit) callerLocation() *callerLocation {
+	return &a.l
+}
+
 // Rotate corresponds to the vg.Canvas.Rotate method.
 type Rotate struct {
 	Angle float64
@@ -381,6 +497,82 @@ func (a *Fill) callerLocation() *callerLocation {
 	return &a.l
 }
 
+// Clip corresponds to the vg.Clipper.Clip method.
+type Clip struct {
+	Path vg.Path
+
+	l callerLocation
+}
+
+// Clip implements the Clip method of the vg.Clipper interface.
+func (c *Canvas) Clip(path vg.Path) {
+	c.append(&Clip{Path: append(vg.Path(nil), path...)})
+}
+
+// Call returns the method call that generated the action.
+func (a *Clip) Call() string {
+	return fmt.Sprintf("%sClip(%#v)", a.l, a.Path)
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+// ApplyTo is a no-op if the canvas does not implement vg.Clipper.
+func (a *Clip) ApplyTo(c vg.Canvas) {
+	if c, ok := c.(vg.Clipper); ok {
+		c.Clip(a.Path)
+	}
+}
+
+func (a *Clip) callerLocation() *callerLocation {
+	return &a.l
+}
+
+// DrawGroup corresponds to the vg.Grouper.DrawGroup method.
+type DrawGroup struct {
+	Group vg.Group
+
+	// Actions holds the actions drawn in the group.
+	Actions []Action
+
+	l callerLocation
+}
+
+// DrawGroup implements the DrawGroup method of the vg.Grouper interface.
+// The actions drawn by fn are recorded in the DrawGroup action.
+func (c *Canvas) DrawGroup(g vg.Group, fn func(vg.Canvas)) {
+	sub := &Canvas{KeepCaller: c.KeepCaller}
+	fn(sub)
+	c.append(&DrawGroup{Group: g, Actions: sub.Actions})
+}
+
+// Call returns the method call that generated the action.
+func (a *DrawGroup) Call() string {
+	calls := make([]string, len(a.Actions))
+	for i, a := range a.Actions {
+		calls[i] = a.Call()
+	}
+	return fmt.Sprintf("%sDrawGroup(%#v, {%s})", a.l, a.Group, strings.Join(calls, "; "))
+}
+
+// ApplyTo applies the action to the given vg.Canvas.
+// The actions of the group are applied directly to the
+// canvas if it does not implement vg.Grouper.
+func (a *DrawGroup) ApplyTo(c vg.Canvas) {
+	apply := func(c vg.Canvas) {
+		for _, a := range a.Actions {
+			a.ApplyTo(c)
+		}
+	}
+	if g, ok := c.(vg.Grouper); ok {
+		g.DrawGroup(a.Group, apply)
+		return
+	}
+	apply(c)
+}
+
+func (a *DrawGroup) callerLocation() *callerLocation {
+	return &a.l
+}
+
 // FillString corresponds to the vg.Canvas.FillString method.
 type FillString struct {
 	Font   font.Font
@@ -30,8 +30,16 @@ func TestRecorder(t *testing.T) {
 	rec.KeepCaller = false
 	rec.SetLineWidth(100)
 	rec.SetLineDash([]font.Length{2, 5}, 6)
+	rec.SetLineCap(vg.SquareCap)
+	rec.SetLineJoin(vg.MiterJoin)
+	rec.SetMiterLimit(4)
 	rec.SetColor(color.RGBA{R: 0x65, G: 0x23, B: 0xf2})
 	rec.Fill(vg.Path{{Type: vg.MoveComp, Pos: vg.Point{X: 3, Y: 4}}, {Type: vg.LineComp, Pos: vg.Point{X: 2, Y: 3}}, {Type: vg.CloseComp}})
+	rec.Clip(vg.Path{{Type: vg.MoveComp, Pos: vg.Point{X: 1, Y: 2}}})
+	rec.DrawGroup(vg.Group{Opacity: 0.5, Blend: vg.BlendMultiply}, func(c vg.Canvas) {
+		c.SetLineWidth(2)
+		c.SetColor(color.Black)
+	})
 	rec.DrawImage(
 		vg.Rectangle{
 			Min: vg.Point{X: 0, Y: 0},
@@ -85,7 +93,12 @@ var want = []string{
 	`github.com/emptywe/plot/vg/recorder/recorder_test.go:29 Translate(3, 4)`,
 	`SetLineWidth(100)`,
 	`SetLineDash([]font.Length{2, 5}, 6)`,
+	`SetLineCap(SquareCap)`,
+	`SetLineJoin(MiterJoin)`,
+	`SetMiterLimit(4)`,
 	`SetColor(color.RGBA{R:0x65, G:0x23, B:0xf2, A:0x0})`,
 	`Fill(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:3, Y:4}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:1, Pos:vg.Point{X:2, Y:3}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}, vg.PathComp{Type:4, Pos:vg.Point{X:0, Y:0}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})`,
+	`Clip(vg.Path{vg.PathComp{Type:0, Pos:vg.Point{X:1, Y:2}, Control:[]vg.Point(nil), Radius:0, Start:0, Angle:0}})`,
+	`DrawGroup(vg.Group{Opacity:0.5, Blend:1}, {SetLineWidth(2); SetColor(color.Gray16{Y:0x0})})`,
 	`DrawImage(vg.Rectangle{Min:vg.Point{X:0, Y:0}, Max:vg.Point{X:10, Y:10}}, {image.Rectangle{Min:image.Point{X:0, Y:0}, Max:image.Point{X:20, Y:20}}, IMAGE:iVBORw0KGgoAAAANSUhEUgAAABQAAAAUCAAAAACo4kLRAAAAFElEQVR4nGJiwAJGBQeVICAAAP//JBgAKeMueQ8AAAAASUVORK5CYII=})`,
 }
@@ -126,4 +126,63 @@ func (tee teeCanvas) DrawImage(rect Rectangle, img image.Image) {
 	}
 }
 
-var _ Canvas = (*teeCanvas)(nil)
+// Clip clips the drawing operations of the canvases
+// that implement the Clipper interface to the given path.
+// Canvases that do not implement Clipper are not clipped.
+func (tee teeCanvas) Clip(p Path) {
+	for _, c := range tee.cs {
+		if c, ok := c.(Clipper); ok {
+			c.Clip(p)
+		}
+	}
+}
+
+// SetLineCap sets the line cap of the canvases
+// that implement the LineStyler interface.
+func (tee teeCanvas) SetLineCap(capStyle LineCap) {
+	for _, c := range tee.cs {
+		if c, ok := c.(LineStyler); ok {
+			c.SetLineCap(capStyle)
+		}
+	}
+}
+
+// SetLineJoin sets the line join of the canvases
+// that implement the LineStyler interface.
+func (tee teeCanvas) SetLineJoin(joinStyle LineJoin) {
+	for _, c := range tee.cs {
+		if c, ok := c.(LineStyler); ok {
+			c.SetLineJoin(joinStyle)
+		}
+	}
+}
+
+// SetMiterLimit sets the miter limit of the canvases
+// that implement the LineStyler interface.
+func (tee teeCanvas) SetMiterLimit(limit float64) {
+	for _, c := range tee.cs {
+		if c, ok := c.(LineStyler); ok {
+			c.SetMiterLimit(limit)
+		}
+	}
+}
+
+// DrawGroup draws the group on each canvas, calling fn once
+// per canvas. Canvases that do not implement the Grouper
+// interface draw the group directly.
+func (tee teeCanvas) DrawGroup(g Group, fn func(Canvas)) {
+	for _, c := range tee.cs {
+		if c, ok := c.(Grouper); ok {
+			c.DrawGroup(g, fn)
+			continue
+		}
+		fn(c)
+	}
+}
+
+var (
+	_ Canvas     = (*teeCanvas)(nil)
+	_ Clipper    = (*teeCanvas)(nil)
+	_ LineStyler = (*teeCanvas)(nil)
+	_ Grouper    = (*teeCanvas)(nil)
+)
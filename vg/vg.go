@@ -8,9 +8,11 @@
 package vg // import "github.com/emptywe/plot/vg"
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"io"
+	"math"
 
 	"github.com/emptywe/plot/font"
 )
@@ -96,12 +98,134 @@ type CanvasWriterTo interface {
 	io.WriterTo
 }
 
+// Clipper is a Canvas that can restrict its drawing
+// operations to a region of the canvas.
+type Clipper interface {
+	Canvas
+
+	// Clip intersects the current clipping region with
+	// the interior of the given path, as it would be
+	// filled by Fill.
+	// Subsequent drawing operations, including text and
+	// images, only affect the canvas inside the clipping
+	// region.
+	//
+	// The clipping region is saved by Push and restored
+	// by the corresponding call to Pop.
+	Clip(Path)
+}
+
+// LineCap is the shape of the ends of stroked lines.
+type LineCap int
+
+const (
+	// ButtCap ends lines squarely at their end points.
+	ButtCap LineCap = iota
+
+	// RoundCap ends lines with a half circle.
+	RoundCap
+
+	// SquareCap ends lines squarely, half of the
+	// line width beyond their end points.
+	SquareCap
+)
+
+// String returns the name of the line cap.
+func (c LineCap) String() string {
+	switch c {
+	case ButtCap:
+		return "ButtCap"
+	case RoundCap:
+		return "RoundCap"
+	case SquareCap:
+		return "SquareCap"
+	}
+	return fmt.Sprintf("LineCap(%d)", int(c))
+}
+
+// LineJoin is the shape of the corners of stroked lines.
+type LineJoin int
+
+const (
+	// RoundJoin rounds the corners of lines.
+	RoundJoin LineJoin = iota
+
+	// MiterJoin extends the outer edges of lines until
+	// they meet, as long as the ratio of the miter length
+	// to the line width does not exceed the miter limit.
+	// Corners exceeding the limit are beveled.
+	MiterJoin
+
+	// BevelJoin cuts the corners of lines.
+	BevelJoin
+)
+
+// String returns the name of the line join.
+func (j LineJoin) String() string {
+	switch j {
+	case RoundJoin:
+		return "RoundJoin"
+	case MiterJoin:
+		return "MiterJoin"
+	case BevelJoin:
+		return "BevelJoin"
+	}
+	return fmt.Sprintf("LineJoin(%d)", int(j))
+}
+
+// DefaultMiterLimit is the initial miter limit of canvases.
+const DefaultMiterLimit = 10
+
+// LineStyler is a Canvas that can set the shape of the
+// ends and corners of stroked lines.
+// The line cap, line join and miter limit are saved by
+// Push and restored by the corresponding call to Pop.
+type LineStyler interface {
+	Canvas
+
+	// SetLineCap sets the shape of the ends of
+	// stroked lines.
+	//
+	// The initial line cap is ButtCap.
+	SetLineCap(LineCap)
+
+	// SetLineJoin sets the shape of the corners
+	// of stroked lines.
+	//
+	// The initial line join is RoundJoin.
+	SetLineJoin(LineJoin)
+
+	// SetMiterLimit sets the limit on the ratio of the
+	// miter length to the line width of MiterJoin corners.
+	//
+	// The initial miter limit is DefaultMiterLimit.
+	SetMiterLimit(float64)
+}
+
+// Clearer is a Canvas that can replace the content of a
+// region, such as raster canvases, that are created with
+// an opaque background.
+type Clearer interface {
+	Canvas
+
+	// Clear replaces the content of the rectangle by the
+	// color, that may be transparent. The rectangle is
+	// transformed by the current transform, and the
+	// clipping region is ignored.
+	Clear(Rectangle, color.Color)
+}
+
 // Initialize sets all of the canvas's values to their
 // initial values.
 func Initialize(c Canvas) {
 	c.SetLineWidth(Points(1))
 	c.SetLineDash([]Length{}, 0)
 	c.SetColor(color.Black)
+	if c, ok := c.(LineStyler); ok {
+		c.SetLineCap(ButtCap)
+		c.SetLineJoin(RoundJoin)
+		c.SetMiterLimit(DefaultMiterLimit)
+	}
 }
 
 type Path []PathComp
@@ -149,6 +273,64 @@ func (p *Path) Close() {
 	*p = append(*p, PathComp{Type: CloseComp})
 }
 
+// Bounds returns the smallest rectangle containing
+// all the points of the path.
+// The control points of curves are included in the
+// bounds.
+func (p Path) Bounds() Rectangle {
+	var (
+		r     Rectangle
+		empty = true
+	)
+	add := func(pt Point) {
+		if empty {
+			r = Rectangle{Min: pt, Max: pt}
+			empty = false
+			return
+		}
+		if pt.X < r.Min.X {
+			r.Min.X = pt.X
+		}
+		if pt.Y < r.Min.Y {
+			r.Min.Y = pt.Y
+		}
+		if pt.X > r.Max.X {
+			r.Max.X = pt.X
+		}
+		if pt.Y > r.Max.Y {
+			r.Max.Y = pt.Y
+		}
+	}
+	for _, comp := range p {
+		switch comp.Type {
+		case MoveComp, LineComp:
+			add(comp.Pos)
+		case CurveComp:
+			for _, pt := range comp.Control {
+				add(pt)
+			}
+			add(comp.Pos)
+		case ArcComp:
+			at := func(a float64) Point {
+				return Point{
+					X: comp.Pos.X + comp.Radius*Length(math.Cos(a)),
+					Y: comp.Pos.Y + comp.Radius*Length(math.Sin(a)),
+				}
+			}
+			s, e := comp.Start, comp.Start+comp.Angle
+			if e < s {
+				s, e = e, s
+			}
+			add(at(s))
+			add(at(e))
+			for a := math.Ceil(s/(math.Pi/2)) * math.Pi / 2; a < e; a += math.Pi / 2 {
+				add(at(a))
+			}
+		}
+	}
+	return r
+}
+
 // Constants that tag the type of each path
 // component.
 const (
@@ -7,6 +7,7 @@ package vg_test
 import (
 	"bytes"
 	"fmt"
+	"image/color"
 	"log"
 	"os"
 	"path/filepath"
@@ -17,6 +18,7 @@ import (
 	"github.com/emptywe/plot/cmpimg"
 	"github.com/emptywe/plot/plotter"
 	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
 )
 
 // TestLineWidth tests output against test images generated by
@@ -85,6 +87,82 @@ func TestLineWidth(t *testing.T) {
 	}
 }
 
+// TestLineCapJoin tests the line caps, line joins and miter
+// limits of all backends against golden files.
+func TestLineCapJoin(t *testing.T) {
+	const (
+		width  = 3 * vg.Inch
+		height = 3 * vg.Inch
+	)
+	for _, typ := range []string{"pdf", "svg", "png", "eps", "tex"} {
+		c, err := draw.NewFormattedCanvas(width, height, typ)
+		if err != nil {
+			t.Fatalf("failed to create canvas for %s: %v", typ, err)
+		}
+		dc := draw.New(c)
+		caps := []vg.LineCap{vg.ButtCap, vg.RoundCap, vg.SquareCap}
+		joins := []vg.LineJoin{vg.RoundJoin, vg.MiterJoin, vg.BevelJoin}
+		for i, capStyle := range caps {
+			for j, joinStyle := range joins {
+				x := vg.Length(i)*width/3 + 10
+				y := vg.Length(j)*height/3 + 15
+				sty := draw.LineStyle{
+					Color: color.Black,
+					Width: vg.Points(8),
+					Cap:   capStyle,
+					Join:  joinStyle,
+				}
+				dc.StrokeLines(sty, []vg.Point{
+					{X: x, Y: y},
+					{X: x + 15, Y: y + 35},
+					{X: x + 30, Y: y},
+					{X: x + 45, Y: y + 35},
+				})
+				// A sharp corner exceeds the miter
+				// limit and is beveled.
+				sty.Width = vg.Points(2)
+				sty.MiterLimit = 2
+				dc.StrokeLines(sty, []vg.Point{
+					{X: x + 50, Y: y + 40},
+					{X: x + 56, Y: y},
+					{X: x + 62, Y: y + 40},
+				})
+			}
+		}
+
+		var buf bytes.Buffer
+		if _, err = c.WriteTo(&buf); err != nil {
+			t.Fatalf("failed to write canvas for %s: %v", typ, err)
+		}
+
+		name := filepath.Join(".", "testdata", "linecapjoin_golden."+typ)
+		if *cmpimg.GenerateTestData {
+			err = os.WriteFile(name, buf.Bytes(), 0644)
+			if err != nil {
+				t.Fatalf("failed to save %q: %v", name, err)
+			}
+		}
+
+		want, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read test image [%s]: %v\n", name, err)
+		}
+
+		ok, err := cmpimg.Equal(typ, buf.Bytes(), want)
+		if err != nil {
+			t.Fatalf("failed to run cmpimg test [%s]: %v\n", name, err)
+		}
+		if !ok {
+			got := strings.Replace(name, "_golden.", ".", 1)
+			err = os.WriteFile(got, buf.Bytes(), 0644)
+			if err != nil {
+				t.Errorf("could not write image %q: %+v", got, err)
+			}
+			t.Errorf("image mismatch for %s", typ)
+		}
+	}
+}
+
 func lines(w vg.Length) (*plot.Plot, error) {
 	p := plot.New()
 	pts := plotter.XYs{
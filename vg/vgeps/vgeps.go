@@ -9,11 +9,14 @@ package vgeps // import "github.com/emptywe/plot/vg/vgeps"
 import (
 	"bufio"
 	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
 	"fmt"
 	"image"
 	"image/color"
 	"io"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/emptywe/plot/font"
@@ -34,6 +37,12 @@ type Canvas struct {
 	stack []context
 	w, h  vg.Length
 	buf   *bytes.Buffer
+
+	// space is the color space of the colors and
+	// images written to the EPS, and cmyk the
+	// conversion of colors to DeviceCMYK.
+	space vg.ColorSpace
+	cmyk  vg.CMYKConverter
 }
 
 type context struct {
@@ -43,6 +52,9 @@ type context struct {
 	offs   vg.Length
 	font   string
 	fsize  vg.Length
+	cap    vg.LineCap
+	join   vg.LineJoin
+	miter  float64
 }
 
 // pr is the amount of precision to use when outputting float64s.
@@ -56,7 +68,7 @@ func New(w, h vg.Length) *Canvas {
 // NewTitle returns a new Canvas with the given title string.
 func NewTitle(w, h vg.Length, title string) *Canvas {
 	c := &Canvas{
-		stack: []context{{}},
+		stack: []context{{miter: vg.DefaultMiterLimit}},
 		w:     w,
 		h:     h,
 		buf:   new(bytes.Buffer),
@@ -71,10 +83,24 @@ func NewTitle(w, h vg.Length, title string) *Canvas {
 	c.buf.WriteString("%%Orientation: Portrait\n")
 	c.buf.WriteString("%%EndComments\n")
 	c.buf.WriteString("\n")
+	c.buf.WriteString("1 setlinejoin\n")
 	vg.Initialize(c)
 	return c
 }
 
+// SetColorSpace sets the color space of the colors and images
+// drawn on the canvas. Colors are converted to DeviceCMYK with
+// conv, that may be nil to use the default conversion of
+// vg.CMYKConverter. conv is ignored for other color spaces.
+// The color space should be set before drawing on the canvas.
+func (e *Canvas) SetColorSpace(cs vg.ColorSpace, conv vg.CMYKConverter) {
+	e.space = cs
+	e.cmyk = conv
+	clr := e.context().color
+	e.context().color = nil
+	e.SetColor(clr)
+}
+
 func (c *Canvas) Size() (w, h vg.Length) {
 	return c.w, c.h
 }
@@ -111,15 +137,86 @@ func (e *Canvas) SetLineDash(dashes []vg.Length, o vg.Length) {
 	}
 }
 
+// SetLineCap implements the vg.LineStyler interface.
+func (e *Canvas) SetLineCap(capStyle vg.LineCap) {
+	if e.context().cap != capStyle {
+		e.context().cap = capStyle
+		fmt.Fprintf(e.buf, "%d setlinecap\n", psLineCap(capStyle))
+	}
+}
+
+// SetLineJoin implements the vg.LineStyler interface.
+func (e *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	if e.context().join != joinStyle {
+		e.context().join = joinStyle
+		fmt.Fprintf(e.buf, "%d setlinejoin\n", psLineJoin(joinStyle))
+	}
+}
+
+// SetMiterLimit implements the vg.LineStyler interface.
+func (e *Canvas) SetMiterLimit(limit float64) {
+	if e.context().miter != limit {
+		e.context().miter = limit
+		fmt.Fprintf(e.buf, "%.*g setmiterlimit\n", pr, limit)
+	}
+}
+
+// psLineCap returns the PostScript code of the line cap.
+func psLineCap(capStyle vg.LineCap) int {
+	switch capStyle {
+	case vg.RoundCap:
+		return 1
+	case vg.SquareCap:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// psLineJoin returns the PostScript code of the line join.
+func psLineJoin(joinStyle vg.LineJoin) int {
+	switch joinStyle {
+	case vg.MiterJoin:
+		return 0
+	case vg.BevelJoin:
+		return 2
+	default:
+		return 1
+	}
+}
+
 func (e *Canvas) SetColor(c color.Color) {
 	if c == nil {
 		c = color.Black
 	}
 	if e.context().color != c {
 		e.context().color = c
+		e.components(c)
+		switch e.space {
+		case vg.DeviceCMYK:
+			e.buf.WriteString(" setcmykcolor\n")
+		case vg.DeviceGray:
+			e.buf.WriteString(" setgray\n")
+		default:
+			e.buf.WriteString(" setrgbcolor\n")
+		}
+	}
+}
+
+// components writes the components of the color in the
+// color space of the canvas.
+func (e *Canvas) components(c color.Color) {
+	switch e.space {
+	case vg.DeviceCMYK:
+		v := e.cmyk.CMYK(c)
+		fmt.Fprintf(e.buf, "%.*g %.*g %.*g %.*g", pr, float64(v.C)/255,
+			pr, float64(v.M)/255, pr, float64(v.Y)/255, pr, float64(v.K)/255)
+	case vg.DeviceGray:
+		fmt.Fprintf(e.buf, "%.*g", pr, vg.GrayLevel(c))
+	default:
 		r, g, b, _ := c.RGBA()
 		mx := float64(math.MaxUint16)
-		fmt.Fprintf(e.buf, "%.*g %.*g %.*g setrgbcolor\n", pr, float64(r)/mx,
+		fmt.Fprintf(e.buf, "%.*g %.*g %.*g", pr, float64(r)/mx,
 			pr, float64(g)/mx, pr, float64(b)/mx)
 	}
 }
@@ -156,10 +253,92 @@ func (e *Canvas) Stroke(path vg.Path) {
 }
 
 func (e *Canvas) Fill(path vg.Path) {
+	if h, ok := e.context().color.(*vg.Hatch); ok {
+		vg.FillHatch(e, path, h)
+		return
+	}
+	if g, ok := e.context().color.(vg.Gradient); ok && e.shade(path, g) {
+		return
+	}
 	e.trace(path)
 	e.buf.WriteString("fill\n")
 }
 
+// shade fills the path with the gradient using a PostScript
+// shading. shade returns false if the gradient can not be
+// represented as a shading.
+func (e *Canvas) shade(path vg.Path, g vg.Gradient) bool {
+	var stops []vg.GradientStop
+	switch g := g.(type) {
+	case *vg.LinearGradient:
+		stops = g.Stops
+	case *vg.RadialGradient:
+		stops = g.Stops
+	default:
+		return false
+	}
+	if len(stops) < 2 || stops[len(stops)-1].Offset <= stops[0].Offset {
+		return false
+	}
+
+	// The shading spans from the first to the last stop
+	// and is extended beyond them.
+	o0, o1 := stops[0].Offset, stops[len(stops)-1].Offset
+	var (
+		typ    int
+		coords []float64
+	)
+	switch g := g.(type) {
+	case *vg.LinearGradient:
+		dx, dy := g.X2-g.X1, g.Y2-g.Y1
+		typ = 2
+		coords = []float64{g.X1 + o0*dx, g.Y1 + o0*dy, g.X1 + o1*dx, g.Y1 + o1*dy}
+	case *vg.RadialGradient:
+		typ = 3
+		coords = []float64{g.CX, g.CY, g.R * o0, g.CX, g.CY, g.R * o1}
+	}
+
+	rect := path.Bounds()
+	size := rect.Size()
+	if size.X == 0 || size.Y == 0 {
+		return false
+	}
+	e.Push()
+	e.Clip(path)
+	fmt.Fprintf(e.buf, "[%.*g 0 0 %.*g %.*g %.*g] concat\n",
+		pr, size.X.Dots(DPI), pr, size.Y.Dots(DPI), pr, rect.Min.X.Dots(DPI), pr, rect.Min.Y.Dots(DPI))
+	fmt.Fprintf(e.buf, "<< /ShadingType %d /ColorSpace /%v /Coords [", typ, e.space)
+	for _, v := range coords {
+		fmt.Fprintf(e.buf, " %.*g", pr, v)
+	}
+	e.buf.WriteString(" ] /Extend [true true]\n")
+	e.buf.WriteString("/Function << /FunctionType 3 /Domain [0 1] /Functions [\n")
+	for i := 1; i < len(stops); i++ {
+		e.buf.WriteString("<< /FunctionType 2 /Domain [0 1] /C0 [")
+		e.components(stops[i-1].Color)
+		e.buf.WriteString("] /C1 [")
+		e.components(stops[i].Color)
+		e.buf.WriteString("] /N 1 >>\n")
+	}
+	e.buf.WriteString("] /Bounds [")
+	for _, s := range stops[1 : len(stops)-1] {
+		fmt.Fprintf(e.buf, " %.*g", pr, (s.Offset-o0)/(o1-o0))
+	}
+	e.buf.WriteString(" ] /Encode [")
+	for i := 1; i < len(stops); i++ {
+		e.buf.WriteString(" 0 1")
+	}
+	e.buf.WriteString(" ] >>\n>> shfill\n")
+	e.Pop()
+	return true
+}
+
+// Clip implements the vg.Clipper interface.
+func (e *Canvas) Clip(path vg.Path) {
+	e.trace(path)
+	e.buf.WriteString("clip\nnewpath\n")
+}
+
 func (e *Canvas) trace(path vg.Path) {
 	e.buf.WriteString("newpath\n")
 	for _, comp := range path {
@@ -211,9 +390,67 @@ func (e *Canvas) FillString(fnt font.Face, pt vg.Point, str string) {
 }
 
 // DrawImage implements the vg.Canvas.DrawImage method.
-func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
-	// FIXME: https://github.com/gonum/plot/issues/271
-	panic("vgeps: DrawImage not implemented")
+// As PostScript does not support transparency, transparent
+// pixels are drawn over a white background.
+func (e *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+	n := 3
+	switch e.space {
+	case vg.DeviceCMYK:
+		n = 4
+	case vg.DeviceGray:
+		n = 1
+	}
+	data := make([]byte, 0, n*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.At(x, y)
+			if r, g, bl, a := c.RGBA(); a != math.MaxUint16 {
+				w := math.MaxUint16 - a
+				c = color.RGBA64{R: uint16(r + w), G: uint16(g + w), B: uint16(bl + w), A: math.MaxUint16}
+			}
+			switch e.space {
+			case vg.DeviceCMYK:
+				v := e.cmyk.CMYK(c)
+				data = append(data, v.C, v.M, v.Y, v.K)
+			case vg.DeviceGray:
+				data = append(data, uint8(math.Round(255*vg.GrayLevel(c))))
+			default:
+				v := color.RGBAModel.Convert(c).(color.RGBA)
+				data = append(data, v.R, v.G, v.B)
+			}
+		}
+	}
+
+	size := rect.Size()
+	e.buf.WriteString("gsave\n")
+	fmt.Fprintf(e.buf, "%.*g %.*g translate\n", pr, rect.Min.X.Dots(DPI), pr, rect.Min.Y.Dots(DPI))
+	fmt.Fprintf(e.buf, "%.*g %.*g scale\n", pr, size.X.Dots(DPI), pr, size.Y.Dots(DPI))
+	fmt.Fprintf(e.buf, "/%v setcolorspace\n", e.space)
+	fmt.Fprintf(e.buf, "<< /ImageType 1 /Width %d /Height %d /BitsPerComponent 8 /Decode [%s]\n",
+		b.Dx(), b.Dy(), strings.TrimSpace(strings.Repeat(" 0 1", n)))
+	fmt.Fprintf(e.buf, "/ImageMatrix [%d 0 0 %d 0 %d]\n", b.Dx(), -b.Dy(), b.Dy())
+	e.buf.WriteString("/DataSource currentfile /ASCII85Decode filter /FlateDecode filter >> image\n")
+
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(data)
+	zw.Close()
+	a85 := make([]byte, ascii85.MaxEncodedLen(z.Len()))
+	a85 = a85[:ascii85.Encode(a85, z.Bytes())]
+	// Keep lines shorter than the 255 characters
+	// recommended by the DSC.
+	const width = 76
+	for len(a85) > width {
+		e.buf.Write(a85[:width])
+		e.buf.WriteByte('\n')
+		a85 = a85[width:]
+	}
+	e.buf.Write(a85)
+	e.buf.WriteString("~>\ngrestore\n")
 }
 
 // WriteTo writes the canvas to an io.Writer.
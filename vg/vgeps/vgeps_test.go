@@ -0,0 +1,93 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgeps_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/vgeps"
+)
+
+func TestColorSpace(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 128})
+
+	for _, tc := range []struct {
+		name  string
+		space vg.ColorSpace
+		want  []string
+	}{
+		{
+			name:  "rgb",
+			space: vg.DeviceRGB,
+			want: []string{
+				"1 0 0 setrgbcolor\n",
+				"/C0 [1 1 1] /C1 [0 0 1]",
+				"/DeviceRGB setcolorspace\n",
+				"/Decode [0 1 0 1 0 1]\n",
+			},
+		},
+		{
+			name:  "cmyk",
+			space: vg.DeviceCMYK,
+			want: []string{
+				"0 1 1 0 setcmykcolor\n",
+				"0.039216 0.078431 0.11765 0.15686 setcmykcolor\n",
+				"/ShadingType 2 /ColorSpace /DeviceCMYK",
+				"/C0 [0 0 0 0] /C1 [1 1 0 0]",
+				"/DeviceCMYK setcolorspace\n",
+				"/Decode [0 1 0 1 0 1 0 1]\n",
+			},
+		},
+		{
+			name:  "gray",
+			space: vg.DeviceGray,
+			want: []string{
+				"0.299 setgray\n",
+				"/C0 [1] /C1 [0.114]",
+				"/DeviceGray setcolorspace\n",
+				"/Decode [0 1]\n",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := vgeps.New(100, 100)
+			c.SetColorSpace(tc.space, nil)
+
+			var p vg.Path
+			p.Move(vg.Point{X: 10, Y: 10})
+			p.Line(vg.Point{X: 50, Y: 10})
+			p.Line(vg.Point{X: 50, Y: 50})
+			p.Close()
+			c.SetColor(color.RGBA{R: 255, A: 255})
+			c.Fill(p)
+			c.SetColor(color.CMYK{C: 10, M: 20, Y: 30, K: 40})
+			c.Fill(p)
+			c.SetColor(&vg.LinearGradient{X2: 1, Stops: []vg.GradientStop{
+				{Color: color.White},
+				{Offset: 1, Color: color.RGBA{B: 255, A: 255}},
+			}})
+			c.Fill(p)
+			c.DrawImage(vg.Rectangle{Max: vg.Point{X: 20, Y: 20}}, img)
+
+			var buf bytes.Buffer
+			_, err := c.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("could not write canvas: %v", err)
+			}
+			got := buf.String()
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing %q", want)
+				}
+			}
+		})
+	}
+}
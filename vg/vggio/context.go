@@ -33,7 +33,14 @@ type context struct {
 	linew   vg.Length   // linew is the current line width.
 	pattern []vg.Length // pattern is the current line style.
 	offset  vg.Length   // offset is the current line style.
+	cap     vg.LineCap  // cap is the current line cap.
+	join    vg.LineJoin // join is the current line join.
+	miter   float64     // miter is the current miter limit.
 	state   op.StateOp  // state is the Gio op context state.
+
+	// clips holds the Gio clip operations pushed
+	// since the context was saved.
+	clips []clip.Stack
 }
 
 func (ctx *ctxops) cur() *context {
@@ -43,13 +50,25 @@ func (ctx *ctxops) cur() *context {
 func (ctx *ctxops) push() {
 	ctx.ctx = append(ctx.ctx, *ctx.cur())
 	ctx.cur().state = op.Save(ctx.ops)
+	ctx.cur().clips = nil
 }
 
 func (ctx *ctxops) pop() {
-	ctx.cur().state.Load()
+	cur := ctx.cur()
+	for i := len(cur.clips) - 1; i >= 0; i-- {
+		cur.clips[i].Pop()
+	}
+	cur.state.Load()
 	ctx.ctx = ctx.ctx[:len(ctx.ctx)-1]
 }
 
+// clip pushes the clip operation onto the Gio clip stack.
+// The operation is popped by the next call to pop.
+func (ctx *ctxops) clip(o clip.Op) {
+	cur := ctx.cur()
+	cur.clips = append(cur.clips, o.Push(ctx.ops))
+}
+
 func (ctx *ctxops) scale(x, y float64) {
 	ops := ctx.ops
 	aff := f32.Affine2D{}.Scale(
@@ -16,6 +16,7 @@ import (
 	"strings"
 	"sync"
 
+	"gioui.org/f32"
 	"gioui.org/font/opentype"
 	"gioui.org/gpu/headless"
 	"gioui.org/layout"
@@ -34,6 +35,8 @@ import (
 var (
 	_ vg.Canvas      = (*Canvas)(nil)
 	_ vg.CanvasSizer = (*Canvas)(nil)
+	_ vg.Clipper     = (*Canvas)(nil)
+	_ vg.LineStyler  = (*Canvas)(nil)
 )
 
 // Canvas implements the vg.Canvas interface,
@@ -66,7 +69,7 @@ func New(gtx layout.Context, w, h vg.Length, opts ...option) *Canvas {
 		ctx: ctxops{
 			ops: gtx.Ops,
 			ctx: []context{
-				{color: color.Black},
+				{color: color.Black, miter: vg.DefaultMiterLimit},
 			},
 			w:   w,
 			h:   h,
@@ -170,6 +173,28 @@ func (c *Canvas) SetLineDash(pattern []vg.Length, offset vg.Length) {
 	cur.offset = offset
 }
 
+// SetLineCap sets the shape of the ends of lines.
+//
+// The initial line cap is vg.ButtCap.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.ctx.cur().cap = capStyle
+}
+
+// SetLineJoin sets the shape of the corners of lines.
+//
+// The initial line join is vg.RoundJoin.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.ctx.cur().join = joinStyle
+}
+
+// SetMiterLimit sets the miter limit of vg.MiterJoin
+// corners.
+//
+// The initial miter limit is vg.DefaultMiterLimit.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.ctx.cur().miter = limit
+}
+
 // SetColor sets the current drawing color.
 // Note that fill color and stroke color are
 // the same, so if you want different fill
@@ -236,12 +261,30 @@ func (c *Canvas) Stroke(p vg.Path) {
 		dashes.Dash(float32(v.Dots(c.ctx.dpi)))
 	}
 
+	style := clip.StrokeStyle{
+		Width: float32(cur.linew.Dots(c.ctx.dpi)),
+		Cap:   clip.FlatCap,
+		Join:  clip.RoundJoin,
+	}
+	switch cur.cap {
+	case vg.RoundCap:
+		style.Cap = clip.RoundCap
+	case vg.SquareCap:
+		style.Cap = clip.SquareCap
+	}
+	switch cur.join {
+	case vg.MiterJoin:
+		// Gio draws miter joins as bevel joins
+		// with a non-zero miter limit.
+		style.Join = clip.BevelJoin
+		style.Miter = float32(cur.miter)
+	case vg.BevelJoin:
+		style.Join = clip.BevelJoin
+	}
+
 	clip.Stroke{
-		Path: c.outline(p),
-		Style: clip.StrokeStyle{
-			Width: float32(cur.linew.Dots(c.ctx.dpi)),
-			Cap:   clip.FlatCap,
-		},
+		Path:   c.outline(p),
+		Style:  style,
 		Dashes: dashes.End(),
 	}.Op().Add(c.gtx.Ops)
 
@@ -252,6 +295,15 @@ func (c *Canvas) Stroke(p vg.Path) {
 
 // Fill fills the given path.
 func (c *Canvas) Fill(p vg.Path) {
+	if h, ok := c.ctx.cur().color.(*vg.Hatch); ok {
+		vg.FillHatch(c, p, h)
+		return
+	}
+	if g, ok := c.ctx.cur().color.(vg.Gradient); ok {
+		c.fillGradient(p, g)
+		return
+	}
+
 	c.ctx.push()
 	defer c.ctx.pop()
 
@@ -264,6 +316,55 @@ func (c *Canvas) Fill(p vg.Path) {
 	paint.FillShape(c.gtx.Ops, rgba(clr), r32.Op())
 }
 
+// fillGradient fills the path with the gradient, using a gio
+// linear gradient for linear gradients with at most two stops
+// and a rasterized image of the gradient otherwise.
+func (c *Canvas) fillGradient(p vg.Path, g vg.Gradient) {
+	lg, ok := g.(*vg.LinearGradient)
+	if !ok || len(lg.Stops) == 0 || len(lg.Stops) > 2 {
+		vg.FillGradient(c, p, g)
+		return
+	}
+
+	c.ctx.push()
+	defer c.ctx.pop()
+
+	defer clip.Outline{
+		Path: c.outline(p),
+	}.Op().Push(c.gtx.Ops).Pop()
+
+	var (
+		rect   = p.Bounds()
+		size   = rect.Size()
+		s0, s1 = lg.Stops[0], lg.Stops[len(lg.Stops)-1]
+		at     = func(o float64) f32.Point {
+			x := lg.X1 + o*(lg.X2-lg.X1)
+			y := lg.Y1 + o*(lg.Y2-lg.Y1)
+			return c.ctx.pt32(vg.Point{
+				X: rect.Min.X + vg.Length(x)*size.X,
+				Y: rect.Min.Y + vg.Length(y)*size.Y,
+			})
+		}
+	)
+	paint.LinearGradientOp{
+		Stop1:  at(s0.Offset),
+		Color1: rgba(s0.Color),
+		Stop2:  at(s1.Offset),
+		Color2: rgba(s1.Color),
+	}.Add(c.gtx.Ops)
+	defer c.ctx.rect().Push(c.gtx.Ops).Pop()
+	paint.PaintOp{}.Add(c.gtx.Ops)
+}
+
+// Clip implements the vg.Clipper interface.
+// The clip is removed by the call to Pop matching
+// the most recent call to Push.
+func (c *Canvas) Clip(p vg.Path) {
+	c.ctx.clip(clip.Outline{
+		Path: c.outline(p),
+	}.Op())
+}
+
 func rgba(c color.Color) color.NRGBA {
 	r, g, b, a := c.RGBA()
 	return color.NRGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}
@@ -0,0 +1,636 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vghtml implements the vg.Canvas interface by writing
+// JavaScript drawing commands for the HTML5 canvas 2D API.
+//
+// The produced document is a self-contained HTML snippet, made of
+// a canvas element and the script drawing on it, that can be
+// embedded in a web page. The plot is drawn at the resolution of
+// the display.
+package vghtml // import "github.com/emptywe/plot/vg/vghtml"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	xfnt "golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+)
+
+func init() {
+	draw.RegisterFormat("html", func(w, h vg.Length) vg.CanvasWriterTo {
+		return New(w, h)
+	})
+}
+
+// defaultPrecision is the default precision to use
+// when outputting float64s.
+const defaultPrecision = 5
+
+const (
+	// DefaultWidth and DefaultHeight are the default canvas
+	// dimensions.
+	DefaultWidth  = 4 * vg.Inch
+	DefaultHeight = 4 * vg.Inch
+)
+
+// Canvas implements the vg.Canvas interface, writing JavaScript
+// drawing commands for the HTML5 canvas 2D API.
+type Canvas struct {
+	w, h vg.Length
+
+	buf   *bytes.Buffer // buf holds the drawing commands.
+	stack []context
+
+	pr int // pr is the precision to use when outputting float64s.
+
+	// images are the data URLs of the images drawn on the
+	// canvas, and their indices in the document.
+	images []string
+	index  map[string]int
+
+	// groups reports whether the document uses groups.
+	groups bool
+}
+
+// context is the state of the canvas. It holds the state
+// requested by the vg.Canvas methods, and the state of the
+// HTML canvas, that is saved and restored with it.
+type context struct {
+	color  color.Color
+	width  vg.Length
+	dashes []vg.Length
+	offset vg.Length
+	cap    vg.LineCap
+	join   vg.LineJoin
+	miter  float64
+
+	js jsState
+}
+
+// jsState holds the values of the properties of the HTML
+// canvas context, as written in the document.
+type jsState struct {
+	fillStyle      string
+	strokeStyle    string
+	lineWidth      string
+	lineDash       string
+	lineDashOffset string
+	lineCap        string
+	lineJoin       string
+	miterLimit     string
+	font           string
+}
+
+type option func(*Canvas)
+
+// UseWH specifies the width and height of the canvas.
+func UseWH(w, h vg.Length) option {
+	return func(c *Canvas) {
+		if w <= 0 || h <= 0 {
+			panic("vghtml: w and h must both be > 0")
+		}
+		c.w = w
+		c.h = h
+	}
+}
+
+// Precision specifies the number of significant digits used when
+// writing coordinates and lengths.
+// The default precision is 5.
+func Precision(n int) option {
+	return func(c *Canvas) {
+		if n <= 0 {
+			panic("vghtml: precision must be > 0")
+		}
+		c.pr = n
+	}
+}
+
+// New returns a new HTML canvas.
+func New(w, h vg.Length) *Canvas {
+	return NewWith(UseWH(w, h))
+}
+
+// NewWith returns a new HTML canvas created according to the specified
+// options. The currently accepted options are UseWH and Precision.
+// If size is not specified, the default is used.
+func NewWith(opts ...option) *Canvas {
+	c := &Canvas{
+		w:   DefaultWidth,
+		h:   DefaultHeight,
+		buf: new(bytes.Buffer),
+		pr:  defaultPrecision,
+		// The initial state is the default state
+		// of the HTML canvas context.
+		stack: []context{{js: jsState{
+			fillStyle:      `"#000000"`,
+			strokeStyle:    `"#000000"`,
+			lineWidth:      "1",
+			lineDash:       "[]",
+			lineDashOffset: "0",
+			lineCap:        `"butt"`,
+			lineJoin:       `"miter"`,
+			miterLimit:     "10",
+		}}},
+		index: make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	vg.Initialize(c)
+	return c
+}
+
+// Size returns the width and height of the canvas.
+func (c *Canvas) Size() (w, h vg.Length) {
+	return c.w, c.h
+}
+
+func (c *Canvas) context() *context {
+	return &c.stack[len(c.stack)-1]
+}
+
+// SetLineWidth implements the vg.Canvas.SetLineWidth method.
+func (c *Canvas) SetLineWidth(w vg.Length) {
+	c.context().width = w
+}
+
+// SetLineDash implements the vg.Canvas.SetLineDash method.
+func (c *Canvas) SetLineDash(dashes []vg.Length, offs vg.Length) {
+	c.context().dashes = dashes
+	c.context().offset = offs
+}
+
+// SetLineCap implements the vg.LineStyler.SetLineCap method.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.context().cap = capStyle
+}
+
+// SetLineJoin implements the vg.LineStyler.SetLineJoin method.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.context().join = joinStyle
+}
+
+// SetMiterLimit implements the vg.LineStyler.SetMiterLimit method.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.context().miter = limit
+}
+
+// SetColor implements the vg.Canvas.SetColor method.
+func (c *Canvas) SetColor(clr color.Color) {
+	c.context().color = clr
+}
+
+// Rotate implements the vg.Canvas.Rotate method.
+func (c *Canvas) Rotate(rad float64) {
+	fmt.Fprintf(c.buf, "c.rotate(%s);\n", c.num(rad))
+}
+
+// Translate implements the vg.Canvas.Translate method.
+func (c *Canvas) Translate(pt vg.Point) {
+	fmt.Fprintf(c.buf, "c.translate(%s, %s);\n", c.num(pt.X.Points()), c.num(pt.Y.Points()))
+}
+
+// Scale implements the vg.Canvas.Scale method.
+func (c *Canvas) Scale(x, y float64) {
+	fmt.Fprintf(c.buf, "c.scale(%s, %s);\n", c.num(x), c.num(y))
+}
+
+// Push implements the vg.Canvas.Push method.
+func (c *Canvas) Push() {
+	ctx := *c.context()
+	c.stack = append(c.stack, ctx)
+	c.buf.WriteString("c.save();\n")
+}
+
+// Pop implements the vg.Canvas.Pop method.
+func (c *Canvas) Pop() {
+	c.stack = c.stack[:len(c.stack)-1]
+	c.buf.WriteString("c.restore();\n")
+}
+
+// Stroke implements the vg.Canvas.Stroke method.
+func (c *Canvas) Stroke(path vg.Path) {
+	ctx := c.context()
+	if ctx.width.Points() <= 0 {
+		return
+	}
+	c.set(&ctx.js.strokeStyle, "strokeStyle", strconv.Quote(colorString(ctx.color)))
+	c.set(&ctx.js.lineWidth, "lineWidth", c.num(ctx.width.Points()))
+	if c.set(&ctx.js.lineDash, "", c.dashArray(ctx.dashes)) {
+		fmt.Fprintf(c.buf, "c.setLineDash(%s);\n", ctx.js.lineDash)
+	}
+	c.set(&ctx.js.lineDashOffset, "lineDashOffset", c.num(ctx.offset.Points()))
+	c.set(&ctx.js.lineCap, "lineCap", strconv.Quote(lineCapString(ctx.cap)))
+	c.set(&ctx.js.lineJoin, "lineJoin", strconv.Quote(lineJoinString(ctx.join)))
+	c.set(&ctx.js.miterLimit, "miterLimit", c.num(ctx.miter))
+
+	c.path(path)
+	c.buf.WriteString("c.stroke();\n")
+}
+
+// Fill implements the vg.Canvas.Fill method.
+func (c *Canvas) Fill(path vg.Path) {
+	ctx := c.context()
+	if h, ok := ctx.color.(*vg.Hatch); ok {
+		vg.FillHatch(c, path, h)
+		return
+	}
+	if g, ok := ctx.color.(vg.Gradient); ok {
+		c.fillGradient(path, g)
+		return
+	}
+	c.set(&ctx.js.fillStyle, "fillStyle", strconv.Quote(colorString(ctx.color)))
+	c.path(path)
+	c.buf.WriteString("c.fill();\n")
+}
+
+// fillGradient fills the path with the gradient, using a
+// canvas gradient over the bounding box of the path.
+func (c *Canvas) fillGradient(path vg.Path, g vg.Gradient) {
+	var (
+		create string
+		stops  []vg.GradientStop
+	)
+	switch g := g.(type) {
+	case *vg.LinearGradient:
+		create = fmt.Sprintf("c.createLinearGradient(%s, %s, %s, %s)",
+			c.num(g.X1), c.num(g.Y1), c.num(g.X2), c.num(g.Y2))
+		stops = g.Stops
+	case *vg.RadialGradient:
+		create = fmt.Sprintf("c.createRadialGradient(%s, %s, 0, %s, %s, %s)",
+			c.num(g.CX), c.num(g.CY), c.num(g.CX), c.num(g.CY), c.num(g.R))
+		stops = g.Stops
+	default:
+		vg.FillGradient(c, path, g)
+		return
+	}
+
+	rect := path.Bounds()
+	size := rect.Size()
+	if size.X == 0 || size.Y == 0 {
+		return
+	}
+	c.Push()
+	c.Clip(path)
+	fmt.Fprintf(c.buf, "c.transform(%s, 0, 0, %s, %s, %s);\n",
+		c.num(size.X.Points()), c.num(size.Y.Points()),
+		c.num(rect.Min.X.Points()), c.num(rect.Min.Y.Points()))
+	fmt.Fprintf(c.buf, "var g = %s;\n", create)
+	for _, s := range stops {
+		fmt.Fprintf(c.buf, "g.addColorStop(%s, %q);\n", c.num(math.Max(0, math.Min(1, s.Offset))), colorString(s.Color))
+	}
+	c.buf.WriteString("c.fillStyle = g;\nc.fillRect(0, 0, 1, 1);\n")
+	c.Pop()
+}
+
+// Clip implements the vg.Clipper interface.
+// The clipping region is restored by Pop.
+func (c *Canvas) Clip(path vg.Path) {
+	c.path(path)
+	c.buf.WriteString("c.clip();\n")
+}
+
+// DrawGroup implements the vg.Grouper interface.
+// The group is drawn to an offscreen canvas, that is then
+// composited onto the canvas.
+func (c *Canvas) DrawGroup(g vg.Group, fn func(vg.Canvas)) {
+	c.groups = true
+	c.buf.WriteString("c = group(c);\n")
+	c.Push()
+	fn(c)
+	c.Pop()
+	fmt.Fprintf(c.buf, "c = ungroup(c, %s, %q);\n", c.num(g.Alpha()), blendString(g.Blend))
+}
+
+// set writes the assignment of the property of the HTML canvas
+// context to v, if its current value *js is different.
+// An empty property name writes nothing.
+// set reports whether the value changed.
+func (c *Canvas) set(js *string, prop, v string) bool {
+	if *js == v {
+		return false
+	}
+	*js = v
+	if prop != "" {
+		fmt.Fprintf(c.buf, "c.%s = %s;\n", prop, v)
+	}
+	return true
+}
+
+// path writes the commands building the path.
+func (c *Canvas) path(path vg.Path) {
+	c.buf.WriteString("c.beginPath();\n")
+	for _, comp := range path {
+		switch comp.Type {
+		case vg.MoveComp:
+			fmt.Fprintf(c.buf, "c.moveTo(%s);\n", c.pt(comp.Pos))
+		case vg.LineComp:
+			fmt.Fprintf(c.buf, "c.lineTo(%s);\n", c.pt(comp.Pos))
+		case vg.ArcComp:
+			// The angles of the canvas arcs are in the user space,
+			// where the y axis increases upwards, as for vg arcs.
+			fmt.Fprintf(c.buf, "c.arc(%s, %s, %s, %s, %t);\n",
+				c.pt(comp.Pos), c.num(comp.Radius.Points()),
+				c.num(comp.Start), c.num(comp.Start+comp.Angle), comp.Angle < 0)
+		case vg.CurveComp:
+			switch len(comp.Control) {
+			case 1:
+				fmt.Fprintf(c.buf, "c.quadraticCurveTo(%s, %s);\n",
+					c.pt(comp.Control[0]), c.pt(comp.Pos))
+			case 2:
+				fmt.Fprintf(c.buf, "c.bezierCurveTo(%s, %s, %s);\n",
+					c.pt(comp.Control[0]), c.pt(comp.Control[1]), c.pt(comp.Pos))
+			default:
+				panic("vghtml: invalid number of control points")
+			}
+		case vg.CloseComp:
+			c.buf.WriteString("c.closePath();\n")
+		default:
+			panic(fmt.Sprintf("vghtml: unknown path component %d", comp.Type))
+		}
+	}
+}
+
+// FillString implements the vg.Canvas.FillString method.
+// The text is drawn with the font of the face if it is available
+// to the browser, and with similar fonts otherwise.
+func (c *Canvas) FillString(fnt font.Face, pt vg.Point, str string) {
+	if fnt.Font.Size == 0 {
+		return
+	}
+	ctx := c.context()
+	c.set(&ctx.js.fillStyle, "fillStyle", strconv.Quote(colorString(ctx.color)))
+	c.set(&ctx.js.font, "font", jsString(c.font(fnt)))
+
+	// Text is drawn upside-down in the flipped
+	// coordinate system of the canvas.
+	fmt.Fprintf(c.buf, "c.save();\nc.translate(%s);\nc.scale(1, -1);\nc.fillText(%s, 0, 0);\nc.restore();\n",
+		c.pt(pt), jsString(str))
+}
+
+// font returns the CSS font description of the font face.
+func (c *Canvas) font(fnt font.Face) string {
+	var desc []string
+	switch fnt.Font.Style {
+	case xfnt.StyleItalic:
+		desc = append(desc, "italic")
+	case xfnt.StyleOblique:
+		desc = append(desc, "oblique")
+	}
+	if strings.EqualFold(string(fnt.Font.Variant), "smallcaps") {
+		desc = append(desc, "small-caps")
+	}
+	if w := fnt.Font.Weight; w != xfnt.WeightNormal {
+		// The weights of x/image/font are centered on 0,
+		// by steps of 100.
+		desc = append(desc, strconv.Itoa(400+100*int(w)))
+	}
+	desc = append(desc, c.num(fnt.Font.Size.Points())+"px")
+
+	var families []string
+	if fnt.Face != nil {
+		var buf sfnt.Buffer
+		if name, err := fnt.Face.Name(&buf, sfnt.NameIDFamily); err == nil {
+			families = append(families, strconv.Quote(name))
+		}
+	}
+	families = append(families, fallbacks(fnt.Font.Variant)...)
+	return strings.Join(desc, " ") + " " + strings.Join(families, ", ")
+}
+
+// fallbacks returns the font families used when the font
+// of the variant is not available, ending with a generic family.
+func fallbacks(v font.Variant) []string {
+	switch strings.ToLower(string(v)) {
+	case "mono", "monospace":
+		return []string{`"Courier New"`, "monospace"}
+	case "sans", "sansserif", "sans-serif":
+		return []string{"Arial", "Helvetica", "sans-serif"}
+	default:
+		return []string{`"Times New Roman"`, "Times", "serif"}
+	}
+}
+
+// DrawImage implements the vg.Canvas.DrawImage method.
+// Images are embedded in the document as PNG data URLs.
+func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, img)
+	if err != nil {
+		panic(fmt.Errorf("vghtml: error encoding image to PNG: %+v", err))
+	}
+	url := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	i, ok := c.index[url]
+	if !ok {
+		i = len(c.images)
+		c.index[url] = i
+		c.images = append(c.images, url)
+	}
+
+	size := rect.Size()
+	// Images are drawn upside-down in the flipped
+	// coordinate system of the canvas.
+	fmt.Fprintf(c.buf, "c.save();\nc.translate(%s, %s);\nc.scale(1, -1);\nc.drawImage(imgs[%d], 0, 0, %s, %s);\nc.restore();\n",
+		c.num(rect.Min.X.Points()), c.num((rect.Min.Y + size.Y).Points()), i,
+		c.num(size.X.Points()), c.num(size.Y.Points()))
+}
+
+// groupFuncs are the JavaScript functions drawing groups.
+const groupFuncs = `function group(c) {
+	var l = document.createElement("canvas");
+	l.width = c.canvas.width;
+	l.height = c.canvas.height;
+	var lc = l.getContext("2d");
+	lc.setTransform(c.getTransform());
+	["fillStyle", "strokeStyle", "lineWidth", "lineDashOffset", "lineCap", "lineJoin", "miterLimit", "font"].forEach(function(k) { lc[k] = c[k]; });
+	lc.setLineDash(c.getLineDash());
+	lc.parent = c;
+	return lc;
+}
+function ungroup(lc, alpha, op) {
+	var c = lc.parent;
+	c.save();
+	c.setTransform(1, 0, 0, 1, 0, 0);
+	c.globalAlpha = alpha;
+	c.globalCompositeOperation = op;
+	c.drawImage(lc.canvas, 0, 0);
+	c.restore();
+	return c;
+}
+`
+
+// WriteTo writes the HTML snippet to w.
+func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
+	b := &cwriter{w: bufio.NewWriter(w)}
+
+	// The canvas is sized in points, and its backing store
+	// matches the resolution of the display.
+	width, height := c.num(c.w.Points()), c.num(c.h.Points())
+	fmt.Fprintf(b, "<canvas style=\"width:%spt;height:%spt\"></canvas>\n", width, height)
+	b.WriteString("<script>\n(function() {\n")
+	b.WriteString("var canvas = document.currentScript.previousElementSibling;\n")
+	b.WriteString("var ratio = (window.devicePixelRatio || 1) * 96 / 72;\n")
+	fmt.Fprintf(b, "canvas.width = Math.ceil(%s * ratio);\n", width)
+	fmt.Fprintf(b, "canvas.height = Math.ceil(%s * ratio);\n", height)
+	if c.groups {
+		b.WriteString(groupFuncs)
+	}
+	b.WriteString("function draw(imgs) {\nvar c = canvas.getContext(\"2d\");\n")
+	// Scale to points and swap the origin to the bottom left.
+	fmt.Fprintf(b, "c.scale(canvas.width / %s, canvas.height / %s);\n", width, height)
+	fmt.Fprintf(b, "c.transform(1, 0, 0, -1, 0, %s);\n", height)
+
+	// Write the commands without consuming them, so that
+	// the Canvas can be written again if needed.
+	_, err := b.Write(c.buf.Bytes())
+	if err != nil {
+		return b.n, err
+	}
+
+	b.WriteString("}\n")
+	if len(c.images) == 0 {
+		b.WriteString("draw([]);\n")
+	} else {
+		// Images must be decoded before being drawn.
+		b.WriteString("var imgs = [\n")
+		for _, url := range c.images {
+			fmt.Fprintf(b, "\t%q,\n", url)
+		}
+		b.WriteString("].map(function(src) { var img = new Image(); img.src = src; return img; });\n")
+		b.WriteString("Promise.all(imgs.map(function(img) { return img.decode(); })).then(function() { draw(imgs); });\n")
+	}
+	b.WriteString("})();\n</script>\n")
+
+	return b.n, b.w.Flush()
+}
+
+type cwriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *cwriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *cwriter) WriteString(s string) {
+	n, _ := c.w.WriteString(s)
+	c.n += int64(n)
+}
+
+// pt returns the coordinates of the point.
+func (c *Canvas) pt(p vg.Point) string {
+	return c.num(p.X.Points()) + ", " + c.num(p.Y.Points())
+}
+
+// num returns the shortest representation of v
+// with the precision of the canvas.
+func (c *Canvas) num(v float64) string {
+	str := strconv.FormatFloat(v, 'g', c.pr, 64)
+	if str == "-0" {
+		return "0"
+	}
+	return str
+}
+
+// dashArray returns the JavaScript array of the dashes.
+func (c *Canvas) dashArray(dashes []vg.Length) string {
+	strs := make([]string, len(dashes))
+	for i, d := range dashes {
+		strs[i] = c.num(d.Points())
+	}
+	return "[" + strings.Join(strs, ", ") + "]"
+}
+
+// jsString returns the JavaScript string literal of s.
+// Characters that may end the script element are escaped.
+func jsString(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		panic(fmt.Errorf("vghtml: could not encode string: %+v", err))
+	}
+	return string(b)
+}
+
+// colorString returns the CSS representation of the color.
+func colorString(clr color.Color) string {
+	if clr == nil {
+		clr = color.Black
+	}
+	c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("rgba(%d, %d, %d, %s)", c.R, c.G, c.B,
+		strconv.FormatFloat(float64(c.A)/0xff, 'g', 3, 64))
+}
+
+// blendString returns the name of the composite operation
+// of the blend mode.
+func blendString(mode vg.BlendMode) string {
+	switch mode {
+	case vg.BlendMultiply:
+		return "multiply"
+	case vg.BlendScreen:
+		return "screen"
+	case vg.BlendOverlay:
+		return "overlay"
+	case vg.BlendDarken:
+		return "darken"
+	case vg.BlendLighten:
+		return "lighten"
+	default:
+		return "source-over"
+	}
+}
+
+// lineCapString returns the canvas name of the line cap.
+func lineCapString(capStyle vg.LineCap) string {
+	switch capStyle {
+	case vg.RoundCap:
+		return "round"
+	case vg.SquareCap:
+		return "square"
+	default:
+		return "butt"
+	}
+}
+
+// lineJoinString returns the canvas name of the line join.
+func lineJoinString(joinStyle vg.LineJoin) string {
+	switch joinStyle {
+	case vg.MiterJoin:
+		return "miter"
+	case vg.BevelJoin:
+		return "bevel"
+	default:
+		return "round"
+	}
+}
+
+var (
+	_ vg.CanvasWriterTo = (*Canvas)(nil)
+	_ vg.Clipper        = (*Canvas)(nil)
+	_ vg.LineStyler     = (*Canvas)(nil)
+	_ vg.Grouper        = (*Canvas)(nil)
+)
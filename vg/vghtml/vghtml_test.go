@@ -0,0 +1,149 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vghtml_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/font/liberation"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/vghtml"
+)
+
+func TestHTML(t *testing.T) {
+	cmpimg.CheckPlot(Example, t, "scatter.html")
+}
+
+func TestCanvas(t *testing.T) {
+	cache := font.NewCache(liberation.Collection())
+	fnt := cache.Lookup(font.Font{Variant: "Sans", Weight: 3}, 12)
+
+	var p vg.Path
+	p.Move(vg.Point{X: 1, Y: 2})
+	p.Line(vg.Point{X: 3, Y: 4})
+	p.Arc(vg.Point{X: 5, Y: 5}, 1, 0, -1.5)
+	p.QuadTo(vg.Point{X: 6, Y: 6}, vg.Point{X: 7, Y: 8})
+	p.Close()
+
+	for _, test := range []struct {
+		name string
+		draw func(c *vghtml.Canvas)
+		want []string
+	}{
+		{
+			name: "stroke",
+			draw: func(c *vghtml.Canvas) {
+				c.SetColor(color.NRGBA{R: 255, A: 128})
+				c.SetLineWidth(2)
+				c.SetLineDash([]vg.Length{1, 2}, 0.5)
+				c.SetLineCap(vg.RoundCap)
+				c.Stroke(p)
+				// Unchanged properties are not written again.
+				c.Stroke(p)
+			},
+			want: []string{
+				"c.strokeStyle = \"rgba(255, 0, 0, 0.502)\";\n" +
+					"c.lineWidth = 2;\n" +
+					"c.setLineDash([1, 2]);\n" +
+					"c.lineDashOffset = 0.5;\n" +
+					"c.lineCap = \"round\";\n" +
+					"c.lineJoin = \"round\";\n" +
+					"c.beginPath();\n" +
+					"c.moveTo(1, 2);\n" +
+					"c.lineTo(3, 4);\n" +
+					"c.arc(5, 5, 1, 0, -1.5, true);\n" +
+					"c.quadraticCurveTo(6, 6, 7, 8);\n" +
+					"c.closePath();\n" +
+					"c.stroke();\n" +
+					"c.beginPath();\n",
+			},
+		},
+		{
+			name: "push pop",
+			draw: func(c *vghtml.Canvas) {
+				c.Push()
+				c.SetColor(color.White)
+				c.Fill(p)
+				c.Pop()
+				c.Fill(p)
+			},
+			want: []string{
+				"c.save();\nc.fillStyle = \"#FFFFFF\";\n",
+				"c.restore();\nc.beginPath();\n",
+			},
+		},
+		{
+			name: "text",
+			draw: func(c *vghtml.Canvas) {
+				c.Rotate(1)
+				c.FillString(fnt, vg.Point{X: 1, Y: 2}, "a</script>")
+			},
+			want: []string{
+				"c.rotate(1);\n",
+				`c.font = "700 12px \"Liberation Sans\", Arial, Helvetica, sans-serif";`,
+				"c.translate(1, 2);\nc.scale(1, -1);\nc.fillText(\"a\\u003c/script\\u003e\", 0, 0);\n",
+			},
+		},
+		{
+			name: "image",
+			draw: func(c *vghtml.Canvas) {
+				img := image.NewGray(image.Rect(0, 0, 1, 1))
+				rect := vg.Rectangle{Max: vg.Point{X: 10, Y: 20}}
+				c.DrawImage(rect, img)
+				c.DrawImage(rect, img)
+			},
+			want: []string{
+				"c.translate(0, 20);\nc.scale(1, -1);\nc.drawImage(imgs[0], 0, 0, 10, 20);\n",
+				"var imgs = [\n\t\"data:image/png;base64,",
+				"Promise.all(",
+			},
+		},
+		{
+			name: "group",
+			draw: func(c *vghtml.Canvas) {
+				c.DrawGroup(vg.Group{Opacity: 0.5, Blend: vg.BlendMultiply}, func(c vg.Canvas) {
+					c.Fill(p)
+				})
+			},
+			want: []string{
+				"function group(c) {",
+				"c = group(c);\nc.save();\n",
+				"c.restore();\nc = ungroup(c, 0.5, \"multiply\");\n",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			c := vghtml.New(100, 50)
+			test.draw(c)
+
+			var buf bytes.Buffer
+			n, err := c.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("could not write canvas: %+v", err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("invalid number of bytes written: got=%d, want=%d", n, buf.Len())
+			}
+			got := buf.String()
+			if !strings.HasPrefix(got, "<canvas style=\"width:100pt;height:50pt\"></canvas>\n<script>\n") {
+				t.Errorf("invalid prefix:\n%s", got)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing output:\n%s\ngot:\n%s", want, got)
+				}
+			}
+			if test.name != "image" && strings.Contains(got, "imgs = [") {
+				t.Errorf("unexpected images:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io"
+)
+
+// kittyChunk is the largest size of the base64 encoded
+// payload of an escape sequence of the Kitty protocol.
+const kittyChunk = 4096
+
+// EncodeKitty writes the image m to w as escape sequences of the
+// Kitty graphics protocol, that terminals supporting the protocol
+// display inline.
+// The image is transmitted as a PNG image, split into chunks.
+func EncodeKitty(w io.Writer, m image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, m); err != nil {
+		return err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	bw := bufio.NewWriter(w)
+	for i := 0; i == 0 || i < len(data); i += kittyChunk {
+		end := i + kittyChunk
+		if end > len(data) {
+			end = len(data)
+		}
+		more := "1"
+		if end == len(data) {
+			more = "0"
+		}
+
+		bw.WriteString("\x1b_G")
+		if i == 0 {
+			// Transmit and display a PNG image.
+			bw.WriteString("a=T,f=100,")
+		}
+		bw.WriteString("m=" + more + ";")
+		bw.WriteString(data[i:end])
+		bw.WriteString("\x1b\\")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,191 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"git.sr.ht/~sbinet/gg"
+
+	"github.com/emptywe/plot/vg"
+)
+
+// paint strokes or fills, with fn, the current path of the
+// context using the pattern, or the current color if pat is nil.
+// r is the bounding box of the painted pixels, in device pixels.
+//
+// Without anti-aliasing, fn paints in white over a cleared
+// region to compute the coverage of the pixels within r, and
+// the pixels covered by at least half are painted with the
+// pattern.
+func (c *Canvas) paint(r image.Rectangle, pat gg.Pattern, fn func()) {
+	cur := c.color[len(c.color)-1]
+	if c.antialias {
+		if pat != nil {
+			c.ctx.SetFillStyle(pat)
+			defer c.ctx.SetColor(cur)
+		}
+		fn()
+		return
+	}
+
+	img := c.ctx.Image().(*image.RGBA)
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		c.ctx.ClearPath()
+		return
+	}
+	saved := image.NewRGBA(r)
+	draw.Draw(saved, r, img, r.Min, draw.Src)
+	draw.Draw(img, r, image.Transparent, image.Point{}, draw.Src)
+
+	c.ctx.SetColor(color.White)
+	fn()
+	c.ctx.SetColor(cur)
+
+	mask := image.NewAlpha(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] >= 0x80 {
+				mask.Pix[mask.PixOffset(x, y)] = 0xff
+			}
+		}
+	}
+	draw.Draw(img, r, saved, r.Min, draw.Src)
+
+	var src image.Image = image.NewUniform(cur)
+	if pat != nil {
+		src = patternImage{pat}
+	}
+	draw.DrawMask(img, r, src, r.Min, mask, r.Min, draw.Over)
+}
+
+// patternImage is an unbounded image painted by a gg.Pattern.
+type patternImage struct {
+	gg.Pattern
+}
+
+func (p patternImage) ColorModel() color.Model { return color.RGBA64Model }
+
+func (p patternImage) Bounds() image.Rectangle {
+	return image.Rect(math.MinInt32, math.MinInt32, math.MaxInt32, math.MaxInt32)
+}
+
+func (p patternImage) At(x, y int) color.Color { return p.ColorAt(x, y) }
+
+// deviceBounds returns the bounding box in device pixels of the
+// rectangle r of the user space, padded by pad pixels.
+func (c *Canvas) deviceBounds(r vg.Rectangle, pad float64) image.Rectangle {
+	dpi := c.DPI()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, pt := range []vg.Point{r.Min, r.Max, {X: r.Min.X, Y: r.Max.Y}, {X: r.Max.X, Y: r.Min.Y}} {
+		x, y := c.ctx.TransformPoint(pt.X.Dots(dpi), pt.Y.Dots(dpi))
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	return image.Rect(
+		int(math.Floor(minX-pad)), int(math.Floor(minY-pad)),
+		int(math.Ceil(maxX+pad)), int(math.Ceil(maxY+pad)),
+	)
+}
+
+// snapPath returns the path p with its vertices moved to the pixel
+// grid, for axis-aligned strokes of width w device pixels to
+// cover whole pixels, and the width rounded to whole pixels.
+// snapPath returns false if p has segments that are not horizontal
+// or vertical lines in device space.
+//
+// The vertices are snapped in device space, through the current
+// transform. The width is not transformed: gg strokes the
+// transformed path with the line width in device pixels, so
+// scaling does not change the width of strokes.
+func (c *Canvas) snapPath(p vg.Path, w float64) (vg.Path, float64, bool) {
+	const eps = 1e-3
+
+	var (
+		dpi   = c.DPI()
+		pts   = make([]gg.Point, len(p))
+		snapX = make([]bool, len(p))
+		snapY = make([]bool, len(p))
+		start = -1 // start is the first vertex of the sub-path.
+		last  = -1 // last is the current vertex.
+	)
+	segment := func(i, j int) bool {
+		switch {
+		case math.Abs(pts[i].X-pts[j].X) < eps:
+			snapX[i], snapX[j] = true, true
+		case math.Abs(pts[i].Y-pts[j].Y) < eps:
+			snapY[i], snapY[j] = true, true
+		default:
+			return false
+		}
+		return true
+	}
+	for i, comp := range p {
+		switch comp.Type {
+		case vg.MoveComp:
+			pts[i].X, pts[i].Y = c.ctx.TransformPoint(comp.Pos.X.Dots(dpi), comp.Pos.Y.Dots(dpi))
+			start, last = i, i
+		case vg.LineComp:
+			pts[i].X, pts[i].Y = c.ctx.TransformPoint(comp.Pos.X.Dots(dpi), comp.Pos.Y.Dots(dpi))
+			if last < 0 || !segment(last, i) {
+				return nil, 0, false
+			}
+			last = i
+		case vg.CloseComp:
+			if start >= 0 && !segment(last, start) {
+				return nil, 0, false
+			}
+			last = start
+		default:
+			return nil, 0, false
+		}
+	}
+
+	w = math.Max(1, math.Round(w))
+	at := func(v float64) float64 {
+		if int(w)%2 == 1 {
+			// Odd widths are centered on pixels.
+			return math.Floor(v) + 0.5
+		}
+		return math.Round(v)
+	}
+
+	// inv maps device pixels back to the user space.
+	x0, y0 := c.ctx.TransformPoint(0, 0)
+	x1, y1 := c.ctx.TransformPoint(1, 0)
+	x2, y2 := c.ctx.TransformPoint(0, 1)
+	a, b := x1-x0, y1-y0
+	d, e := x2-x0, y2-y0
+	det := a*e - b*d
+	inv := func(x, y float64) vg.Point {
+		dx, dy := x-x0, y-y0
+		return vg.Point{
+			X: vg.Length((e*dx-d*dy)/det/dpi) * vg.Inch,
+			Y: vg.Length((-b*dx+a*dy)/det/dpi) * vg.Inch,
+		}
+	}
+
+	q := make(vg.Path, len(p))
+	copy(q, p)
+	for i := range q {
+		if !snapX[i] && !snapY[i] {
+			continue
+		}
+		pt := pts[i]
+		if snapX[i] {
+			pt.X = at(pt.X)
+		}
+		if snapY[i] {
+			pt.Y = at(pt.Y)
+		}
+		q[i].Pos = inv(pt.X, pt.Y)
+	}
+	return q, w, true
+}
@@ -0,0 +1,287 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// SixelOptions are the encoding parameters of Sixel images.
+type SixelOptions struct {
+	// Colors is the maximum number of colors of the
+	// palette, between 2 and 256. If zero, 256 colors
+	// are used.
+	Colors int
+}
+
+// DefaultSixelColors is the default number of colors of
+// the palette of Sixel images.
+const DefaultSixelColors = 256
+
+// EncodeSixel writes the image m to w as a Sixel escape sequence,
+// that terminals supporting Sixel graphics display inline.
+// The colors of the image are quantized to a palette, and
+// transparent pixels are not drawn.
+// Default parameters are used if a nil *SixelOptions is passed.
+func EncodeSixel(w io.Writer, m image.Image, o *SixelOptions) error {
+	n := DefaultSixelColors
+	if o != nil && o.Colors != 0 {
+		n = o.Colors
+	}
+	if n < 2 || n > 256 {
+		return fmt.Errorf("vgimg: invalid number of sixel colors: %d", n)
+	}
+
+	pal, ok := m.(*image.Paletted)
+	if !ok || len(pal.Palette) > n {
+		pal = quantize(m, n)
+	}
+
+	var (
+		bw     = bufio.NewWriter(w)
+		bounds = pal.Bounds()
+		dx, dy = bounds.Dx(), bounds.Dy()
+	)
+
+	// The second parameter selects transparent
+	// pixels for the pixels that are not drawn.
+	fmt.Fprintf(bw, "\x1bP0;1;0q\"1;1;%d;%d", dx, dy)
+	for i, c := range pal.Palette {
+		c := color.NRGBAModel.Convert(c).(color.NRGBA)
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, percent(c.R), percent(c.G), percent(c.B))
+	}
+
+	var (
+		sixels = make([][]byte, len(pal.Palette))
+		used   = make([]bool, len(pal.Palette))
+	)
+	for i := range sixels {
+		sixels[i] = make([]byte, dx)
+	}
+	for y0 := 0; y0 < dy; y0 += 6 {
+		for i := range sixels {
+			used[i] = false
+			for x := range sixels[i] {
+				sixels[i][x] = 0
+			}
+		}
+		for j := 0; j < 6 && y0+j < dy; j++ {
+			for x := 0; x < dx; x++ {
+				idx := pal.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y0+j)
+				if _, _, _, a := pal.Palette[idx].RGBA(); a < 0x8000 {
+					continue
+				}
+				sixels[idx][x] |= 1 << j
+				used[idx] = true
+			}
+		}
+
+		first := true
+		for i, row := range sixels {
+			if !used[i] {
+				continue
+			}
+			if !first {
+				// Go back to the start of the band.
+				bw.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(bw, "#%d", i)
+			writeSixels(bw, row)
+		}
+		if y0+6 < dy {
+			// Go to the next band.
+			bw.WriteByte('-')
+		}
+	}
+	bw.WriteString("\x1b\\")
+	return bw.Flush()
+}
+
+// writeSixels writes the row of sixels, compressing runs of
+// identical sixels. Empty sixels at the end of the row are
+// not written.
+func writeSixels(w *bufio.Writer, row []byte) {
+	end := len(row)
+	for end > 0 && row[end-1] == 0 {
+		end--
+	}
+	for i := 0; i < end; {
+		j := i + 1
+		for j < end && row[j] == row[i] {
+			j++
+		}
+		c := '?' + row[i]
+		switch n := j - i; {
+		case n > 3:
+			fmt.Fprintf(w, "!%d%c", n, c)
+		default:
+			for k := 0; k < n; k++ {
+				w.WriteByte(c)
+			}
+		}
+		i = j
+	}
+}
+
+// percent returns the color component v as a percentage.
+func percent(v uint8) int {
+	return (int(v)*100 + 127) / 255
+}
+
+// quantize returns a paletted copy of m, with at most n colors.
+// If m has more than n colors, the palette is computed with the
+// median cut algorithm.
+func quantize(m image.Image, n int) *image.Paletted {
+	bounds := m.Bounds()
+	hist := make(map[color.NRGBA]int)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			hist[color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)]++
+		}
+	}
+	entries := make([]entry, 0, len(hist))
+	for c, n := range hist {
+		entries = append(entries, entry{c: c, n: n})
+	}
+	// Sort the colors for the palette to be deterministic.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key() < entries[j].key()
+	})
+
+	var pal color.Palette
+	if len(entries) <= n {
+		for _, e := range entries {
+			pal = append(pal, e.c)
+		}
+	} else {
+		pal = medianCut(entries, n)
+	}
+
+	dst := image.NewPaletted(bounds, pal)
+	cache := make(map[color.NRGBA]uint8, len(hist))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(m.At(x, y)).(color.NRGBA)
+			idx, ok := cache[c]
+			if !ok {
+				idx = uint8(pal.Index(c))
+				cache[c] = idx
+			}
+			dst.SetColorIndex(x, y, idx)
+		}
+	}
+	return dst
+}
+
+// entry is a color of an image and its number of pixels.
+type entry struct {
+	c color.NRGBA
+	n int
+}
+
+func (e entry) key() uint32 {
+	return uint32(e.c.R)<<24 | uint32(e.c.G)<<16 | uint32(e.c.B)<<8 | uint32(e.c.A)
+}
+
+// component returns the i-th component of the color.
+func (e entry) component(i int) uint8 {
+	switch i {
+	case 0:
+		return e.c.R
+	case 1:
+		return e.c.G
+	case 2:
+		return e.c.B
+	default:
+		return e.c.A
+	}
+}
+
+// medianCut returns a palette of n colors representing the
+// entries, by repeatedly splitting the box of colors with the
+// largest range at the median of its pixels.
+func medianCut(entries []entry, n int) color.Palette {
+	// widest returns the component with the largest range
+	// of the box, and its range.
+	widest := func(box []entry) (int, int) {
+		best, rng := 0, -1
+		for i := 0; i < 4; i++ {
+			lo, hi := 255, 0
+			for _, e := range box {
+				v := int(e.component(i))
+				if v < lo {
+					lo = v
+				}
+				if v > hi {
+					hi = v
+				}
+			}
+			if hi-lo > rng {
+				best, rng = i, hi-lo
+			}
+		}
+		return best, rng
+	}
+
+	boxes := [][]entry{entries}
+	for len(boxes) < n {
+		k, comp, rng := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if c, r := widest(box); r > rng {
+				k, comp, rng = i, c, r
+			}
+		}
+		if k < 0 {
+			break
+		}
+
+		box := boxes[k]
+		sort.SliceStable(box, func(i, j int) bool {
+			return box[i].component(comp) < box[j].component(comp)
+		})
+		total := 0
+		for _, e := range box {
+			total += e.n
+		}
+		mid, sum := 1, 0
+		for i, e := range box[:len(box)-1] {
+			sum += e.n
+			mid = i + 1
+			if 2*sum >= total {
+				break
+			}
+		}
+		boxes[k] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	pal := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		var r, g, b, a, total int
+		for _, e := range box {
+			r += int(e.c.R) * e.n
+			g += int(e.c.G) * e.n
+			b += int(e.c.B) * e.n
+			a += int(e.c.A) * e.n
+			total += e.n
+		}
+		pal[i] = color.NRGBA{
+			R: uint8((r + total/2) / total),
+			G: uint8((g + total/2) / total),
+			B: uint8((b + total/2) / total),
+			A: uint8((a + total/2) / total),
+		}
+	}
+	return pal
+}
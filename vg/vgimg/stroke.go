@@ -0,0 +1,369 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"fmt"
+	"math"
+
+	"git.sr.ht/~sbinet/gg"
+
+	"github.com/emptywe/plot/vg"
+)
+
+// lineStyle is the state of the canvas used to stroke lines.
+type lineStyle struct {
+	width  vg.Length
+	dashes []float64 // dashes are in device pixels.
+	offset float64
+	cap    vg.LineCap
+	join   vg.LineJoin
+	miter  float64
+}
+
+// polyline is a flattened sub-path, in device pixels.
+type polyline struct {
+	pts    []gg.Point
+	closed bool
+}
+
+// strokeOutline strokes the path by filling the outline of the
+// stroke. It is used for line styles that gg can not draw, such
+// as miter joins.
+func (c *Canvas) strokeOutline(p vg.Path) {
+	sty := c.line[len(c.line)-1]
+	hw := sty.width.Dots(c.DPI()) / 2
+
+	c.ctx.Push()
+	defer c.ctx.Pop()
+	c.ctx.Identity()
+	c.ctx.SetFillRuleWinding()
+	for _, pl := range c.flatten(p) {
+		for _, pl := range dash(pl, sty.dashes, sty.offset) {
+			for _, poly := range strokePolygons(pl, hw, sty) {
+				addPolygon(c.ctx, poly)
+			}
+		}
+	}
+	c.ctx.Fill()
+}
+
+// flatten returns the sub-paths of p approximated by line
+// segments, in device pixels.
+func (c *Canvas) flatten(p vg.Path) []polyline {
+	var (
+		dpi   = c.DPI()
+		lines []polyline
+		cur   *polyline
+		start vg.Point // start is the first point of the sub-path.
+		last  vg.Point // last is the current point.
+	)
+	pt := func(p vg.Point) gg.Point {
+		x, y := c.ctx.TransformPoint(p.X.Dots(dpi), p.Y.Dots(dpi))
+		return gg.Point{X: x, Y: y}
+	}
+	moveTo := func(p vg.Point) {
+		lines = append(lines, polyline{pts: []gg.Point{pt(p)}})
+		cur = &lines[len(lines)-1]
+		start, last = p, p
+	}
+	lineTo := func(p vg.Point) {
+		if cur == nil {
+			moveTo(start)
+		}
+		cur.pts = append(cur.pts, pt(p))
+		last = p
+	}
+	for _, comp := range p {
+		switch comp.Type {
+		case vg.MoveComp:
+			moveTo(comp.Pos)
+
+		case vg.LineComp:
+			lineTo(comp.Pos)
+
+		case vg.ArcComp:
+			// Arcs are approximated by line segments,
+			// one every 2° at most.
+			n := int(math.Ceil(math.Abs(comp.Angle) * 90 / math.Pi))
+			if n < 1 {
+				n = 1
+			}
+			for i := 0; i <= n; i++ {
+				sin, cos := math.Sincos(comp.Start + comp.Angle*float64(i)/float64(n))
+				p := vg.Point{
+					X: comp.Pos.X + comp.Radius*vg.Length(cos),
+					Y: comp.Pos.Y + comp.Radius*vg.Length(sin),
+				}
+				if i == 0 && cur == nil {
+					moveTo(p)
+					continue
+				}
+				lineTo(p)
+			}
+
+		case vg.CurveComp:
+			var p1, p2 vg.Point
+			switch len(comp.Control) {
+			case 1:
+				// Elevate the quadratic curve to a cubic one.
+				p1 = last.Add(comp.Control[0].Sub(last).Scale(2.0 / 3))
+				p2 = comp.Pos.Add(comp.Control[0].Sub(comp.Pos).Scale(2.0 / 3))
+			case 2:
+				p1, p2 = comp.Control[0], comp.Control[1]
+			default:
+				panic("vgimg: invalid number of control points")
+			}
+			const n = 16
+			p0 := last
+			for i := 1; i <= n; i++ {
+				t := vg.Length(i) / n
+				u := 1 - t
+				lineTo(vg.Point{
+					X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*comp.Pos.X,
+					Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*comp.Pos.Y,
+				})
+			}
+
+		case vg.CloseComp:
+			if cur != nil {
+				cur.closed = true
+			}
+			cur = nil
+			last = start
+
+		default:
+			panic(fmt.Sprintf("Unknown path component: %d", comp.Type))
+		}
+	}
+
+	for i := range lines {
+		lines[i].pts = dedup(lines[i].pts, lines[i].closed)
+	}
+	return lines
+}
+
+// dedup removes consecutive duplicate points.
+func dedup(pts []gg.Point, closed bool) []gg.Point {
+	out := pts[:1]
+	for _, p := range pts[1:] {
+		if p != out[len(out)-1] {
+			out = append(out, p)
+		}
+	}
+	if closed && len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// dash splits the polyline into the dashes of the pattern.
+func dash(pl polyline, dashes []float64, offset float64) []polyline {
+	var total float64
+	for _, d := range dashes {
+		total += d
+	}
+	if total <= 0 {
+		return []polyline{pl}
+	}
+	if len(dashes)%2 != 0 {
+		dashes = append(dashes[:len(dashes):len(dashes)], dashes...)
+		total *= 2
+	}
+
+	pts := pl.pts
+	if pl.closed {
+		pts = append(pts[:len(pts):len(pts)], pts[0])
+	}
+
+	i, rem := 0, dashes[0]
+	for off := math.Mod(offset, total); off > 0; {
+		if off < rem {
+			rem -= off
+			break
+		}
+		off -= rem
+		i = (i + 1) % len(dashes)
+		rem = dashes[i]
+	}
+	on := i%2 == 0
+
+	var (
+		out []polyline
+		cur []gg.Point
+	)
+	if on {
+		cur = []gg.Point{pts[0]}
+	}
+	for k := 1; k < len(pts); k++ {
+		a, b := pts[k-1], pts[k]
+		n := a.Distance(b)
+		t := 0.0
+		for n-t > rem {
+			t += rem
+			q := a.Interpolate(b, t/n)
+			if on {
+				out = append(out, polyline{pts: append(cur, q)})
+				cur = nil
+			} else {
+				cur = []gg.Point{q}
+			}
+			on = !on
+			i = (i + 1) % len(dashes)
+			rem = dashes[i]
+		}
+		rem -= n - t
+		if on {
+			cur = append(cur, b)
+		}
+	}
+	if on && len(cur) > 0 {
+		out = append(out, polyline{pts: cur})
+	}
+	for i := range out {
+		out[i].pts = dedup(out[i].pts, false)
+	}
+	return out
+}
+
+// strokePolygons returns the polygons covering the stroke of
+// the polyline, with the half line width hw.
+func strokePolygons(pl polyline, hw float64, sty lineStyle) [][]gg.Point {
+	pts := pl.pts
+	if len(pts) == 1 {
+		p := pts[0]
+		switch sty.cap {
+		case vg.RoundCap:
+			return [][]gg.Point{circle(p, hw)}
+		case vg.SquareCap:
+			return [][]gg.Point{{
+				{X: p.X - hw, Y: p.Y - hw}, {X: p.X + hw, Y: p.Y - hw},
+				{X: p.X + hw, Y: p.Y + hw}, {X: p.X - hw, Y: p.Y + hw},
+			}}
+		}
+		return nil
+	}
+
+	// dir returns the unit direction from a to b.
+	dir := func(a, b gg.Point) gg.Point {
+		n := a.Distance(b)
+		return gg.Point{X: (b.X - a.X) / n, Y: (b.Y - a.Y) / n}
+	}
+	// off returns p offset by hw to the left of the direction d.
+	off := func(p, d gg.Point, s float64) gg.Point {
+		return gg.Point{X: p.X - s*d.Y*hw, Y: p.Y + s*d.X*hw}
+	}
+
+	var polys [][]gg.Point
+	n := len(pts)
+	nseg := n - 1
+	if pl.closed {
+		nseg = n
+	}
+	for k := 0; k < nseg; k++ {
+		a, b := pts[k], pts[(k+1)%n]
+		d := dir(a, b)
+		polys = append(polys, []gg.Point{off(a, d, +1), off(b, d, +1), off(b, d, -1), off(a, d, -1)})
+	}
+
+	// Joins.
+	for k := 0; k < n; k++ {
+		if !pl.closed && (k == 0 || k == n-1) {
+			continue
+		}
+		var (
+			p  = pts[k]
+			d1 = dir(pts[(k+n-1)%n], p)
+			d2 = dir(p, pts[(k+1)%n])
+		)
+		if sty.join == vg.RoundJoin {
+			polys = append(polys, circle(p, hw))
+			continue
+		}
+		cross := d1.X*d2.Y - d1.Y*d2.X
+		if cross == 0 {
+			continue
+		}
+		// The outer side of the corner is opposite
+		// to the direction of the turn.
+		s := 1.0
+		if cross > 0 {
+			s = -1
+		}
+		o1, o2 := off(p, d1, s), off(p, d2, s)
+		// cos is the cosine of half the turn angle, so that
+		// 1/cos is the ratio of the miter length to the line
+		// width.
+		cos := math.Sqrt((1 + d1.X*d2.X + d1.Y*d2.Y) / 2)
+		if sty.join == vg.MiterJoin && cos > 0 && 1/cos <= sty.miter {
+			bx, by := (o1.X+o2.X)/2-p.X, (o1.Y+o2.Y)/2-p.Y
+			l := math.Hypot(bx, by)
+			tip := gg.Point{X: p.X + bx/l*hw/cos, Y: p.Y + by/l*hw/cos}
+			polys = append(polys, []gg.Point{p, o1, tip, o2})
+			continue
+		}
+		polys = append(polys, []gg.Point{p, o1, o2})
+	}
+
+	// Caps.
+	if pl.closed {
+		return polys
+	}
+	ends := [2][2]gg.Point{
+		{pts[0], dir(pts[1], pts[0])},
+		{pts[n-1], dir(pts[n-2], pts[n-1])},
+	}
+	for _, e := range ends {
+		p, d := e[0], e[1]
+		switch sty.cap {
+		case vg.RoundCap:
+			polys = append(polys, circle(p, hw))
+		case vg.SquareCap:
+			q := gg.Point{X: p.X + d.X*hw, Y: p.Y + d.Y*hw}
+			polys = append(polys, []gg.Point{off(p, d, +1), off(q, d, +1), off(q, d, -1), off(p, d, -1)})
+		}
+	}
+	return polys
+}
+
+// circle returns a polygon approximating the circle of radius r
+// centered on p.
+func circle(p gg.Point, r float64) []gg.Point {
+	n := int(math.Ceil(math.Pi * r))
+	if n < 8 {
+		n = 8
+	}
+	poly := make([]gg.Point, n)
+	for i := range poly {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		poly[i] = gg.Point{X: p.X + r*cos, Y: p.Y + r*sin}
+	}
+	return poly
+}
+
+// addPolygon adds the polygon to the current path of ctx,
+// oriented so that all polygons have the same winding.
+func addPolygon(ctx *gg.Context, poly []gg.Point) {
+	var area float64
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	if area == 0 {
+		return
+	}
+	for i := range poly {
+		p := poly[i]
+		if area < 0 {
+			p = poly[len(poly)-1-i]
+		}
+		if i == 0 {
+			ctx.MoveTo(p.X, p.Y)
+			continue
+		}
+		ctx.LineTo(p.X, p.Y)
+	}
+	ctx.ClosePath()
+}
@@ -0,0 +1,181 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgimg
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"runtime"
+	"sync"
+
+	"git.sr.ht/~sbinet/gg"
+
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/recorder"
+)
+
+// DrawTiled draws fn onto the canvas using n concurrent workers.
+// The drawing is recorded once and replayed onto n horizontal
+// tiles of the image, which are then stitched together. The
+// resulting image is the same as the one drawn by calling fn
+// with the canvas, but large images are drawn faster.
+// If n is not positive, runtime.GOMAXPROCS(0) tiles are used.
+// The tiles are drawn with a margin above them, large enough
+// for the arcs and curves crossing their top, so that large
+// curved shapes reduce the speed up.
+//
+// fn is called once, with a canvas of the size of the receiver.
+// The drawing starts from the initial state of the canvas: the
+// transforms, clipping paths and styles set on the receiver are
+// not applied.
+func (c *Canvas) DrawTiled(n int, fn func(vg.CanvasSizer)) error {
+	rec := sizedRecorder{Canvas: new(recorder.Canvas), w: c.w, h: c.h}
+	fn(rec)
+
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	m := margin(rec.Actions, c.DPI())
+	b := c.img.Bounds()
+	rows := (b.Dy() + n - 1) / n
+	if rows == 0 {
+		return nil
+	}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, n)
+	)
+	for i := 0; i < n; i++ {
+		r := image.Rect(b.Min.X, b.Min.Y+i*rows, b.Max.X, b.Min.Y+(i+1)*rows).Intersect(b)
+		if r.Empty() {
+			break
+		}
+		wg.Add(1)
+		go func(i int, r image.Rectangle) {
+			defer wg.Done()
+			t := c.tile(r, m)
+			errs[i] = rec.ReplayOn(t)
+			draw.Draw(c.img, r, t.img, image.Pt(0, r.Min.Y-t.top), draw.Src)
+		}(i, r)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// margin returns the number of rows to draw above the tiles
+// for the actions, at the given resolution. The rasterizer
+// rounds negative coordinates towards zero when splitting
+// curves, so that the curves crossing the top of a tile must
+// lie within the tile and its margin to be painted as in the
+// whole image. The curves include the arcs and Bézier curves
+// of paths, and the round caps and joins of strokes.
+func margin(actions []recorder.Action, dpi float64) int {
+	type state struct {
+		// scale is an upper bound of the scale
+		// of the transforms, width is the line
+		// width in the user space.
+		scale, width float64
+	}
+	var (
+		cur   = state{scale: 1, width: 1}
+		stack []state
+		max   float64
+	)
+	var walk func([]recorder.Action)
+	walk = func(actions []recorder.Action) {
+		for _, a := range actions {
+			switch a := a.(type) {
+			case *recorder.Push:
+				stack = append(stack, cur)
+			case *recorder.Pop:
+				if len(stack) > 0 {
+					cur = stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+				}
+			case *recorder.Scale:
+				cur.scale *= math.Max(math.Abs(a.X), math.Abs(a.Y))
+			case *recorder.SetLineWidth:
+				cur.width = a.Width.Dots(dpi)
+			case *recorder.Stroke:
+				ext := cur.width
+				if curved(a.Path) {
+					sz := a.Path.Bounds().Size()
+					ext += (sz.X + sz.Y).Dots(dpi)
+				}
+				max = math.Max(max, cur.scale*ext)
+			case *recorder.Fill:
+				if curved(a.Path) {
+					sz := a.Path.Bounds().Size()
+					max = math.Max(max, cur.scale*(sz.X+sz.Y).Dots(dpi))
+				}
+			case *recorder.DrawGroup:
+				walk(a.Actions)
+			}
+		}
+	}
+	walk(actions)
+	return int(math.Ceil(max)) + 1
+}
+
+// curved returns whether the path p has arcs or curves.
+func curved(p vg.Path) bool {
+	for _, comp := range p {
+		switch comp.Type {
+		case vg.ArcComp, vg.CurveComp:
+			return true
+		}
+	}
+	return false
+}
+
+// tile returns a canvas drawing the rows r of the image of
+// the canvas and margin rows above them, in its initial
+// state, onto a separate image.
+// The pixels of the tile are at the same position relative
+// to the drawing as the pixels of the full image, so that
+// they are painted identically.
+func (c *Canvas) tile(r image.Rectangle, margin int) *Canvas {
+	top := r.Min.Y - margin
+	if top < 0 {
+		top = 0
+	}
+	img := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Max.Y-top))
+	ctx := gg.NewContextForRGBA(img)
+	ctx.SetLineCapButt()
+	ctx.Translate(0, float64(c.height-top))
+	ctx.Scale(1, -1)
+
+	t := NewWith(
+		UseImageWithContext(img, ctx),
+		UseDPI(c.dpi),
+		UseAntialias(c.antialias),
+		UsePixelSnapping(c.snap),
+	)
+	t.w, t.h = c.w, c.h
+	t.top, t.height = top, c.height
+
+	// The margin is drawn over a transparent background, as
+	// its rows of the image may be drawn by another tile.
+	draw.Draw(img, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+	draw.Draw(img, r.Sub(image.Pt(r.Min.X, top)), c.img, r.Min, draw.Src)
+	return t
+}
+
+// sizedRecorder is a recorder with the size of the
+// canvas it is replayed on.
+type sizedRecorder struct {
+	*recorder.Canvas
+	w, h vg.Length
+}
+
+func (c sizedRecorder) Size() (w, h vg.Length) { return c.w, c.h }
@@ -15,9 +15,11 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
+	"math"
 
 	"git.sr.ht/~sbinet/gg"
 	"golang.org/x/image/tiff"
+	"golang.org/x/image/vector"
 
 	"github.com/emptywe/plot/font"
 	"github.com/emptywe/plot/vg"
@@ -44,6 +46,14 @@ func init() {
 	vgdraw.RegisterFormat("tiff", func(w, h vg.Length) vg.CanvasWriterTo {
 		return TiffCanvas{Canvas: New(w, h)}
 	})
+
+	vgdraw.RegisterFormat("sixel", func(w, h vg.Length) vg.CanvasWriterTo {
+		return SixelCanvas{Canvas: New(w, h)}
+	})
+
+	vgdraw.RegisterFormat("kitty", func(w, h vg.Length) vg.CanvasWriterTo {
+		return KittyCanvas{Canvas: New(w, h)}
+	})
 }
 
 // Canvas implements the vg.Canvas interface,
@@ -57,12 +67,26 @@ type Canvas struct {
 	// dpi is the number of dots per inch for this canvas.
 	dpi int
 
-	// width is the current line width.
-	width vg.Length
+	// line is the stack of line styles.
+	line []lineStyle
 
 	// backgroundColor is the background color, set by
 	// UseBackgroundColor.
 	backgroundColor color.Color
+
+	// clip is the stack of clipping masks.
+	// A nil mask does not clip.
+	clip []*image.Alpha
+
+	// antialias and snap are set by UseAntialias
+	// and UsePixelSnapping.
+	antialias bool
+	snap      bool
+
+	// img holds the rows of the drawn image starting
+	// at top, out of height rows. It holds the whole
+	// image, except for the tiles of DrawTiled.
+	top, height int
 }
 
 const (
@@ -83,7 +107,8 @@ func New(w, h vg.Length) *Canvas {
 
 // NewWith returns a new image canvas created according to the specified
 // options. The currently accepted options are UseWH,
-// UseDPI, UseImage, and UseImageWithContext.
+// UseDPI, UseImage, UseImageWithContext, UseBackgroundColor,
+// UseAntialias and UsePixelSnapping.
 // Each of the options specifies the size of the canvas (UseWH, UseImage),
 // the resolution of the canvas (UseDPI), or both (useImageWithContext).
 // If size or resolution are not specified, defaults are used.
@@ -92,6 +117,7 @@ func New(w, h vg.Length) *Canvas {
 func NewWith(o ...option) *Canvas {
 	c := new(Canvas)
 	c.backgroundColor = color.White
+	c.antialias = true
 	var g uint32
 	for _, opt := range o {
 		f := opt(c)
@@ -125,8 +151,14 @@ func NewWith(o ...option) *Canvas {
 		c.img = c.ctx.Image().(draw.Image)
 		c.ctx.InvertY()
 	}
+	c.height = c.ctx.Height()
+	if c.backgroundColor == nil {
+		c.backgroundColor = color.Transparent
+	}
 	draw.Draw(c.img, c.img.Bounds(), &image.Uniform{c.backgroundColor}, image.Point{}, draw.Src)
 	c.color = []color.Color{color.Black}
+	c.clip = []*image.Alpha{nil}
+	c.line = []lineStyle{{miter: vg.DefaultMiterLimit}}
 	vg.Initialize(c)
 	return c
 }
@@ -195,6 +227,7 @@ func UseImageWithContext(img draw.Image, ctx *gg.Context) option {
 
 // UseBackgroundColor specifies the image background color.
 // Without UseBackgroundColor, the default color is white.
+// A nil color gives a fully transparent background.
 func UseBackgroundColor(c color.Color) option {
 	return func(canvas *Canvas) uint32 {
 		canvas.backgroundColor = c
@@ -202,6 +235,30 @@ func UseBackgroundColor(c color.Color) option {
 	}
 }
 
+// UseAntialias specifies whether strokes, fills, clipping paths
+// and text are anti-aliased. Without anti-aliasing, pixels are
+// either fully painted or left unchanged, which gives pixel-exact
+// images. Anti-aliasing is enabled by default.
+func UseAntialias(aa bool) option {
+	return func(c *Canvas) uint32 {
+		c.antialias = aa
+		return 0
+	}
+}
+
+// UsePixelSnapping specifies whether horizontal and vertical
+// strokes are snapped to the pixel grid, for thin lines such as
+// axes, ticks and grid lines to be drawn sharply instead of
+// straddling pixel boundaries. The width of snapped strokes is
+// rounded to whole pixels, at the resolution of the canvas.
+// Pixel snapping is disabled by default.
+func UsePixelSnapping(snap bool) option {
+	return func(c *Canvas) uint32 {
+		c.snap = snap
+		return 0
+	}
+}
+
 // Image returns the image the canvas is drawing to.
 //
 // The dimensions of the returned image must not be modified.
@@ -214,7 +271,7 @@ func (c *Canvas) Size() (w, h vg.Length) {
 }
 
 func (c *Canvas) SetLineWidth(w vg.Length) {
-	c.width = w
+	c.line[len(c.line)-1].width = w
 	c.ctx.SetLineWidth(w.Dots(c.DPI()))
 }
 
@@ -225,6 +282,39 @@ func (c *Canvas) SetLineDash(ds []vg.Length, offs vg.Length) {
 	}
 	c.ctx.SetDashOffset(offs.Dots(c.DPI()))
 	c.ctx.SetDash(dashes...)
+	c.line[len(c.line)-1].dashes = dashes
+	c.line[len(c.line)-1].offset = offs.Dots(c.DPI())
+}
+
+// SetLineCap implements the vg.LineStyler interface.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.line[len(c.line)-1].cap = capStyle
+	switch capStyle {
+	case vg.RoundCap:
+		c.ctx.SetLineCapRound()
+	case vg.SquareCap:
+		c.ctx.SetLineCapSquare()
+	default:
+		c.ctx.SetLineCapButt()
+	}
+}
+
+// SetLineJoin implements the vg.LineStyler interface.
+// Miter joins are drawn by the canvas itself, as gg only
+// supports round and bevel joins.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.line[len(c.line)-1].join = joinStyle
+	switch joinStyle {
+	case vg.BevelJoin:
+		c.ctx.SetLineJoinBevel()
+	default:
+		c.ctx.SetLineJoinRound()
+	}
+}
+
+// SetMiterLimit implements the vg.LineStyler interface.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.line[len(c.line)-1].miter = limit
 }
 
 func (c *Canvas) SetColor(clr color.Color) {
@@ -235,6 +325,17 @@ func (c *Canvas) SetColor(clr color.Color) {
 	c.color[len(c.color)-1] = clr
 }
 
+// Clear implements the vg.Clearer interface.
+func (c *Canvas) Clear(r vg.Rectangle, clr color.Color) {
+	if clr == nil {
+		clr = color.Transparent
+	}
+	// Round the bounds to the nearest pixels, for adjacent
+	// rectangles not to overlap.
+	b := c.deviceBounds(r, -0.5)
+	draw.Draw(c.img, b, &image.Uniform{clr}, image.Point{}, draw.Src)
+}
+
 func (c *Canvas) Rotate(t float64) {
 	c.ctx.Rotate(t)
 }
@@ -249,25 +350,274 @@ func (c *Canvas) Scale(x, y float64) {
 
 func (c *Canvas) Push() {
 	c.color = append(c.color, c.color[len(c.color)-1])
+	c.clip = append(c.clip, c.clip[len(c.clip)-1])
+	c.line = append(c.line, c.line[len(c.line)-1])
 	c.ctx.Push()
 }
 
 func (c *Canvas) Pop() {
 	c.color = c.color[:len(c.color)-1]
+	c.line = c.line[:len(c.line)-1]
 	c.ctx.Pop()
+
+	// gg does not restore the clipping mask on Pop.
+	cur := c.clip[len(c.clip)-1]
+	c.clip = c.clip[:len(c.clip)-1]
+	if prev := c.clip[len(c.clip)-1]; prev != cur {
+		if prev == nil {
+			c.ctx.ResetClip()
+		} else {
+			_ = c.ctx.SetMask(prev)
+		}
+	}
+}
+
+// clipBand is the height in pixels of the bands in which
+// clipping masks are rasterized.
+const clipBand = 64
+
+// Clip implements the vg.Clipper interface.
+// The mask is rasterized in bands of rows at fixed positions
+// in the drawn image, so that the tiles of DrawTiled are
+// clipped exactly as the whole image.
+func (c *Canvas) Clip(p vg.Path) {
+	var (
+		size = c.img.Bounds().Size()
+		mask = image.NewAlpha(image.Rect(0, 0, size.X, size.Y))
+		band = image.NewAlpha(image.Rect(0, 0, size.X, clipBand))
+		r    = vector.NewRasterizer(size.X, clipBand)
+	)
+	for y := c.top - c.top%clipBand; y < c.top+size.Y; y += clipBand {
+		// off is the position of the band in img.
+		off := y - c.top
+		r.Reset(size.X, clipBand)
+		c.rasterize(r, p, float64(off))
+		for i := range band.Pix {
+			band.Pix[i] = 0
+		}
+		r.Draw(band, band.Bounds(), image.Opaque, image.Point{})
+		draw.Draw(mask, band.Bounds().Add(image.Pt(0, off)), band, image.Point{}, draw.Src)
+	}
+	if !c.antialias {
+		for i, v := range mask.Pix {
+			if v >= 0x80 {
+				mask.Pix[i] = 0xff
+			} else {
+				mask.Pix[i] = 0
+			}
+		}
+	}
+	if cur := c.clip[len(c.clip)-1]; cur != nil {
+		for i, v := range cur.Pix {
+			mask.Pix[i] = uint8(uint32(mask.Pix[i]) * uint32(v) / 0xff)
+		}
+	}
+	c.clip[len(c.clip)-1] = mask
+	_ = c.ctx.SetMask(mask)
+}
+
+// rasterize adds the path p to r, in device pixels
+// shifted up by off rows.
+func (c *Canvas) rasterize(r *vector.Rasterizer, p vg.Path, off float64) {
+	var (
+		dpi  = c.DPI()
+		open = false
+	)
+	pt := func(p vg.Point) (float32, float32) {
+		x, y := c.ctx.TransformPoint(p.X.Dots(dpi), p.Y.Dots(dpi))
+		return float32(x), float32(y - off)
+	}
+	moveTo := func(x, y float32) {
+		if open {
+			r.ClosePath()
+		}
+		r.MoveTo(x, y)
+		open = true
+	}
+	for _, comp := range p {
+		switch comp.Type {
+		case vg.MoveComp:
+			moveTo(pt(comp.Pos))
+
+		case vg.LineComp:
+			r.LineTo(pt(comp.Pos))
+
+		case vg.ArcComp:
+			// Arcs are approximated by line segments,
+			// one every 2° at most.
+			n := int(math.Ceil(math.Abs(comp.Angle) * 90 / math.Pi))
+			if n < 1 {
+				n = 1
+			}
+			for i := 0; i <= n; i++ {
+				sin, cos := math.Sincos(comp.Start + comp.Angle*float64(i)/float64(n))
+				x, y := pt(vg.Point{
+					X: comp.Pos.X + comp.Radius*vg.Length(cos),
+					Y: comp.Pos.Y + comp.Radius*vg.Length(sin),
+				})
+				if i == 0 && !open {
+					moveTo(x, y)
+					continue
+				}
+				r.LineTo(x, y)
+			}
+
+		case vg.CurveComp:
+			switch len(comp.Control) {
+			case 1:
+				x1, y1 := pt(comp.Control[0])
+				x, y := pt(comp.Pos)
+				r.QuadTo(x1, y1, x, y)
+			case 2:
+				x1, y1 := pt(comp.Control[0])
+				x2, y2 := pt(comp.Control[1])
+				x, y := pt(comp.Pos)
+				r.CubeTo(x1, y1, x2, y2, x, y)
+			default:
+				panic("vgimg: invalid number of control points")
+			}
+
+		case vg.CloseComp:
+			r.ClosePath()
+
+		default:
+			panic(fmt.Sprintf("Unknown path component: %d", comp.Type))
+		}
+	}
+	if open {
+		r.ClosePath()
+	}
+}
+
+// DrawGroup implements the vg.Grouper interface.
+// The group is drawn onto a transparent layer that is then
+// composited onto the previous content of the image.
+func (c *Canvas) DrawGroup(g vg.Group, fn func(vg.Canvas)) {
+	img := c.ctx.Image().(*image.RGBA)
+	backdrop := image.NewRGBA(img.Bounds())
+	copy(backdrop.Pix, img.Pix)
+	draw.Draw(img, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+	fn(c)
+	composite(img, backdrop, g)
+}
+
+// composite composites the layer drawn in dst over the backdrop,
+// according to the opacity and blend mode of g, following the
+// W3C compositing model.
+func composite(dst, backdrop *image.RGBA, g vg.Group) {
+	op := g.Alpha()
+	for i := 0; i < len(dst.Pix); i += 4 {
+		s := dst.Pix[i : i+4 : i+4]
+		b := backdrop.Pix[i : i+4 : i+4]
+		if s[3] == 0 {
+			copy(s, b)
+			continue
+		}
+		as := float64(s[3]) / 0xff * op
+		ab := float64(b[3]) / 0xff
+		for k := 0; k < 3; k++ {
+			cs := float64(s[k]) / float64(s[3])
+			cb := 0.0
+			if b[3] != 0 {
+				cb = float64(b[k]) / float64(b[3])
+			}
+			co := as*(1-ab)*cs + as*ab*g.Blend.Blend(cb, cs) + (1-as)*ab*cb
+			s[k] = uint8(math.Round(math.Min(co, 1) * 0xff))
+		}
+		s[3] = uint8(math.Round((as + ab*(1-as)) * 0xff))
+	}
 }
 
 func (c *Canvas) Stroke(p vg.Path) {
-	if c.width <= 0 {
+	sty := c.line[len(c.line)-1]
+	if sty.width <= 0 {
 		return
 	}
-	c.outline(p)
-	c.ctx.Stroke()
+	if c.snap {
+		if q, w, ok := c.snapPath(p, sty.width.Dots(c.DPI())); ok {
+			p = q
+			c.SetLineWidth(vg.Length(w/c.DPI()) * vg.Inch)
+			defer c.SetLineWidth(sty.width)
+		}
+	}
+
+	// Miter joins extend up to half the miter limit
+	// times the width beyond the path.
+	pad := c.line[len(c.line)-1].width.Dots(c.DPI())/2*math.Max(sty.miter, math.Sqrt2) + 1
+	c.paint(c.deviceBounds(p.Bounds(), pad), nil, func() {
+		if sty.join == vg.MiterJoin {
+			c.strokeOutline(p)
+			return
+		}
+		c.outline(p)
+		c.ctx.Stroke()
+	})
 }
 
 func (c *Canvas) Fill(p vg.Path) {
-	c.outline(p)
-	c.ctx.Fill()
+	if h, ok := c.color[len(c.color)-1].(*vg.Hatch); ok {
+		vg.FillHatch(c, p, h)
+		return
+	}
+	var pat gg.Pattern
+	if g, ok := c.color[len(c.color)-1].(vg.Gradient); ok {
+		pat = c.gradientPattern(g, p.Bounds())
+	}
+	c.paint(c.deviceBounds(p.Bounds(), 1), pat, func() {
+		c.outline(p)
+		c.ctx.Fill()
+	})
+}
+
+// gradientPattern returns a pattern painting the gradient g
+// over the rectangle r, in the current user space.
+func (c *Canvas) gradientPattern(g vg.Gradient, r vg.Rectangle) gg.Pattern {
+	dpi := c.DPI()
+	x0, y0 := c.ctx.TransformPoint(0, 0)
+	x1, y1 := c.ctx.TransformPoint(1, 0)
+	x2, y2 := c.ctx.TransformPoint(0, 1)
+	a, b := x1-x0, y1-y0
+	d, e := x2-x0, y2-y0
+	det := a*e - b*d
+	return &gradientPattern{
+		g:    g,
+		x0:   x0,
+		y0:   y0,
+		inv:  [4]float64{e / det, -d / det, -b / det, a / det},
+		minX: r.Min.X.Dots(dpi),
+		minY: r.Min.Y.Dots(dpi),
+		w:    r.Size().X.Dots(dpi),
+		h:    r.Size().Y.Dots(dpi),
+	}
+}
+
+// gradientPattern is a gg.Pattern painting a vg.Gradient.
+type gradientPattern struct {
+	g vg.Gradient
+
+	// x0, y0 and inv map pixels back to the user space
+	// in which the gradient was set.
+	x0, y0 float64
+	inv    [4]float64
+
+	// minX, minY, w and h define the bounding box of the
+	// filled path, in user space.
+	minX, minY, w, h float64
+}
+
+func (p *gradientPattern) ColorAt(x, y int) color.Color {
+	dx := float64(x) + 0.5 - p.x0
+	dy := float64(y) + 0.5 - p.y0
+	ux := p.inv[0]*dx + p.inv[1]*dy
+	uy := p.inv[2]*dx + p.inv[3]*dy
+	var rx, ry float64
+	if p.w != 0 {
+		rx = (ux - p.minX) / p.w
+	}
+	if p.h != 0 {
+		ry = (uy - p.minY) / p.h
+	}
+	return p.g.ColorAt(rx, ry)
 }
 
 func (c *Canvas) outline(p vg.Path) {
@@ -321,23 +671,38 @@ func (c *Canvas) FillString(font font.Face, pt vg.Point, str string) {
 		return
 	}
 
-	c.ctx.Push()
-	defer c.ctx.Pop()
+	// The bounds of the glyphs are padded for the
+	// glyphs extending beyond their advance.
+	var (
+		ext  = font.Extents()
+		pad  = font.Font.Size.Dots(c.DPI()) / 2
+		bnds = vg.Rectangle{
+			Min: vg.Point{X: pt.X, Y: pt.Y - ext.Descent},
+			Max: vg.Point{X: pt.X + font.Width(str), Y: pt.Y + ext.Ascent},
+		}
+	)
+	c.paint(c.deviceBounds(bnds, pad), nil, func() {
+		c.ctx.Push()
+		defer c.ctx.Pop()
 
-	face := font.FontFace(c.DPI())
-	defer face.Close()
+		face := font.FontFace(c.DPI())
+		defer face.Close()
 
-	c.ctx.SetFontFace(face)
+		c.ctx.SetFontFace(face)
 
-	x := pt.X.Dots(c.DPI())
-	y := pt.Y.Dots(c.DPI())
-	h := c.h.Dots(c.DPI())
+		x := pt.X.Dots(c.DPI())
+		y := pt.Y.Dots(c.DPI())
+		h := c.h.Dots(c.DPI())
 
-	c.ctx.InvertY()
-	c.ctx.DrawString(str, x, h-y)
+		c.ctx.Translate(0, float64(c.height))
+		c.ctx.Scale(1, -1)
+		c.ctx.DrawString(str, x, h-y)
+	})
 }
 
 // DrawImage implements the vg.Canvas.DrawImage method.
+// Images with bounds that do not start at the origin, such
+// as sub-images, are drawn from their minimum point.
 func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
 	var (
 		dpi    = c.DPI()
@@ -354,7 +719,7 @@ func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
 	c.ctx.Scale(1, -1)
 	c.ctx.Translate(xmin, -ymin-height)
 	c.ctx.Scale(width/dx, height/dy)
-	c.ctx.DrawImage(img, 0, 0)
+	c.ctx.DrawImage(img, -img.Bounds().Min.X, -img.Bounds().Min.Y)
 	c.ctx.Pop()
 }
 
@@ -378,10 +743,21 @@ type JpegCanvas struct {
 }
 
 // WriteTo implements the io.WriterTo interface, writing a jpeg image.
+// As JPEG does not support transparency, translucent pixels
+// are drawn over a white background.
 func (c JpegCanvas) WriteTo(w io.Writer) (int64, error) {
+	var img image.Image = c.img
+	if o, ok := c.img.(interface{ Opaque() bool }); !ok || !o.Opaque() {
+		bnds := c.img.Bounds()
+		dst := image.NewRGBA(bnds)
+		draw.Draw(dst, bnds, image.White, image.Point{}, draw.Src)
+		draw.Draw(dst, bnds, c.img, bnds.Min, draw.Over)
+		img = dst
+	}
+
 	wc := writerCounter{Writer: w}
 	b := bufio.NewWriter(&wc)
-	if err := jpeg.Encode(b, c.img, nil); err != nil {
+	if err := jpeg.Encode(b, img, nil); err != nil {
 		return wc.n, err
 	}
 	err := b.Flush()
@@ -392,16 +768,26 @@ func (c JpegCanvas) WriteTo(w io.Writer) (int64, error) {
 // writes a png image.
 type PngCanvas struct {
 	*Canvas
+
+	// BitDepth is the number of bits per channel of the
+	// encoded image, 8 or 16. If zero, 8 bits are used.
+	// The canvas is drawn with 8 bits per channel, so 16-bit
+	// output adds no precision to the drawing.
+	BitDepth int
 }
 
 // WriteTo implements the io.WriterTo interface, writing a png image.
 func (c PngCanvas) WriteTo(w io.Writer) (int64, error) {
+	img, err := c.depth(c.BitDepth)
+	if err != nil {
+		return 0, err
+	}
 	wc := writerCounter{Writer: w}
 	b := bufio.NewWriter(&wc)
-	if err := png.Encode(b, c.img); err != nil {
+	if err := png.Encode(b, img); err != nil {
 		return wc.n, err
 	}
-	err := b.Flush()
+	err = b.Flush()
 	return wc.n, err
 }
 
@@ -409,15 +795,77 @@ func (c PngCanvas) WriteTo(w io.Writer) (int64, error) {
 // writes a tiff image.
 type TiffCanvas struct {
 	*Canvas
+
+	// BitDepth is the number of bits per channel of the
+	// encoded image, 8 or 16. If zero, 8 bits are used.
+	// The canvas is drawn with 8 bits per channel, so 16-bit
+	// output adds no precision to the drawing.
+	BitDepth int
 }
 
 // WriteTo implements the io.WriterTo interface, writing a tiff image.
 func (c TiffCanvas) WriteTo(w io.Writer) (int64, error) {
+	img, err := c.depth(c.BitDepth)
+	if err != nil {
+		return 0, err
+	}
 	wc := writerCounter{Writer: w}
 	b := bufio.NewWriter(&wc)
-	if err := tiff.Encode(b, c.img, nil); err != nil {
+	if err := tiff.Encode(b, img, nil); err != nil {
 		return wc.n, err
 	}
-	err := b.Flush()
+	err = b.Flush()
+	return wc.n, err
+}
+
+// depth returns the image of the canvas with the given number
+// of bits per channel.
+// The canvas is drawn with 8 bits per channel, and 16 bits
+// images only widen its samples: they add no precision to the
+// drawing, but translucent colors are not rounded again to 8
+// bits when the encoder converts them to non-premultiplied alpha.
+func (c *Canvas) depth(bits int) (image.Image, error) {
+	switch bits {
+	case 0, 8:
+		return c.img, nil
+	case 16:
+		bnds := c.img.Bounds()
+		img := image.NewRGBA64(bnds)
+		draw.Draw(img, bnds, c.img, bnds.Min, draw.Src)
+		return img, nil
+	default:
+		return nil, fmt.Errorf("vgimg: invalid bit depth: %d", bits)
+	}
+}
+
+// A SixelCanvas is an image canvas with a WriteTo method that
+// writes a Sixel escape sequence, for display in a terminal.
+type SixelCanvas struct {
+	*Canvas
+
+	// Options are the encoding parameters of the image.
+	// Default parameters are used if Options is nil.
+	Options *SixelOptions
+}
+
+// WriteTo implements the io.WriterTo interface, writing a Sixel image.
+func (c SixelCanvas) WriteTo(w io.Writer) (int64, error) {
+	wc := writerCounter{Writer: w}
+	err := EncodeSixel(&wc, c.img, c.Options)
+	return wc.n, err
+}
+
+// A KittyCanvas is an image canvas with a WriteTo method that
+// writes escape sequences of the Kitty graphics protocol, for
+// display in a terminal.
+type KittyCanvas struct {
+	*Canvas
+}
+
+// WriteTo implements the io.WriterTo interface, writing an image
+// with the Kitty graphics protocol.
+func (c KittyCanvas) WriteTo(w io.Writer) (int64, error) {
+	wc := writerCounter{Writer: w}
+	err := EncodeKitty(&wc, c.img)
 	return wc.n, err
 }
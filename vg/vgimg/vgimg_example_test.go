@@ -7,8 +7,10 @@ package vgimg_test
 import (
 	"image"
 	"image/color"
+	"log"
 
 	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/plotter"
 	"github.com/emptywe/plot/vg"
 	"github.com/emptywe/plot/vg/draw"
 	"github.com/emptywe/plot/vg/vgimg"
@@ -78,3 +80,56 @@ func ExampleUseBackgroundColor() {
 	dc := draw.New(c)
 	p.Draw(dc)
 }
+
+func ExampleUsePixelSnapping() {
+	p := plot.New()
+	p.Title.Text = "Title"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+	p.Add(plotter.NewGrid())
+
+	const (
+		width  = 10 * vg.Centimeter
+		height = 10 * vg.Centimeter
+	)
+
+	// Create a new canvas with the given dimensions,
+	// drawing the axes and grid lines of the plot
+	// aligned on pixels, without anti-aliasing.
+	c := vgimg.NewWith(
+		vgimg.UseWH(width, height),
+		vgimg.UsePixelSnapping(true),
+		vgimg.UseAntialias(false),
+	)
+
+	dc := draw.New(c)
+	p.Draw(dc)
+}
+
+func ExampleCanvas_DrawTiled() {
+	p := plot.New()
+	p.Title.Text = "Title"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+	p.Add(plotter.NewGrid())
+
+	const (
+		width  = 50 * vg.Centimeter
+		height = 50 * vg.Centimeter
+	)
+
+	// Create a large canvas, and draw the plot
+	// on horizontal tiles of the image concurrently,
+	// using all the available processors.
+	c := vgimg.NewWith(
+		vgimg.UseWH(width, height),
+		vgimg.UseDPI(300),
+	)
+
+	err := c.DrawTiled(0, func(c vg.CanvasSizer) {
+		p.Draw(draw.New(c))
+	})
+	if err != nil {
+		log.Fatalf("could not draw plot: %+v", err)
+	}
+}
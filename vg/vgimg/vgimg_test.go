@@ -6,15 +6,24 @@ package vgimg_test
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
+	"image"
 	"image/color"
+	"image/png"
+	"io"
 	"log"
+	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 
+	_ "golang.org/x/image/tiff"
+
 	"github.com/emptywe/plot"
 	"github.com/emptywe/plot/cmpimg"
 	"github.com/emptywe/plot/font"
@@ -94,6 +103,369 @@ func TestUseBackgroundColor(t *testing.T) {
 	}
 }
 
+func TestClip(t *testing.T) {
+	c := vgimg.NewWith(vgimg.UseWH(10, 10), vgimg.UseDPI(72), vgimg.UseBackgroundColor(color.White))
+	full := vg.Rectangle{Max: vg.Point{X: 10, Y: 10}}.Path()
+
+	c.Push()
+	c.Clip(vg.Rectangle{Max: vg.Point{X: 5, Y: 10}}.Path())
+	c.SetColor(color.NRGBA{R: 255, A: 255})
+	c.Fill(full)
+	c.Pop()
+
+	img := c.Image()
+	if got, want := img.At(2, 5), color.RGBAModel.Convert(color.NRGBA{R: 255, A: 255}); got != want {
+		t.Errorf("invalid color inside clip region: got=%v, want=%v", got, want)
+	}
+	if got, want := img.At(7, 5), color.RGBAModel.Convert(color.White); got != want {
+		t.Errorf("invalid color outside clip region: got=%v, want=%v", got, want)
+	}
+
+	c.SetColor(color.NRGBA{B: 255, A: 255})
+	c.Fill(full)
+	if got, want := img.At(7, 5), color.RGBAModel.Convert(color.NRGBA{B: 255, A: 255}); got != want {
+		t.Errorf("clip region not restored by Pop: got=%v, want=%v", got, want)
+	}
+}
+
+func TestAntialias(t *testing.T) {
+	for _, aa := range []bool{true, false} {
+		t.Run(fmt.Sprintf("antialias=%v", aa), func(t *testing.T) {
+			c := vgimg.NewWith(vgimg.UseWH(40, 40), vgimg.UseDPI(72), vgimg.UseAntialias(aa))
+			c.SetLineWidth(1.5)
+			var p vg.Path
+			p.Move(vg.Point{X: 2, Y: 3})
+			p.Line(vg.Point{X: 37, Y: 21})
+			c.Stroke(p)
+			c.FillString(cache.Lookup(font.Font{Typeface: "Liberation", Variant: "Sans"}, 12), vg.Point{X: 2, Y: 24}, "Text")
+			var q vg.Path
+			q.Arc(vg.Point{X: 30, Y: 32}, 5, 0, 2*math.Pi)
+			c.Fill(q)
+
+			var partial int
+			img := c.Image()
+			b := img.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					switch img.At(x, y) {
+					case color.RGBA{A: 255}, color.RGBA{R: 255, G: 255, B: 255, A: 255}:
+					default:
+						partial++
+					}
+				}
+			}
+			if aa && partial == 0 {
+				t.Errorf("no anti-aliased pixels")
+			}
+			if !aa && partial != 0 {
+				t.Errorf("unexpected anti-aliased pixels: %d", partial)
+			}
+		})
+	}
+}
+
+func TestPixelSnapping(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		dpi   int
+		width vg.Length
+		scale float64
+		want  []uint8 // want is the column of pixels at x=5.
+	}{
+		{name: "72dpi", dpi: 72, width: 1, want: []uint8{0xff, 0xff, 0, 0xff, 0xff, 0xff}},
+		{name: "72dpi-rounded", dpi: 72, width: 1.8, want: []uint8{0xff, 0xff, 0, 0, 0xff, 0xff}},
+		{name: "144dpi", dpi: 144, width: 1, want: []uint8{0xff, 0xff, 0xff, 0xff, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		// Line widths are not affected by scaling.
+		{name: "72dpi-scaled", dpi: 72, width: 1, scale: 2, want: []uint8{0xff, 0xff, 0, 0xff, 0xff, 0xff}},
+		{name: "72dpi-rounded-scaled", dpi: 72, width: 1.8, scale: 0.5, want: []uint8{0xff, 0xff, 0, 0, 0xff, 0xff}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			scale := tc.scale
+			if scale == 0 {
+				scale = 1
+			}
+			c := vgimg.NewWith(vgimg.UseWH(10, 6), vgimg.UseDPI(tc.dpi), vgimg.UsePixelSnapping(true))
+			c.Scale(scale, scale)
+			c.SetLineWidth(tc.width)
+			var p vg.Path
+			p.Move(vg.Point{X: 0, Y: 3.3 / vg.Length(scale)})
+			p.Line(vg.Point{X: 10 / vg.Length(scale), Y: 3.3 / vg.Length(scale)})
+			c.Stroke(p)
+
+			img := c.Image()
+			got := make([]uint8, img.Bounds().Dy())
+			for y := range got {
+				got[y] = color.GrayModel.Convert(img.At(5*tc.dpi/72, y)).(color.Gray).Y
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("invalid snapped line:\ngot= %v\nwant=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClear(t *testing.T) {
+	c := vgimg.NewWith(vgimg.UseWH(10, 10), vgimg.UseDPI(72))
+	c.Clear(vg.Rectangle{Min: vg.Point{X: 5}, Max: vg.Point{X: 10, Y: 10}}, color.Transparent)
+
+	img := c.Image()
+	if got, want := img.At(4, 5), color.RGBAModel.Convert(color.White); got != want {
+		t.Errorf("invalid color outside cleared region: got=%v, want=%v", got, want)
+	}
+	if got, want := img.At(5, 5), color.RGBAModel.Convert(color.Transparent); got != want {
+		t.Errorf("invalid color inside cleared region: got=%v, want=%v", got, want)
+	}
+
+	c = vgimg.NewWith(vgimg.UseWH(10, 10), vgimg.UseBackgroundColor(nil))
+	if got, want := c.Image().At(5, 5), color.RGBAModel.Convert(color.Transparent); got != want {
+		t.Errorf("invalid nil background color: got=%v, want=%v", got, want)
+	}
+}
+
+func TestBitDepth(t *testing.T) {
+	const alpha = 0x10
+	for _, tc := range []struct {
+		name string
+		c    vg.CanvasWriterTo
+		want color.Model
+
+		// lossy is whether the color of translucent
+		// pixels is rounded by the encoding.
+		lossy bool
+	}{
+		{name: "png-8", c: vgimg.PngCanvas{BitDepth: 8}, want: color.NRGBAModel, lossy: true},
+		{name: "png-16", c: vgimg.PngCanvas{BitDepth: 16}, want: color.NRGBA64Model},
+		{name: "tiff-8", c: vgimg.TiffCanvas{}, want: color.RGBAModel},
+		{name: "tiff-16", c: vgimg.TiffCanvas{BitDepth: 16}, want: color.RGBA64Model},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := vgimg.NewWith(vgimg.UseWH(10, 10), vgimg.UseBackgroundColor(nil))
+			c.SetColor(color.NRGBA{R: 0x80, G: 0x40, B: 0x20, A: alpha})
+			c.Fill(vg.Rectangle{Max: vg.Point{X: 10, Y: 10}}.Path())
+
+			var w io.WriterTo
+			switch cw := tc.c.(type) {
+			case vgimg.PngCanvas:
+				cw.Canvas = c
+				w = cw
+			case vgimg.TiffCanvas:
+				cw.Canvas = c
+				w = cw
+			}
+			var buf bytes.Buffer
+			if _, err := w.WriteTo(&buf); err != nil {
+				t.Fatalf("could not write image: %v", err)
+			}
+			img, _, err := image.Decode(&buf)
+			if err != nil {
+				t.Fatalf("could not decode image: %v", err)
+			}
+			if got := img.ColorModel(); got != tc.want {
+				t.Errorf("invalid color model: got=%v, want=%v", got, tc.want)
+			}
+			if got, want := color.RGBAModel.Convert(img.At(5, 5)), c.Image().At(5, 5); got != want && !tc.lossy {
+				t.Errorf("invalid color: got=%v, want=%v", got, want)
+			}
+		})
+	}
+
+	_, err := vgimg.PngCanvas{Canvas: vgimg.New(10, 10), BitDepth: 12}.WriteTo(io.Discard)
+	if err == nil {
+		t.Errorf("expected an error for an invalid bit depth")
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	for _, tc := range []struct {
+		name string
+		img  image.Image
+		want color.Color // want is the color at the center.
+	}{
+		{
+			name: "translucent",
+			img:  filled(image.Rect(0, 0, 2, 2), color.NRGBA{R: 255, A: 128}),
+			want: color.RGBA{R: 255, G: 127, B: 127, A: 255},
+		},
+		{
+			name: "translucent-edge",
+			img: func() image.Image {
+				img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+				img.Set(0, 0, red)
+				img.Set(1, 0, color.NRGBA{B: 255})
+				return img
+			}(),
+			want: color.RGBA{R: 255, G: 143, B: 143, A: 255},
+		},
+		{
+			name: "offset",
+			img:  filled(image.Rect(5, 5, 7, 7), red),
+			want: color.RGBA{R: 255, A: 255},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := vgimg.NewWith(vgimg.UseWH(20, 20), vgimg.UseDPI(72))
+			c.DrawImage(vg.Rectangle{Min: vg.Point{X: 2, Y: 2}, Max: vg.Point{X: 18, Y: 18}}, tc.img)
+			if got := c.Image().At(10, 10); got != tc.want {
+				t.Errorf("invalid color: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+// filled returns an image of the given bounds filled with c.
+func filled(r image.Rectangle, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDrawGroup(t *testing.T) {
+	var (
+		white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		gray  = color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	)
+	for _, test := range []struct {
+		group    vg.Group
+		backdrop color.RGBA
+		want     color.RGBA
+	}{
+		{
+			group:    vg.Group{Opacity: 1},
+			backdrop: white,
+			want:     color.RGBA{R: 255, A: 255},
+		},
+		{
+			group:    vg.Group{Opacity: 0.5},
+			backdrop: white,
+			want:     color.RGBA{R: 255, G: 128, B: 128, A: 255},
+		},
+		{
+			group:    vg.Group{Opacity: 1, Blend: vg.BlendMultiply},
+			backdrop: gray,
+			want:     color.RGBA{R: 128, A: 255},
+		},
+		{
+			group:    vg.Group{Opacity: 1, Blend: vg.BlendScreen},
+			backdrop: gray,
+			want:     color.RGBA{R: 255, G: 128, B: 128, A: 255},
+		},
+		{
+			// The zero opacity is opaque.
+			group:    vg.Group{Blend: vg.BlendMultiply},
+			backdrop: gray,
+			want:     color.RGBA{R: 128, A: 255},
+		},
+	} {
+		c := vgimg.NewWith(vgimg.UseWH(10, 10), vgimg.UseDPI(72), vgimg.UseBackgroundColor(test.backdrop))
+		c.DrawGroup(test.group, func(c vg.Canvas) {
+			// Overlapping fills of the group are
+			// composited as a whole.
+			right := vg.Rectangle{Min: vg.Point{X: 5}, Max: vg.Point{X: 10, Y: 10}}.Path()
+			c.SetColor(color.NRGBA{R: 255, A: 255})
+			c.Fill(right)
+			c.Fill(right)
+		})
+
+		img := c.Image()
+		if got := img.At(2, 5); got != test.backdrop {
+			t.Errorf("%v: invalid color outside group: got=%v, want=%v", test.group, got, test.backdrop)
+		}
+		if got := img.At(7, 5); got != test.want {
+			t.Errorf("%v: invalid color inside group: got=%v, want=%v", test.group, got, test.want)
+		}
+	}
+}
+
+func TestDrawTiled(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	pts := make(plotter.XYs, 200)
+	for i := range pts {
+		pts[i].X = rnd.Float64()
+		pts[i].Y = rnd.NormFloat64()
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rnd.Intn(256))
+	}
+
+	drawing := func(c vg.CanvasSizer) {
+		p := plot.New()
+		p.Title.Text = "Tiles"
+		p.X.Label.Text = "X"
+		p.Y.Label.Text = "Y"
+		p.Add(plotter.NewGrid())
+		s, err := plotter.NewScatter(pts)
+		if err != nil {
+			t.Fatalf("could not create scatter: %+v", err)
+		}
+		l, err := plotter.NewLine(pts[:20])
+		if err != nil {
+			t.Fatalf("could not create line: %+v", err)
+		}
+		p.Add(s, l)
+		p.Legend.Add("scatter", s)
+		p.Draw(draw.New(c))
+
+		c.Push()
+		c.Rotate(0.3)
+		c.(vg.Clipper).Clip(vg.Rectangle{Min: vg.Point{X: 40, Y: 10}, Max: vg.Point{X: 120, Y: 90}}.Path())
+		c.SetColor(&vg.LinearGradient{X2: 1, Y2: 1, Stops: []vg.GradientStop{
+			{Offset: 0, Color: color.NRGBA{R: 255, A: 200}},
+			{Offset: 1, Color: color.NRGBA{B: 255, A: 100}},
+		}})
+		var q vg.Path
+		q.Arc(vg.Point{X: 80, Y: 50}, 45, 0, 2*math.Pi)
+		q.Close()
+		c.Fill(q)
+		c.Pop()
+
+		c.(vg.Grouper).DrawGroup(vg.Group{Opacity: 0.6, Blend: vg.BlendMultiply}, func(c vg.Canvas) {
+			c.DrawImage(vg.Rectangle{Min: vg.Point{X: 20, Y: 100}, Max: vg.Point{X: 90, Y: 170}}, img)
+		})
+	}
+
+	for _, test := range []struct {
+		name string
+		new  func() *vgimg.Canvas
+	}{
+		{
+			name: "default",
+			new:  func() *vgimg.Canvas { return vgimg.New(200, 200) },
+		},
+		{
+			name: "aliased",
+			new: func() *vgimg.Canvas {
+				return vgimg.NewWith(vgimg.UseWH(200, 200), vgimg.UseAntialias(false), vgimg.UsePixelSnapping(true))
+			},
+		},
+		{
+			name: "transparent",
+			new: func() *vgimg.Canvas {
+				return vgimg.NewWith(vgimg.UseWH(200, 200), vgimg.UseDPI(150), vgimg.UseBackgroundColor(nil))
+			},
+		},
+	} {
+		want := test.new()
+		drawing(want)
+
+		for _, n := range []int{0, 1, 3, 7, 1000} {
+			got := test.new()
+			err := got.DrawTiled(n, drawing)
+			if err != nil {
+				t.Fatalf("could not draw tiles: %+v", err)
+			}
+			if !bytes.Equal(got.Image().(*image.RGBA).Pix, want.Image().(*image.RGBA).Pix) {
+				t.Errorf("%s: image drawn with %d tiles differs from the image drawn directly", test.name, n)
+			}
+		}
+	}
+}
+
 func TestIssue540(t *testing.T) {
 	p := plot.New()
 
@@ -184,3 +556,153 @@ func TestIssue687(t *testing.T) {
 
 	}, t, filepath.Base(fname))
 }
+
+func TestEncodeSixel(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	img := image.NewPaletted(image.Rect(0, 0, 2, 7), color.Palette{red, blue})
+	for y := 0; y < 6; y++ {
+		img.SetColorIndex(1, y, 1)
+	}
+
+	wide := image.NewNRGBA(image.Rect(0, 0, 5, 1))
+	for x := 0; x < 5; x++ {
+		wide.Set(x, 0, red)
+	}
+	wide.Set(4, 0, color.Transparent)
+
+	for _, test := range []struct {
+		name string
+		img  image.Image
+		want string
+	}{
+		{
+			name: "bands",
+			img:  img,
+			want: "\x1bP0;1;0q\"1;1;2;7#0;2;100;0;0#1;2;0;0;100#0~$#1?~-#0@@\x1b\\",
+		},
+		{
+			name: "runs",
+			img:  wide,
+			want: "\x1bP0;1;0q\"1;1;5;1#0;2;0;0;0#1;2;100;0;0#1!4@\x1b\\",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := vgimg.EncodeSixel(&buf, test.img, nil)
+			if err != nil {
+				t.Fatalf("could not encode image: %+v", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("invalid sixel image:\ngot= %q\nwant=%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEncodeSixelColors(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 100, 6))
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 6; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(2 * x)})
+		}
+	}
+
+	var buf bytes.Buffer
+	err := vgimg.EncodeSixel(&buf, img, &vgimg.SixelOptions{Colors: 4})
+	if err != nil {
+		t.Fatalf("could not encode image: %+v", err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "#3;2;") || strings.Contains(got, "#4;2;") {
+		t.Errorf("invalid palette: %q", got)
+	}
+
+	err = vgimg.EncodeSixel(&buf, img, &vgimg.SixelOptions{Colors: 1000})
+	if err == nil {
+		t.Errorf("expected an error for an invalid number of colors")
+	}
+}
+
+func TestEncodeKitty(t *testing.T) {
+	// Fill the image with noise, for the PNG image
+	// to be split into several chunks.
+	rnd := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	rnd.Read(img.Pix)
+
+	var buf bytes.Buffer
+	err := vgimg.EncodeKitty(&buf, img)
+	if err != nil {
+		t.Fatalf("could not encode image: %+v", err)
+	}
+
+	var (
+		seqs = strings.SplitAfter(buf.String(), "\x1b\\")
+		data strings.Builder
+	)
+	if seqs[len(seqs)-1] != "" {
+		t.Fatalf("unterminated escape sequence: %q", seqs[len(seqs)-1])
+	}
+	seqs = seqs[:len(seqs)-1]
+	if len(seqs) < 2 {
+		t.Fatalf("invalid number of chunks: %d", len(seqs))
+	}
+	for i, seq := range seqs {
+		want := "\x1b_Gm=1;"
+		switch i {
+		case 0:
+			want = "\x1b_Ga=T,f=100,m=1;"
+		case len(seqs) - 1:
+			want = "\x1b_Gm=0;"
+		}
+		if !strings.HasPrefix(seq, want) {
+			t.Fatalf("invalid chunk %d: got=%q, want prefix %q", i, seq[:20], want)
+		}
+		data.WriteString(strings.TrimSuffix(strings.TrimPrefix(seq, want), "\x1b\\"))
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data.String())
+	if err != nil {
+		t.Fatalf("could not decode payload: %+v", err)
+	}
+	got, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("could not decode image: %+v", err)
+	}
+	if !reflect.DeepEqual(got, img) {
+		t.Errorf("invalid image")
+	}
+}
+
+func TestSixelKittyFormats(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		prefix string
+	}{
+		{format: "sixel", prefix: "\x1bP0;1;0q"},
+		{format: "kitty", prefix: "\x1b_Ga=T,f=100,"},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			p := plot.New()
+			p.Title.Text = "Title"
+			c, err := p.WriterTo(2*vg.Centimeter, 1*vg.Centimeter, test.format)
+			if err != nil {
+				t.Fatalf("could not create canvas: %+v", err)
+			}
+			var buf bytes.Buffer
+			n, err := c.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("could not write canvas: %+v", err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("invalid number of bytes written: got=%d, want=%d", n, buf.Len())
+			}
+			got := buf.String()
+			if !strings.HasPrefix(got, test.prefix) || !strings.HasSuffix(got, "\x1b\\") {
+				t.Errorf("invalid escape sequence: %q", got)
+			}
+		})
+	}
+}
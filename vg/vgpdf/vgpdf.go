@@ -9,6 +9,7 @@ package vgpdf // import "github.com/emptywe/plot/vg/vgpdf"
 import (
 	"bufio"
 	"bytes"
+	"compress/zlib"
 	_ "embed"
 	"fmt"
 	"image"
@@ -19,6 +20,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	pdf "github.com/go-pdf/fpdf"
@@ -27,6 +29,7 @@ import (
 	"github.com/emptywe/plot/font"
 	"github.com/emptywe/plot/vg"
 	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/vgimg"
 )
 
 // codePageEncoding holds informations about the characters encoding of TrueType
@@ -50,6 +53,10 @@ func init() {
 // DPI is the nominal resolution of drawing in PDF.
 const DPI = 72
 
+// groupDPI is the resolution of the images of groups
+// drawn with a blend mode that PDF does not support.
+const groupDPI = 300
+
 // Canvas implements the vg.Canvas interface,
 // drawing to a PDF.
 type Canvas struct {
@@ -65,12 +72,24 @@ type Canvas struct {
 	// The default is to embed fonts.
 	// This makes the PDF file more portable but also larger.
 	embed bool
+
+	// space is the color space of the colors and
+	// images written to the PDF, and cmyk the
+	// conversion of colors to DeviceCMYK.
+	space vg.ColorSpace
+	cmyk  vg.CMYKConverter
 }
 
 type context struct {
 	fill  color.Color
 	line  color.Color
 	width vg.Length
+	cap   vg.LineCap
+	join  vg.LineJoin
+	miter float64
+
+	alpha float64 // alpha is the opacity of the enclosing groups.
+	blend string  // blend is the blend mode of the enclosing group.
 }
 
 // New creates a new PDF Canvas.
@@ -84,7 +103,7 @@ func New(w, h vg.Length) *Canvas {
 		w:     w,
 		h:     h,
 		dpi:   DPI,
-		stack: make([]context, 1),
+		stack: []context{{miter: vg.DefaultMiterLimit, alpha: 1, blend: "Normal"}},
 		fonts: make(map[font.Font]struct{}),
 		embed: true,
 	}
@@ -102,6 +121,22 @@ func (c *Canvas) EmbedFonts(v bool) bool {
 	return prev
 }
 
+// SetColorSpace sets the color space of the colors and images
+// drawn on the canvas. Colors are converted to DeviceCMYK with
+// conv, that may be nil to use the default conversion of
+// vg.CMYKConverter. conv is ignored for other color spaces.
+//
+// Colors and images of PDF files written in DeviceCMYK or
+// DeviceGray do not use DeviceRGB, as required by some print
+// vendors. The transparency group of pages is still declared
+// in DeviceRGB by fpdf.
+// The color space should be set before drawing on the canvas.
+func (c *Canvas) SetColorSpace(cs vg.ColorSpace, conv vg.CMYKConverter) {
+	c.space = cs
+	c.cmyk = conv
+	c.SetColor(c.context().fill)
+}
+
 func (c *Canvas) DPI() float64 {
 	return float64(c.dpi)
 }
@@ -135,10 +170,68 @@ func (c *Canvas) SetColor(clr color.Color) {
 	c.context().line = clr
 	c.context().fill = clr
 	r, g, b, a := rgba(clr)
-	c.doc.SetFillColor(r, g, b)
-	c.doc.SetDrawColor(r, g, b)
-	c.doc.SetTextColor(r, g, b)
-	c.doc.SetAlpha(a, "Normal")
+	switch c.space {
+	case vg.DeviceRGB:
+		c.doc.SetFillColor(r, g, b)
+		c.doc.SetDrawColor(r, g, b)
+		c.doc.SetTextColor(r, g, b)
+	default:
+		// fpdf only handles RGB colors. The fill and text
+		// colors of fpdf are left equal for text to be
+		// drawn with the fill color set here.
+		c.doc.RawWriteStr(c.pdfColor(clr))
+	}
+	c.setAlpha(a)
+}
+
+// setAlpha sets the alpha of the next drawing operations,
+// combined with the opacity and blend mode of the enclosing
+// groups.
+func (c *Canvas) setAlpha(a float64) {
+	ctx := c.context()
+	c.doc.SetAlpha(a*ctx.alpha, ctx.blend)
+}
+
+// pdfColor returns the PDF operators setting the stroke
+// and fill colors to clr, in the CMYK or gray color space
+// of the canvas.
+func (c *Canvas) pdfColor(clr color.Color) string {
+	if c.space == vg.DeviceGray {
+		v := vg.GrayLevel(clr)
+		return fmt.Sprintf("%.3f G %.3f g", v, v)
+	}
+	v := c.cmyk.CMYK(clr)
+	cmyk := fmt.Sprintf("%.3f %.3f %.3f %.3f",
+		float64(v.C)/255, float64(v.M)/255, float64(v.Y)/255, float64(v.K)/255,
+	)
+	return cmyk + " K " + cmyk + " k"
+}
+
+// SetLineCap implements the vg.LineStyler interface.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	if c.context().cap == capStyle {
+		return
+	}
+	c.context().cap = capStyle
+	c.doc.SetLineCapStyle(pdfLineCap(capStyle))
+}
+
+// SetLineJoin implements the vg.LineStyler interface.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	if c.context().join == joinStyle {
+		return
+	}
+	c.context().join = joinStyle
+	c.doc.SetLineJoinStyle(pdfLineJoin(joinStyle))
+}
+
+// SetMiterLimit implements the vg.LineStyler interface.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	if c.context().miter == limit {
+		return
+	}
+	c.context().miter = limit
+	c.doc.RawWriteStr(fmt.Sprintf("%.2f M", limit))
 }
 
 func (c *Canvas) Rotate(r float64) {
@@ -171,9 +264,112 @@ func (c *Canvas) Stroke(p vg.Path) {
 }
 
 func (c *Canvas) Fill(p vg.Path) {
+	if h, ok := c.context().fill.(*vg.Hatch); ok {
+		vg.FillHatch(c, p, h)
+		return
+	}
+	if g, ok := c.context().fill.(vg.Gradient); ok {
+		c.fillGradient(p, g)
+		return
+	}
 	c.pdfPath(p, "F")
 }
 
+// fillGradient fills the path with the gradient, using a PDF
+// shading for opaque gradients with at most two stops and
+// a rasterized image of the gradient otherwise.
+func (c *Canvas) fillGradient(p vg.Path, g vg.Gradient) {
+	rect := p.Bounds()
+	if sz := rect.Size(); sz.X == 0 || sz.Y == 0 {
+		return
+	}
+
+	c.Push()
+	defer c.Pop()
+	c.Clip(p)
+
+	if c.space != vg.DeviceRGB {
+		// fpdf shadings are RGB only.
+		c.setAlpha(1)
+		vg.FillGradient(c, p, g)
+		return
+	}
+
+	xp, yp := c.pdfPoint(rect.Min)
+	wp, hp := c.pdfPoint(rect.Size())
+	switch g := g.(type) {
+	case *vg.LinearGradient:
+		if s0, s1, ok := shadingStops(g.Stops); ok {
+			r0, g0, b0, _ := rgba(s0.Color)
+			r1, g1, b1, _ := rgba(s1.Color)
+			dx, dy := g.X2-g.X1, g.Y2-g.Y1
+			c.doc.LinearGradient(xp, yp, wp, hp, r0, g0, b0, r1, g1, b1,
+				g.X1+s0.Offset*dx, g.Y1+s0.Offset*dy,
+				g.X1+s1.Offset*dx, g.Y1+s1.Offset*dy,
+			)
+			return
+		}
+	case *vg.RadialGradient:
+		if s0, s1, ok := shadingStops(g.Stops); ok && s0.Offset == 0 {
+			r0, g0, b0, _ := rgba(s0.Color)
+			r1, g1, b1, _ := rgba(s1.Color)
+			c.doc.RadialGradient(xp, yp, wp, hp, r0, g0, b0, r1, g1, b1,
+				g.CX, g.CY, g.CX, g.CY, g.R*s1.Offset,
+			)
+			return
+		}
+	}
+
+	// The alpha set by SetColor would otherwise be
+	// applied on top of the alpha of the image.
+	c.setAlpha(1)
+	vg.FillGradient(c, p, g)
+}
+
+// shadingStops returns the first and last stops of a gradient
+// that can be drawn as a two colors PDF shading.
+func shadingStops(stops []vg.GradientStop) (s0, s1 vg.GradientStop, ok bool) {
+	if len(stops) == 0 || len(stops) > 2 {
+		return s0, s1, false
+	}
+	for _, s := range stops {
+		if _, _, _, a := s.Color.RGBA(); a != math.MaxUint16 {
+			return s0, s1, false
+		}
+	}
+	return stops[0], stops[len(stops)-1], true
+}
+
+// Clip implements the vg.Clipper interface.
+func (c *Canvas) Clip(p vg.Path) {
+	// fpdf paints arcs as soon as they are added to the path,
+	// approximate them with line segments instead.
+	var path vg.Path
+	for _, comp := range p {
+		if comp.Type != vg.ArcComp {
+			path = append(path, comp)
+			continue
+		}
+		n := int(math.Ceil(math.Abs(comp.Angle) * 90 / math.Pi))
+		if n < 1 {
+			n = 1
+		}
+		for i := 0; i <= n; i++ {
+			sin, cos := math.Sincos(comp.Start + comp.Angle*float64(i)/float64(n))
+			pt := vg.Point{
+				X: comp.Pos.X + comp.Radius*vg.Length(cos),
+				Y: comp.Pos.Y + comp.Radius*vg.Length(sin),
+			}
+			if i == 0 && len(path) == 0 {
+				path.Move(pt)
+				continue
+			}
+			path.Line(pt)
+		}
+	}
+	c.pdfPath(path, "W n")
+}
+
 func (c *Canvas) FillString(fnt font.Face, pt vg.Point, str string) {
 	if fnt.Font.Size == 0 {
 		return
@@ -224,6 +420,10 @@ func (c *Canvas) sbounds(fnt font.Face, txt string) (left, top, right, bottom fl
 
 // DrawImage implements the vg.Canvas.DrawImage method.
 func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
+	if c.space != vg.DeviceRGB {
+		c.drawImage(rect, img)
+		return
+	}
 	opts := pdf.ImageOptions{ImageType: "png", ReadDpi: true}
 	name := c.imageName()
 
@@ -240,6 +440,186 @@ func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
 	c.doc.ImageOptions(name, xp, yp, wp, hp, false, opts, 0, "")
 }
 
+// drawImage draws the image in the CMYK or gray color space
+// of the canvas. As fpdf only embeds RGB and gray images
+// without transparency, the image is written as a PDF object
+// imported into the document, with a soft mask for its alpha.
+func (c *Canvas) drawImage(rect vg.Rectangle, img image.Image) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+	data, alpha := imageData(img, c.space, c.cmyk)
+
+	c.numImages++
+	var (
+		key   = fmt.Sprintf("vgpdf-image-%03d", c.numImages)
+		mask  = key + "-mask"
+		name  = fmt.Sprintf("/VGI%d", c.numImages)
+		objs  = map[string][]byte{key: nil}
+		pos   = map[string]map[int]string{}
+		extra string
+	)
+	if alpha != nil {
+		// The soft mask is referenced by a 40 bytes
+		// placeholder, replaced by its object number.
+		extra = "/SMask " + strings.Repeat(" ", 40) + " 0 R "
+		objs[mask] = imageObject(b, vg.DeviceGray, "", alpha)
+		pos[key] = map[int]string{len(imageHeader(b, c.space)) + len("/SMask "): mask}
+	}
+	objs[key] = imageObject(b, c.space, extra, data)
+	c.doc.ImportObjects(objs)
+	c.doc.ImportObjPos(pos)
+	c.doc.ImportTemplates(map[string]string{name: key})
+
+	xp, yp := c.pdfPoint(rect.Min)
+	wp, hp := c.pdfPoint(rect.Size())
+	_, ph := c.doc.GetPageSize()
+	c.doc.RawWriteStr(fmt.Sprintf("q %.5f 0 0 %.5f %.5f %.5f cm %s Do Q", wp, hp, xp, ph-(yp+hp), name))
+}
+
+// imageHeader returns the start of the dictionary of an
+// image object, before its optional entries.
+func imageHeader(b image.Rectangle, cs vg.ColorSpace) string {
+	return fmt.Sprintf("<</Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%v /BitsPerComponent 8 ",
+		b.Dx(), b.Dy(), cs,
+	)
+}
+
+// imageObject returns a PDF image object holding the image
+// data, compressed with flate.
+func imageObject(b image.Rectangle, cs vg.ColorSpace, extra string, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+
+	obj := new(bytes.Buffer)
+	obj.WriteString(imageHeader(b, cs))
+	obj.WriteString(extra)
+	fmt.Fprintf(obj, "/Filter /FlateDecode /Length %d>>\nstream\n", buf.Len())
+	buf.WriteTo(obj)
+	obj.WriteString("\nendstream\nendobj")
+	return obj.Bytes()
+}
+
+// imageData returns the components of the pixels of img in
+// the color space cs, and their alpha if img is not opaque.
+func imageData(img image.Image, cs vg.ColorSpace, conv vg.CMYKConverter) (data, alpha []byte) {
+	var (
+		b      = img.Bounds()
+		n      = 1
+		opaque = true
+	)
+	if cs == vg.DeviceCMYK {
+		n = 4
+	}
+	data = make([]byte, 0, n*b.Dx()*b.Dy())
+	alpha = make([]byte, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			clr := img.At(x, y)
+			_, _, _, a := clr.RGBA()
+			alpha = append(alpha, uint8(a>>8))
+			opaque = opaque && a == math.MaxUint16
+			switch cs {
+			case vg.DeviceCMYK:
+				v := conv.CMYK(clr)
+				data = append(data, v.C, v.M, v.Y, v.K)
+			default:
+				data = append(data, uint8(math.Round(255*vg.GrayLevel(clr))))
+			}
+		}
+	}
+	if opaque {
+		alpha = nil
+	}
+	return data, alpha
+}
+
+// DrawGroup implements the vg.Grouper interface.
+// The opacity and blend mode of the group are set with
+// the PDF graphics state, and apply to each drawing
+// operation of the group: overlapping parts of a
+// translucent group are thus not isolated.
+// Groups with a blend mode not supported by PDF are drawn
+// as an image of their bounds, with the BlendNormal mode.
+func (c *Canvas) DrawGroup(g vg.Group, fn func(vg.Canvas)) {
+	blend, ok := pdfBlend(g.Blend)
+	if !ok {
+		c.drawGroupImage(g, fn)
+		return
+	}
+	c.Push()
+	ctx := c.context()
+	ctx.alpha *= g.Alpha()
+	ctx.blend = blend
+	c.SetColor(ctx.fill)
+	fn(c)
+	c.Pop()
+}
+
+// pdfBlend returns the name of the PDF blend mode
+// corresponding to m, and whether PDF supports m.
+func pdfBlend(m vg.BlendMode) (string, bool) {
+	switch m {
+	case vg.BlendNormal, vg.BlendMultiply, vg.BlendScreen,
+		vg.BlendOverlay, vg.BlendDarken, vg.BlendLighten:
+		return m.String(), true
+	}
+	return "", false
+}
+
+// drawGroupImage draws the group as an image, cropped
+// to the bounds of the pixels drawn by fn.
+func (c *Canvas) drawGroupImage(g vg.Group, fn func(vg.Canvas)) {
+	img := vgimg.NewWith(
+		vgimg.UseWH(c.w, c.h),
+		vgimg.UseDPI(groupDPI),
+		vgimg.UseBackgroundColor(color.Transparent),
+	)
+	ctx := c.context()
+	img.SetColor(ctx.fill)
+	img.SetLineWidth(ctx.width)
+	img.SetLineCap(ctx.cap)
+	img.SetLineJoin(ctx.join)
+	img.SetMiterLimit(ctx.miter)
+	fn(img)
+
+	rgba := img.Image().(*image.RGBA)
+	b := opaqueBounds(rgba)
+	if b.Empty() {
+		return
+	}
+	// Image rows grow downwards from the top of the page.
+	dot := vg.Inch / groupDPI
+	rect := vg.Rectangle{
+		Min: vg.Point{X: vg.Length(b.Min.X) * dot, Y: c.h - vg.Length(b.Max.Y)*dot},
+		Max: vg.Point{X: vg.Length(b.Max.X) * dot, Y: c.h - vg.Length(b.Min.Y)*dot},
+	}
+
+	c.Push()
+	c.context().alpha *= g.Alpha()
+	c.setAlpha(1)
+	c.DrawImage(rect, rgba.SubImage(b))
+	c.Pop()
+}
+
+// opaqueBounds returns the bounds of the pixels
+// of img that are not fully transparent.
+func opaqueBounds(img *image.RGBA) image.Rectangle {
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] != 0 {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
 // font registers a font and a size with the PDF canvas.
 func (c *Canvas) font(fnt font.Face, pt vg.Point) {
 	if _, ok := c.fonts[fnt.Font]; ok {
@@ -364,6 +744,30 @@ func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
 	return wc.n, err
 }
 
+// pdfLineCap returns the fpdf name of the line cap.
+func pdfLineCap(capStyle vg.LineCap) string {
+	switch capStyle {
+	case vg.RoundCap:
+		return "round"
+	case vg.SquareCap:
+		return "square"
+	default:
+		return "butt"
+	}
+}
+
+// pdfLineJoin returns the fpdf name of the line join.
+func pdfLineJoin(joinStyle vg.LineJoin) string {
+	switch joinStyle {
+	case vg.MiterJoin:
+		return "miter"
+	case vg.BevelJoin:
+		return "bevel"
+	default:
+		return "round"
+	}
+}
+
 // rgba converts a Go color into a gofpdf 3-tuple int + 1 float64
 func rgba(c color.Color) (int, int, int, float64) {
 	if c == nil {
@@ -479,11 +883,23 @@ func makeFont(key fontKey, font, encoding []byte) (val fontVal, err error) {
 // The new page is the new current page.
 // Modifications applied to the canvas will only be applied to that new page.
 func (c *Canvas) NextPage() {
+	fill := c.context().fill
 	if c.doc.PageNo() > 0 {
 		c.Pop()
 	}
 	c.doc.SetMargins(0, 0, 0)
+	// The line cap and line join are set by AddPage,
+	// the miter limit is reset to its default value.
+	c.doc.SetLineCapStyle(pdfLineCap(c.context().cap))
+	c.doc.SetLineJoinStyle(pdfLineJoin(c.context().join))
 	c.doc.AddPage()
+	if c.context().miter != vg.DefaultMiterLimit {
+		c.doc.RawWriteStr(fmt.Sprintf("%.2f M", c.context().miter))
+	}
+	if c.space != vg.DeviceRGB && fill != nil {
+		// AddPage only sets the RGB colors of fpdf.
+		c.doc.RawWriteStr(c.pdfColor(fill))
+	}
 	c.Push()
 	c.Translate(vg.Point{X: 0, Y: c.h})
 	c.Scale(1, -1)
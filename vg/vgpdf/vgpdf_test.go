@@ -6,11 +6,16 @@ package vgpdf_test
 
 import (
 	"bytes"
+	"compress/zlib"
 	"fmt"
+	"image"
 	"image/color"
 	"image/png"
+	"io"
 	"log"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/emptywe/plot"
@@ -209,6 +214,158 @@ func TestIssue540(t *testing.T) {
 	}
 }
 
+func TestColorSpace(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 128})
+	img.Set(1, 1, color.White)
+
+	for _, tc := range []struct {
+		name  string
+		space vg.ColorSpace
+		conv  vg.CMYKConverter
+		want  []string
+	}{
+		{
+			name:  "cmyk",
+			space: vg.DeviceCMYK,
+			want: []string{
+				"0.000 1.000 1.000 0.000 K 0.000 1.000 1.000 0.000 k",
+				"0.039 0.078 0.118 0.157 K 0.039 0.078 0.118 0.157 k",
+				"/ColorSpace /DeviceCMYK",
+				"/SMask",
+			},
+		},
+		{
+			name:  "cmyk-profile",
+			space: vg.DeviceCMYK,
+			conv: func(color.Color) color.CMYK {
+				return color.CMYK{K: 255}
+			},
+			want: []string{
+				"0.000 0.000 0.000 1.000 K 0.000 0.000 0.000 1.000 k",
+				"0.039 0.078 0.118 0.157 K 0.039 0.078 0.118 0.157 k",
+			},
+		},
+		{
+			name:  "gray",
+			space: vg.DeviceGray,
+			want: []string{
+				"0.299 G 0.299 g",
+				"0.783 G 0.783 g",
+				"/ColorSpace /DeviceGray",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := vgpdf.New(100, 100)
+			c.SetColorSpace(tc.space, tc.conv)
+
+			var p vg.Path
+			p.Move(vg.Point{X: 10, Y: 10})
+			p.Line(vg.Point{X: 50, Y: 10})
+			p.Line(vg.Point{X: 50, Y: 50})
+			p.Close()
+			c.SetColor(color.RGBA{R: 255, A: 255})
+			c.Fill(p)
+			c.SetColor(color.CMYK{C: 10, M: 20, Y: 30, K: 40})
+			c.Fill(p)
+			c.SetColor(&vg.LinearGradient{X2: 1, Stops: []vg.GradientStop{
+				{Color: color.White},
+				{Offset: 1, Color: color.RGBA{B: 255, A: 255}},
+			}})
+			c.Fill(p)
+			c.DrawImage(vg.Rectangle{Max: vg.Point{X: 20, Y: 20}}, img)
+
+			var buf bytes.Buffer
+			_, err := c.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("could not write canvas: %v", err)
+			}
+			got := inflate(t, buf.Bytes())
+			for _, op := range []string{" rg\n", " RG\n", "/ColorSpace /DeviceRGB"} {
+				if strings.Contains(got, op) {
+					t.Errorf("unexpected RGB color %q", op)
+				}
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawGroup(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		group vg.Group
+		want  []string
+		image bool
+	}{
+		{
+			name:  "opaque",
+			group: vg.Group{Blend: vg.BlendMultiply},
+			want:  []string{"/ca 1.000 /CA 1.000 /BM /Multiply"},
+		},
+		{
+			name:  "translucent",
+			group: vg.Group{Opacity: 0.5, Blend: vg.BlendScreen},
+			want:  []string{"/ca 0.251 /CA 0.251 /BM /Screen"},
+		},
+		{
+			name:  "unsupported",
+			group: vg.Group{Opacity: 0.5, Blend: vg.BlendMode(-1)},
+			want:  []string{"/ca 0.500 /CA 0.500 /BM /Normal"},
+			image: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := vgpdf.New(100, 100)
+			c.DrawGroup(tc.group, func(c vg.Canvas) {
+				c.SetColor(color.NRGBA{R: 255, A: 128})
+				c.Fill(vg.Rectangle{Min: vg.Point{X: 10, Y: 10}, Max: vg.Point{X: 20, Y: 20}}.Path())
+			})
+
+			var buf bytes.Buffer
+			_, err := c.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("could not write canvas: %v", err)
+			}
+			got := inflate(t, buf.Bytes())
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing %q", want)
+				}
+			}
+			if image := strings.Contains(got, "/Subtype /Image"); image != tc.image {
+				t.Errorf("invalid group image: got=%v, want=%v", image, tc.image)
+			}
+			if tc.image && !strings.Contains(got, "/Width 43\n/Height 43") {
+				t.Errorf("group image is not cropped to its bounds")
+			}
+		})
+	}
+}
+
+// inflate returns the PDF document with its flate
+// encoded streams decompressed.
+func inflate(t *testing.T, doc []byte) string {
+	streams := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+	return string(streams.ReplaceAllFunc(doc, func(s []byte) []byte {
+		data := streams.FindSubmatch(s)[1]
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return s
+		}
+		raw, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("could not decompress stream: %v", err)
+		}
+		return raw
+	}))
+}
+
 func BenchmarkCanvas(b *testing.B) {
 	p := plot.New()
 
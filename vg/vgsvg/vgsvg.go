@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"html"
 	"image"
@@ -27,11 +28,13 @@ import (
 	"image/png"
 	"io"
 	"math"
+	"strconv"
 	"strings"
 
 	svgo "github.com/ajstarks/svgo"
 	xfnt "golang.org/x/image/font"
 	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
 
 	"github.com/emptywe/plot/font"
 	"github.com/emptywe/plot/vg"
@@ -44,8 +47,9 @@ func init() {
 	})
 }
 
-// pr is the precision to use when outputting float64s.
-const pr = 5
+// defaultPrecision is the default precision to use
+// when outputting float64s.
+const defaultPrecision = 5
 
 const (
 	// DefaultWidth and DefaultHeight are the default canvas
@@ -73,6 +77,22 @@ type Canvas struct {
 	// Embedding fonts makes the SVG file larger but also more portable.
 	embed bool
 	fonts map[string]struct{} // set of already embedded fonts
+
+	// Switch to draw text as paths built from the glyph outlines.
+	// The default is to draw text with SVG text elements.
+	outline bool
+
+	pr     int  // pr is the precision to use when outputting float64s.
+	minify bool // minify is the switch to minify path data.
+
+	// Switch to share style attributes through CSS classes.
+	// The default is to write the style of each element inline.
+	css     bool
+	classes map[string]string // CSS class names, keyed by style
+	styles  []string          // styles, in order of first use
+
+	clips int               // number of clip paths defined in the document
+	defs  map[string]string // gradient and pattern IDs, keyed by definition
 }
 
 type context struct {
@@ -80,6 +100,9 @@ type context struct {
 	dashArray  []vg.Length
 	dashOffset vg.Length
 	lineWidth  vg.Length
+	lineCap    vg.LineCap
+	lineJoin   vg.LineJoin
+	miterLimit float64
 	gEnds      int
 }
 
@@ -104,14 +127,59 @@ func EmbedFonts(v bool) option {
 	}
 }
 
+// OutlineText specifies whether text should be drawn as vector paths
+// built from the glyph outlines of the font, instead of SVG text elements.
+// Outlined text displays correctly even when the font is not available
+// to the program displaying the SVG plot.
+// An invisible copy of the text is kept in the document so that it can
+// still be selected and searched.
+func OutlineText(v bool) option {
+	return func(c *Canvas) {
+		c.outline = v
+	}
+}
+
+// Precision specifies the number of significant digits used when
+// writing coordinates, lengths and opacities.
+// The default precision is 5.
+func Precision(n int) option {
+	return func(c *Canvas) {
+		if n <= 0 {
+			panic("vgsvg: precision must be > 0")
+		}
+		c.pr = n
+	}
+}
+
+// MinifyPaths specifies whether path data should be written in
+// its most compact form, eliding repeated commands and redundant
+// characters.
+func MinifyPaths(v bool) option {
+	return func(c *Canvas) {
+		c.minify = v
+	}
+}
+
+// StyleClasses specifies whether styles should be deduplicated
+// into CSS classes declared once in the SVG document, instead of
+// being repeated on each element.
+// Classes are named after their order of first use, so identical
+// plots produce identical documents.
+func StyleClasses(v bool) option {
+	return func(c *Canvas) {
+		c.css = v
+	}
+}
+
 // New returns a new image canvas.
 func New(w, h vg.Length) *Canvas {
 	return NewWith(UseWH(w, h))
 }
 
 // NewWith returns a new image canvas created according to the specified
-// options. The currently accepted options is UseWH. If size is not
-// specified, the default is used.
+// options. The currently accepted options are UseWH, EmbedFonts,
+// OutlineText, Precision, MinifyPaths and StyleClasses.
+// If size is not specified, the default is used.
 func NewWith(opts ...option) *Canvas {
 	buf := new(bytes.Buffer)
 	c := &Canvas{
@@ -123,6 +191,7 @@ func NewWith(opts ...option) *Canvas {
 		stack: []context{{}},
 		embed: false,
 		fonts: make(map[string]struct{}),
+		pr:    defaultPrecision,
 	}
 
 	for _, opt := range opts {
@@ -136,20 +205,25 @@ func NewWith(opts ...option) *Canvas {
 <svg width="%.*gpt" height="%.*gpt" viewBox="0 0 %.*g %.*g"
 	xmlns="http://www.w3.org/2000/svg"
 	xmlns:xlink="http://www.w3.org/1999/xlink">`+"\n",
-		pr, c.w,
-		pr, c.h,
-		pr, c.w,
-		pr, c.h,
+		c.pr, c.w,
+		c.pr, c.h,
+		c.pr, c.w,
+		c.pr, c.h,
 	)
 
-	if c.embed {
+	if c.embed || c.css {
 		fmt.Fprintf(c.hdr, "<defs>\n\t<style>\n")
 	}
+	if c.css {
+		c.classes = make(map[string]string)
+	}
 
-	// Swap the origin to the bottom left.
+	// Swap the origin to the bottom left and set the
+	// initial line join and miter limit.
 	// This must be matched with a </g> when saving,
 	// before the closing </svg>.
-	c.svg.Gtransform(fmt.Sprintf("scale(1, -1) translate(0, -%.*g)", pr, c.h.Points()))
+	fmt.Fprintf(c.buf, "<g transform=\"scale(1, -1) translate(0, -%.*g)\" stroke-linejoin=\"round\" stroke-miterlimit=\"%d\">\n",
+		c.pr, c.h.Points(), vg.DefaultMiterLimit)
 
 	vg.Initialize(c)
 	return c
@@ -183,7 +257,7 @@ func (c *Canvas) Rotate(rot float64) {
 }
 
 func (c *Canvas) Translate(pt vg.Point) {
-	c.svg.Gtransform(fmt.Sprintf("translate(%.*g, %.*g)", pr, pt.X.Points(), pr, pt.Y.Points()))
+	c.svg.Gtransform(fmt.Sprintf("translate(%.*g, %.*g)", c.pr, pt.X.Points(), c.pr, pt.Y.Points()))
 	c.context().gEnds++
 }
 
@@ -210,76 +284,310 @@ func (c *Canvas) Stroke(path vg.Path) {
 		return
 	}
 	c.svg.Path(c.pathData(path),
-		style(elm("fill", "#000000", "none"),
+		c.style(elm("fill", "#000000", "none"),
 			elm("stroke", "none", colorString(c.context().color)),
-			elm("stroke-opacity", "1", opacityString(c.context().color)),
-			elm("stroke-width", "1", "%.*g", pr, c.context().lineWidth.Points()),
+			elm("stroke-opacity", "1", opacityString(c.context().color, c.pr)),
+			elm("stroke-width", "1", "%.*g", c.pr, c.context().lineWidth.Points()),
 			elm("stroke-dasharray", "none", dashArrayString(c)),
-			elm("stroke-dashoffset", "0", "%.*g", pr, c.context().dashOffset.Points())))
+			elm("stroke-dashoffset", "0", "%.*g", c.pr, c.context().dashOffset.Points()),
+			elm("stroke-linecap", "butt", lineCapString(c.context().lineCap)),
+			elm("stroke-linejoin", "round", lineJoinString(c.context().lineJoin)),
+			elm("stroke-miterlimit", strconv.Itoa(vg.DefaultMiterLimit), "%.*g", c.pr, c.context().miterLimit)))
+}
+
+// SetLineCap implements the vg.LineStyler interface.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.context().lineCap = capStyle
+}
+
+// SetLineJoin implements the vg.LineStyler interface.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.context().lineJoin = joinStyle
+}
+
+// SetMiterLimit implements the vg.LineStyler interface.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.context().miterLimit = limit
 }
 
 func (c *Canvas) Fill(path vg.Path) {
+	if h, ok := c.context().color.(*vg.Hatch); ok {
+		c.svg.Path(c.pathData(path), c.style(elm("fill", "", "url(#%s)", c.hatch(h))))
+		return
+	}
+	if g, ok := c.context().color.(vg.Gradient); ok {
+		id, ok := c.gradient(g)
+		if !ok {
+			vg.FillGradient(c, path, g)
+			return
+		}
+		c.svg.Path(c.pathData(path), c.style(elm("fill", "", "url(#%s)", id)))
+		return
+	}
 	c.svg.Path(c.pathData(path),
-		style(elm("fill", "#000000", colorString(c.context().color)),
-			elm("fill-opacity", "1", opacityString(c.context().color))))
+		c.style(elm("fill", "#000000", colorString(c.context().color)),
+			elm("fill-opacity", "1", opacityString(c.context().color, c.pr))))
+}
+
+// Clip implements the vg.Clipper interface.
+// The clipping region is applied to a new group, closed by Pop.
+func (c *Canvas) Clip(path vg.Path) {
+	id := fmt.Sprintf("clip%d", c.clips)
+	c.clips++
+	fmt.Fprintf(c.buf, "<clipPath id=%q>\n<path d=%q />\n</clipPath>\n", id, c.pathData(path))
+	fmt.Fprintf(c.buf, "<g clip-path=\"url(#%s)\">\n", id)
+	c.context().gEnds++
+}
+
+// DrawGroup implements the vg.Grouper interface.
+func (c *Canvas) DrawGroup(g vg.Group, fn func(vg.Canvas)) {
+	attr := c.style(
+		elm("opacity", "1", "%.*g", c.pr, g.Alpha()),
+		elm("mix-blend-mode", "normal", blendString(g.Blend)),
+	)
+	if attr == "" {
+		c.buf.WriteString("<g>\n")
+	} else {
+		fmt.Fprintf(c.buf, "<g %s>\n", attr)
+	}
+	c.Push()
+	fn(c)
+	c.Pop()
+	c.buf.WriteString("</g>\n")
+}
+
+// gradient returns the ID of the SVG definition of the
+// gradient, writing the definition if needed.
+// gradient returns false if the gradient can not be
+// represented in SVG.
+func (c *Canvas) gradient(g vg.Gradient) (string, bool) {
+	var (
+		tag   string
+		attrs string
+		stops []vg.GradientStop
+	)
+	switch g := g.(type) {
+	case *vg.LinearGradient:
+		tag = "linearGradient"
+		attrs = fmt.Sprintf(`x1="%.*g" y1="%.*g" x2="%.*g" y2="%.*g"`,
+			c.pr, g.X1, c.pr, g.Y1, c.pr, g.X2, c.pr, g.Y2)
+		stops = g.Stops
+	case *vg.RadialGradient:
+		tag = "radialGradient"
+		attrs = fmt.Sprintf(`cx="%.*g" cy="%.*g" r="%.*g"`, c.pr, g.CX, c.pr, g.CY, c.pr, g.R)
+		stops = g.Stops
+	default:
+		return "", false
+	}
+	body := ""
+	for _, s := range stops {
+		body += fmt.Sprintf("<stop offset=\"%.*g\" stop-color=\"%s\" stop-opacity=\"%s\" />\n",
+			c.pr, s.Offset, colorString(s.Color), opacityString(s.Color, c.pr))
+	}
+
+	return c.define("grad", tag, attrs, body), true
+}
+
+// hatch returns the ID of the SVG pattern drawing the hatch,
+// writing the definition if needed.
+func (c *Canvas) hatch(h *vg.Hatch) string {
+	var (
+		s     = h.HatchSpacing().Points()
+		w     = h.HatchWidth().Points()
+		angle = h.Angle
+		body  = ""
+	)
+	if h.Style == vg.HatchDiagonal {
+		angle += math.Pi / 4
+	}
+	if h.Background != nil {
+		body += fmt.Sprintf("<rect width=\"%.*g\" height=\"%.*g\" %s />\n", c.pr, s, c.pr, s,
+			c.style(elm("fill", "#000000", colorString(h.Background)),
+				elm("fill-opacity", "1", opacityString(h.Background, c.pr))))
+	}
+	stroke := c.style(elm("fill", "", "none"),
+		elm("stroke", "none", colorString(h)),
+		elm("stroke-opacity", "1", opacityString(h, c.pr)),
+		elm("stroke-width", "1", "%.*g", c.pr, w))
+	switch h.Style {
+	case vg.HatchDots:
+		body += fmt.Sprintf("<circle cx=\"%.*g\" cy=\"%.*g\" r=\"%.*g\" %s />\n", c.pr, s/2, c.pr, s/2, c.pr, w/2,
+			c.style(elm("fill", "#000000", colorString(h)),
+				elm("fill-opacity", "1", opacityString(h, c.pr))))
+	case vg.HatchCross:
+		body += fmt.Sprintf("<path d=\"M0,%.*gH%.*gM%.*g,0V%.*g\" %s />\n", c.pr, s/2, c.pr, s, c.pr, s/2, c.pr, s, stroke)
+	case vg.HatchVertical:
+		body += fmt.Sprintf("<path d=\"M%.*g,0V%.*g\" %s />\n", c.pr, s/2, c.pr, s, stroke)
+	default:
+		body += fmt.Sprintf("<path d=\"M0,%.*gH%.*g\" %s />\n", c.pr, s/2, c.pr, s, stroke)
+	}
+	attrs := fmt.Sprintf(`patternUnits="userSpaceOnUse" width="%.*g" height="%.*g"`, c.pr, s, c.pr, s)
+	if angle != 0 {
+		attrs += fmt.Sprintf(` patternTransform="rotate(%.*g)"`, c.pr, angle*180/math.Pi)
+	}
+	return c.define("pat", "pattern", attrs, body)
+}
+
+// define returns the ID of the definition of the given element,
+// writing the definition if it was not already written.
+// New IDs are made of the prefix and a unique number.
+func (c *Canvas) define(prefix, tag, attrs, body string) string {
+	key := tag + " " + attrs + "\n" + body
+	id, ok := c.defs[key]
+	if !ok {
+		if c.defs == nil {
+			c.defs = make(map[string]string)
+		}
+		id = prefix + strconv.Itoa(len(c.defs))
+		c.defs[key] = id
+		fmt.Fprintf(c.buf, "<%s id=%q %s>\n%s</%s>\n", tag, id, attrs, body, tag)
+	}
+	return id
 }
 
 func (c *Canvas) pathData(path vg.Path) string {
-	buf := new(bytes.Buffer)
-	var x, y float64
+	w := &pathWriter{pr: c.pr, minify: c.minify}
+	// (x, y) is the current point and (sx, sy) the
+	// start of the current subpath.
+	var x, y, sx, sy float64
 	for _, comp := range path {
 		switch comp.Type {
 		case vg.MoveComp:
-			fmt.Fprintf(buf, "M%.*g,%.*g", pr, comp.Pos.X.Points(), pr, comp.Pos.Y.Points())
 			x = comp.Pos.X.Points()
 			y = comp.Pos.Y.Points()
+			sx, sy = x, y
+			w.cmd('M', x, y)
 		case vg.LineComp:
-			fmt.Fprintf(buf, "L%.*g,%.*g", pr, comp.Pos.X.Points(), pr, comp.Pos.Y.Points())
-			x = comp.Pos.X.Points()
-			y = comp.Pos.Y.Points()
+			x, y = w.line(x, y, comp.Pos.X.Points(), comp.Pos.Y.Points())
 		case vg.ArcComp:
 			r := comp.Radius.Points()
 			sin, cos := math.Sincos(comp.Start)
 			x0 := comp.Pos.X.Points() + r*cos
 			y0 := comp.Pos.Y.Points() + r*sin
 			if x0 != x || y0 != y {
-				fmt.Fprintf(buf, "L%.*g,%.*g", pr, x0, pr, y0)
+				w.line(x, y, x0, y0)
 			}
 			if math.Abs(comp.Angle) >= 2*math.Pi {
-				x, y = circle(buf, c, &comp)
+				x, y = circle(w, &comp)
 			} else {
-				x, y = arc(buf, c, &comp)
+				x, y = arc(w, &comp)
 			}
 		case vg.CurveComp:
 			switch len(comp.Control) {
 			case 1:
-				fmt.Fprintf(buf, "Q%.*g,%.*g,%.*g,%.*g",
-					pr, comp.Control[0].X.Points(), pr, comp.Control[0].Y.Points(),
-					pr, comp.Pos.X.Points(), pr, comp.Pos.Y.Points())
+				w.cmd('Q',
+					comp.Control[0].X.Points(), comp.Control[0].Y.Points(),
+					comp.Pos.X.Points(), comp.Pos.Y.Points())
 			case 2:
-				fmt.Fprintf(buf, "C%.*g,%.*g,%.*g,%.*g,%.*g,%.*g",
-					pr, comp.Control[0].X.Points(), pr, comp.Control[0].Y.Points(),
-					pr, comp.Control[1].X.Points(), pr, comp.Control[1].Y.Points(),
-					pr, comp.Pos.X.Points(), pr, comp.Pos.Y.Points())
+				w.cmd('C',
+					comp.Control[0].X.Points(), comp.Control[0].Y.Points(),
+					comp.Control[1].X.Points(), comp.Control[1].Y.Points(),
+					comp.Pos.X.Points(), comp.Pos.Y.Points())
 			default:
 				panic("vgsvg: invalid number of control points")
 			}
 			x = comp.Pos.X.Points()
 			y = comp.Pos.Y.Points()
 		case vg.CloseComp:
-			buf.WriteString("Z")
+			w.cmd('Z')
+			x, y = sx, sy
 		default:
 			panic(fmt.Sprintf("vgsvg: unknown path component type: %d", comp.Type))
 		}
 	}
-	return buf.String()
+	return w.buf.String()
+}
+
+// pathWriter writes SVG path data.
+//
+// When minify is set, repeated commands are elided, axis-aligned
+// lines are written with the H and V commands and numbers are
+// written with the fewest characters possible.
+type pathWriter struct {
+	buf    bytes.Buffer
+	pr     int
+	minify bool
+	last   byte // last command written to buf
+}
+
+// cmd writes the command op with its arguments.
+func (w *pathWriter) cmd(op byte, args ...float64) {
+	if !w.minify {
+		w.buf.WriteByte(op)
+		for i, v := range args {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.buf.WriteString(strconv.FormatFloat(v, 'g', w.pr, 64))
+		}
+		return
+	}
+
+	// Consecutive commands of the same type may omit the command letter,
+	// and coordinates following a moveto are implicit linetos.
+	implicit := op != 'Z' && (op == w.last || (op == 'L' && w.last == 'M'))
+	if !implicit {
+		w.buf.WriteByte(op)
+	}
+	for i, v := range args {
+		str := minNum(v, w.pr)
+		if (i > 0 || implicit) && str[0] != '-' {
+			w.buf.WriteByte(',')
+		}
+		w.buf.WriteString(str)
+	}
+	w.last = op
+	if op == 'M' {
+		w.last = 'L'
+	}
+}
+
+// line writes a line from (x0, y0) to (x, y) and
+// returns the new current point.
+func (w *pathWriter) line(x0, y0, x, y float64) (float64, float64) {
+	switch {
+	case !w.minify:
+		w.cmd('L', x, y)
+	case minNum(y, w.pr) == minNum(y0, w.pr):
+		w.cmd('H', x)
+	case minNum(x, w.pr) == minNum(x0, w.pr):
+		w.cmd('V', y)
+	default:
+		w.cmd('L', x, y)
+	}
+	return x, y
+}
+
+// arc writes an elliptical arc of radius r ending at (x, y).
+func (w *pathWriter) arc(r float64, large, sweep int, x, y float64) {
+	if !w.minify {
+		fmt.Fprintf(&w.buf, "A%.*g,%.*g 0 %d %d %.*g,%.*g", w.pr, r, w.pr, r,
+			large, sweep, w.pr, x, w.pr, y)
+		return
+	}
+	w.cmd('A', r, r, 0, float64(large), float64(sweep), x, y)
+}
+
+// minNum returns the shortest representation of v
+// with the provided precision.
+func minNum(v float64, pr int) string {
+	str := strconv.FormatFloat(v, 'g', pr, 64)
+	switch {
+	case str == "-0":
+		return "0"
+	case strings.HasPrefix(str, "0."):
+		return str[1:]
+	case strings.HasPrefix(str, "-0."):
+		return "-" + str[2:]
+	}
+	return str
 }
 
 // circle adds circle path data to the given writer.
 // Circles must be drawn using two arcs because
 // SVG disallows the start and end point of an arc
 // from being at the same location.
-func circle(w io.Writer, c *Canvas, comp *vg.PathComp) (x, y float64) {
+func circle(w *pathWriter, comp *vg.PathComp) (x, y float64) {
 	angle := 2 * math.Pi
 	if comp.Angle < 0 {
 		angle = -2 * math.Pi
@@ -298,10 +606,8 @@ func circle(w io.Writer, c *Canvas, comp *vg.PathComp) (x, y float64) {
 	x = comp.Pos.X.Points() + r*c1
 	y = comp.Pos.Y.Points() + r*s1
 
-	fmt.Fprintf(w, "A%.*g,%.*g 0 %d %d %.*g,%.*g", pr, r, pr, r,
-		large(angle/2), sweep(angle/2), pr, x0, pr, y0) //
-	fmt.Fprintf(w, "A%.*g,%.*g 0 %d %d %.*g,%.*g", pr, r, pr, r,
-		large(angle/2), sweep(angle/2), pr, x, pr, y)
+	w.arc(r, large(angle/2), sweep(angle/2), x0, y0)
+	w.arc(r, large(angle/2), sweep(angle/2), x, y)
 	return
 }
 
@@ -317,13 +623,12 @@ func remainder(x, y float64) float64 {
 // Arc can only be used if the arc's angle is
 // less than a full circle, if it is greater then
 // circle should be used instead.
-func arc(w io.Writer, c *Canvas, comp *vg.PathComp) (x, y float64) {
+func arc(w *pathWriter, comp *vg.PathComp) (x, y float64) {
 	r := comp.Radius.Points()
 	sin, cos := math.Sincos(comp.Start + comp.Angle)
 	x = comp.Pos.X.Points() + r*cos
 	y = comp.Pos.Y.Points() + r*sin
-	fmt.Fprintf(w, "A%.*g,%.*g 0 %d %d %.*g,%.*g", pr, r, pr, r,
-		large(comp.Angle), sweep(comp.Angle), pr, x, pr, y)
+	w.arc(r, large(comp.Angle), sweep(comp.Angle), x, y)
 	return
 }
 
@@ -347,12 +652,22 @@ func large(a float64) int {
 
 // FillString draws str at position pt using the specified font.
 // Text passed to FillString is escaped with html.EscapeString.
+//
+// If the canvas was created with OutlineText, the glyphs are drawn
+// as filled paths and the text element is made fully transparent.
 func (c *Canvas) FillString(font font.Face, pt vg.Point, str string) {
 	name := svgFontDescr(font)
-	sty := style(
+	fill := elm("fill", "#000000", colorString(c.context().color))
+	if c.outline {
+		if path := glyphPath(font, pt, str); len(path) > 0 {
+			c.Fill(path)
+		}
+		fill = "fill-opacity:0"
+	}
+	sty := c.style(
 		name,
-		elm("font-size", "medium", "%.*gpx", pr, font.Font.Size.Points()),
-		elm("fill", "#000000", colorString(c.context().color)),
+		elm("font-size", "medium", "%.*gpx", c.pr, font.Font.Size.Points()),
+		fill,
 	)
 	if sty != "" {
 		sty = "\n\t" + sty
@@ -360,14 +675,95 @@ func (c *Canvas) FillString(font font.Face, pt vg.Point, str string) {
 	fmt.Fprintf(
 		c.buf,
 		`<text x="%.*g" y="%.*g" transform="scale(1, -1)"%s>%s</text>`+"\n",
-		pr, pt.X.Points(), pr, -pt.Y.Points(), sty, html.EscapeString(str),
+		c.pr, pt.X.Points(), c.pr, -pt.Y.Points(), sty, html.EscapeString(str),
 	)
 
-	if c.embed {
+	if c.embed && !c.outline {
 		c.embedFont(name, font)
 	}
 }
 
+// glyphPath returns the outlines of the glyphs of str drawn with
+// the provided font, with the text baseline starting at pt.
+func glyphPath(fnt font.Face, pt vg.Point, str string) vg.Path {
+	if fnt.Font.Size == 0 {
+		return nil
+	}
+
+	var (
+		buf  sfnt.Buffer
+		ppem = fixed.Int26_6(fnt.Face.UnitsPerEm())
+
+		// scale converts sfnt.Unit to points.
+		scale = fnt.Font.Size.Points() / float64(ppem)
+
+		path      vg.Path
+		dot       fixed.Int26_6
+		hasPrev   bool
+		prev, idx sfnt.GlyphIndex
+	)
+
+	pos := func(p fixed.Point26_6) vg.Point {
+		// sfnt segments have their Y axis increasing downwards.
+		return vg.Point{
+			X: pt.X + vg.Length(float64(dot+p.X)*scale),
+			Y: pt.Y - vg.Length(float64(p.Y)*scale),
+		}
+	}
+
+	for _, r := range str {
+		var err error
+		idx, err = fnt.Face.GlyphIndex(&buf, r)
+		if err != nil {
+			panic(fmt.Errorf("vgsvg: could not get glyph index: %+v", err))
+		}
+		if hasPrev {
+			kern, err := fnt.Face.Kern(&buf, prev, idx, ppem, xfnt.HintingNone)
+			switch {
+			case err == nil:
+				dot += kern
+			case errors.Is(err, sfnt.ErrNotFound):
+				// no-op
+			default:
+				panic(fmt.Errorf("vgsvg: could not get kerning: %+v", err))
+			}
+		}
+
+		segs, err := fnt.Face.LoadGlyph(&buf, idx, ppem, nil)
+		if err != nil {
+			panic(fmt.Errorf("vgsvg: could not load glyph %q: %+v", r, err))
+		}
+		for i, seg := range segs {
+			switch seg.Op {
+			case sfnt.SegmentOpMoveTo:
+				if i > 0 {
+					path.Close()
+				}
+				path.Move(pos(seg.Args[0]))
+			case sfnt.SegmentOpLineTo:
+				path.Line(pos(seg.Args[0]))
+			case sfnt.SegmentOpQuadTo:
+				path.QuadTo(pos(seg.Args[0]), pos(seg.Args[1]))
+			case sfnt.SegmentOpCubeTo:
+				path.CubeTo(pos(seg.Args[0]), pos(seg.Args[1]), pos(seg.Args[2]))
+			default:
+				panic(fmt.Errorf("vgsvg: unknown glyph segment operation %d", seg.Op))
+			}
+		}
+		if len(segs) > 0 {
+			path.Close()
+		}
+
+		adv, err := fnt.Face.GlyphAdvance(&buf, idx, ppem, xfnt.HintingNone)
+		if err != nil {
+			panic(fmt.Errorf("vgsvg: could not retrieve glyph's advance: %+v", err))
+		}
+		dot += adv
+		prev, hasPrev = idx, true
+	}
+	return path
+}
+
 // DrawImage implements the vg.Canvas.DrawImage method.
 func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
 	buf := new(bytes.Buffer)
@@ -386,11 +782,11 @@ func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
 	)
 	fmt.Fprintf(
 		c.buf,
-		`<image x="%v" y="%v" width="%v" height="%v" xlink:href="%s" %s />`+"\n",
-		xmin,
-		-ymin-height,
-		width,
-		height,
+		`<image x="%.*g" y="%.*g" width="%.*g" height="%.*g" xlink:href="%s" %s />`+"\n",
+		c.pr, xmin,
+		c.pr, -ymin-height,
+		c.pr, width,
+		c.pr, height,
 		str,
 		// invert y so image is not upside-down
 		`transform="scale(1, -1)"`,
@@ -539,7 +935,12 @@ func (c *cwriter) Write(p []byte) (int, error) {
 func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
 	b := &cwriter{w: bufio.NewWriter(w)}
 
-	if c.embed {
+	if c.css {
+		for _, sty := range c.styles {
+			fmt.Fprintf(c.hdr, "\t\t.%s{%s}\n", c.classes[sty], sty)
+		}
+	}
+	if c.embed || c.css {
 		fmt.Fprintf(c.hdr, "\t</style>\n</defs>\n")
 	}
 
@@ -602,6 +1003,25 @@ func style(elms ...string) string {
 	return "style=\"" + str + "\""
 }
 
+// style returns the attribute applying the style composed
+// of all of the given elements.
+// If the canvas shares styles through CSS classes, the
+// returned attribute is the class attribute for that style.
+func (c *Canvas) style(elms ...string) string {
+	attr := style(elms...)
+	if !c.css || attr == "" {
+		return attr
+	}
+	sty := attr[len(`style="`) : len(attr)-1]
+	name, ok := c.classes[sty]
+	if !ok {
+		name = "s" + strconv.Itoa(len(c.styles))
+		c.classes[sty] = name
+		c.styles = append(c.styles, sty)
+	}
+	return `class="` + name + `"`
+}
+
 // elm returns a style element string with the
 // given key and value.  If the value matches
 // default then the empty string is returned.
@@ -618,7 +1038,7 @@ func elm(key, def, f string, vls ...interface{}) string {
 func dashArrayString(c *Canvas) string {
 	str := ""
 	for i, d := range c.context().dashArray {
-		str += fmt.Sprintf("%.*g", pr, d.Points())
+		str += fmt.Sprintf("%.*g", c.pr, d.Points())
 		if i < len(c.context().dashArray)-1 {
 			str += ","
 		}
@@ -635,13 +1055,58 @@ func colorString(clr color.Color) string {
 		clr = color.Black
 	}
 	r, g, b, _a := clr.RGBA()
+	if _a == 0 {
+		return "#000000"
+	}
 	a := 255.0 / float64(_a)
 	return fmt.Sprintf("#%02X%02X%02X", int(float64(r)*a),
 		int(float64(g)*a), int(float64(b)*a))
 }
 
+// blendString returns the CSS name of the blend mode.
+func blendString(mode vg.BlendMode) string {
+	switch mode {
+	case vg.BlendMultiply:
+		return "multiply"
+	case vg.BlendScreen:
+		return "screen"
+	case vg.BlendOverlay:
+		return "overlay"
+	case vg.BlendDarken:
+		return "darken"
+	case vg.BlendLighten:
+		return "lighten"
+	default:
+		return "normal"
+	}
+}
+
+// lineCapString returns the SVG name of the line cap.
+func lineCapString(capStyle vg.LineCap) string {
+	switch capStyle {
+	case vg.RoundCap:
+		return "round"
+	case vg.SquareCap:
+		return "square"
+	default:
+		return "butt"
+	}
+}
+
+// lineJoinString returns the SVG name of the line join.
+func lineJoinString(joinStyle vg.LineJoin) string {
+	switch joinStyle {
+	case vg.MiterJoin:
+		return "miter"
+	case vg.BevelJoin:
+		return "bevel"
+	default:
+		return "round"
+	}
+}
+
 // opacityString returns the opacity value of the given color.
-func opacityString(clr color.Color) string {
+func opacityString(clr color.Color, pr int) string {
 	if clr == nil {
 		clr = color.Black
 	}
@@ -7,6 +7,7 @@ package vgsvg_test
 import (
 	"bytes"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/emptywe/plot"
@@ -122,3 +123,183 @@ func TestHtmlEscape(t *testing.T) {
 		t.Fatalf("images differ:\ngot:\n%s\nwant:\n%s\n", b.Bytes(), want)
 	}
 }
+
+func TestOutlineText(t *testing.T) {
+	fnt := plot.DefaultFont
+	fnt.Size = 12
+	face := plot.DefaultTextHandler.Cache().Lookup(fnt, fnt.Size)
+
+	for _, tc := range []struct {
+		outline bool
+		paths   int
+	}{
+		{outline: false, paths: 0},
+		{outline: true, paths: 1},
+	} {
+		c := vgsvg.NewWith(
+			vgsvg.UseWH(5*vg.Centimeter, 5*vg.Centimeter),
+			vgsvg.OutlineText(tc.outline),
+		)
+		c.FillString(face, vg.Point{X: 10, Y: 10}, "Gonum & plot")
+		c.FillString(face, vg.Point{X: 10, Y: 30}, " ")
+
+		b := new(bytes.Buffer)
+		if _, err := c.WriteTo(b); err != nil {
+			t.Fatal(err)
+		}
+		svg := b.String()
+
+		// Blank text must not produce any outline.
+		if got, want := strings.Count(svg, "<path "), tc.paths; got != want {
+			t.Errorf("outline=%v: invalid number of paths: got=%d, want=%d\n%s", tc.outline, got, want, svg)
+		}
+		if got, want := strings.Count(svg, "<text "), 2; got != want {
+			t.Errorf("outline=%v: invalid number of texts: got=%d, want=%d\n%s", tc.outline, got, want, svg)
+		}
+		if !strings.Contains(svg, ">Gonum &amp; plot</text>") {
+			t.Errorf("outline=%v: missing text content:\n%s", tc.outline, svg)
+		}
+		if got, want := strings.Contains(svg, "fill-opacity:0\""), tc.outline; got != want {
+			t.Errorf("outline=%v: invalid text visibility:\n%s", tc.outline, svg)
+		}
+	}
+}
+
+func TestMinifyPaths(t *testing.T) {
+	var p vg.Path
+	p.Move(vg.Point{X: 0, Y: 0})
+	p.Line(vg.Point{X: 10, Y: 0})
+	p.Line(vg.Point{X: 10, Y: 10})
+	p.Line(vg.Point{X: 0.5, Y: -0.25})
+	p.Line(vg.Point{X: 1.123456, Y: 2})
+	p.Close()
+
+	for _, tc := range []struct {
+		c    *vgsvg.Canvas
+		want string
+	}{
+		{
+			c:    vgsvg.NewWith(),
+			want: `d="M0,0L10,0L10,10L0.5,-0.25L1.1235,2Z"`,
+		},
+		{
+			c:    vgsvg.NewWith(vgsvg.MinifyPaths(true)),
+			want: `d="M0,0H10V10L.5-.25,1.1235,2Z"`,
+		},
+		{
+			c:    vgsvg.NewWith(vgsvg.MinifyPaths(true), vgsvg.Precision(2)),
+			want: `d="M0,0H10V10L.5-.25,1.1,2Z"`,
+		},
+	} {
+		c := tc.c
+		c.Fill(p)
+
+		b := new(bytes.Buffer)
+		if _, err := c.WriteTo(b); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), tc.want) {
+			t.Errorf("invalid path data: want %s\n%s", tc.want, b.String())
+		}
+	}
+}
+
+func TestMinifyPathsClose(t *testing.T) {
+	// After a close the current point is the start of the
+	// subpath, so the line from (0, 0) to (0, 10) is vertical.
+	var p vg.Path
+	p.Move(vg.Point{X: 0, Y: 0})
+	p.Line(vg.Point{X: 10, Y: 0})
+	p.Line(vg.Point{X: 10, Y: 10})
+	p.Close()
+	p.Line(vg.Point{X: 0, Y: 10})
+
+	c := vgsvg.NewWith(vgsvg.MinifyPaths(true))
+	c.Stroke(p)
+
+	b := new(bytes.Buffer)
+	if _, err := c.WriteTo(b); err != nil {
+		t.Fatal(err)
+	}
+	const want = `d="M0,0H10V10ZV10"`
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("invalid path data: want %s\n%s", want, b.String())
+	}
+}
+
+func TestStyleClasses(t *testing.T) {
+	render := func(c *vgsvg.Canvas) []byte {
+		p := plot.New()
+		p.Title.Text = "Scatter & line plot"
+		p.X.Label.Text = "X"
+		p.Y.Label.Text = "Y"
+		p.Add(plotter.NewGrid())
+
+		pts := plotter.XYs{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}, {X: 0.5, Y: 0.25}}
+		scatter, err := plotter.NewScatter(pts)
+		if err != nil {
+			t.Fatalf("could not create scatter: %v", err)
+		}
+		line, err := plotter.NewLine(pts)
+		if err != nil {
+			t.Fatalf("could not create line: %v", err)
+		}
+		p.Add(scatter, line)
+		p.Draw(draw.New(c))
+
+		b := new(bytes.Buffer)
+		if _, err = c.WriteTo(b); err != nil {
+			t.Fatal(err)
+		}
+		return b.Bytes()
+	}
+
+	compact := func() *vgsvg.Canvas {
+		return vgsvg.NewWith(
+			vgsvg.UseWH(5*vg.Centimeter, 5*vg.Centimeter),
+			vgsvg.StyleClasses(true),
+			vgsvg.MinifyPaths(true),
+			vgsvg.Precision(3),
+		)
+	}
+
+	ref := render(vgsvg.NewWith(vgsvg.UseWH(5*vg.Centimeter, 5*vg.Centimeter)))
+	got := render(compact())
+	if again := render(compact()); !bytes.Equal(got, again) {
+		t.Fatalf("compact SVG output is not deterministic")
+	}
+	if len(got) >= len(ref) {
+		t.Errorf("compact SVG is not smaller: got=%d, ref=%d", len(got), len(ref))
+	}
+	if bytes.Contains(got, []byte("style=")) {
+		t.Errorf("compact SVG has inline styles:\n%s", got)
+	}
+	if !bytes.Contains(got, []byte("\t\t.s0{")) || !bytes.Contains(got, []byte(`class="s0"`)) {
+		t.Errorf("compact SVG is missing style classes:\n%s", got)
+	}
+}
+
+func TestClip(t *testing.T) {
+	c := vgsvg.NewWith(vgsvg.UseWH(10, 10))
+	c.Push()
+	c.Clip(vg.Rectangle{Max: vg.Point{X: 5, Y: 10}}.Path())
+	c.Fill(vg.Rectangle{Max: vg.Point{X: 10, Y: 10}}.Path())
+	c.Pop()
+
+	b := new(bytes.Buffer)
+	if _, err := c.WriteTo(b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"<clipPath id=\"clip0\">\n<path d=\"M0,0L5,0L5,10L0,10Z\" />\n</clipPath>\n",
+		"<g clip-path=\"url(#clip0)\">\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in SVG output:\n%s", want, got)
+		}
+	}
+	if n, m := strings.Count(got, "<g"), strings.Count(got, "</g>"); n != m {
+		t.Errorf("unbalanced groups: %d opened, %d closed", n, m)
+	}
+}
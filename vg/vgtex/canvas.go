@@ -62,6 +62,9 @@ type context struct {
 	dashArray  []vg.Length
 	dashOffset vg.Length
 	linew      vg.Length
+	cap        vg.LineCap
+	join       vg.LineJoin
+	miter      float64
 }
 
 // New returns a new LaTeX canvas.
@@ -91,7 +94,7 @@ func newCanvas(w, h vg.Length, document bool) *Canvas {
 	}
 	c.wtex("")
 	c.wtex(`\begin{pgfpicture}`)
-	c.stack = make([]context, 1)
+	c.stack = []context{{miter: vg.DefaultMiterLimit}}
 	vg.Initialize(c)
 	return c
 }
@@ -121,6 +124,21 @@ func (c *Canvas) SetColor(clr color.Color) {
 	c.context().color = clr
 }
 
+// SetLineCap implements the vg.LineStyler interface.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.context().cap = capStyle
+}
+
+// SetLineJoin implements the vg.LineStyler interface.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.context().join = joinStyle
+}
+
+// SetMiterLimit implements the vg.LineStyler interface.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.context().miter = limit
+}
+
 // Rotate implements the vg.Canvas.Rotate method.
 func (c *Canvas) Rotate(rad float64) {
 	c.wtex(`\pgftransformrotate{%g}`, rad*degPerRadian)
@@ -164,6 +182,14 @@ func (c *Canvas) Stroke(p vg.Path) {
 
 // Fill implements the vg.Canvas.Fill method.
 func (c *Canvas) Fill(p vg.Path) {
+	if h, ok := c.context().color.(*vg.Hatch); ok {
+		vg.FillHatch(c, p, h)
+		return
+	}
+	if g, ok := c.context().color.(vg.Gradient); ok {
+		vg.FillGradient(c, p, g)
+		return
+	}
 	c.Push()
 	c.wstyle()
 	c.wpath(p)
@@ -171,6 +197,13 @@ func (c *Canvas) Fill(p vg.Path) {
 	c.Pop()
 }
 
+// Clip implements the vg.Clipper interface.
+// The clipping region lasts until the end of the current pgfscope.
+func (c *Canvas) Clip(p vg.Path) {
+	c.wpath(p)
+	c.wtex(`\pgfusepath{clip}`)
+}
+
 // FillString implements the vg.Canvas.FillString method.
 func (c *Canvas) FillString(f font.Face, pt vg.Point, text string) {
 	c.Push()
@@ -216,6 +249,7 @@ func (c *Canvas) wtex(s string, args ...interface{}) {
 func (c *Canvas) wstyle() {
 	c.wdash()
 	c.wlineWidth()
+	c.wlineCapJoin()
 	c.wcolor()
 }
 
@@ -236,6 +270,26 @@ func (c *Canvas) wlineWidth() {
 	c.wtex(`\pgfsetlinewidth{%gpt}`, c.context().linew)
 }
 
+func (c *Canvas) wlineCapJoin() {
+	switch c.context().cap {
+	case vg.RoundCap:
+		c.wtex(`\pgfsetroundcap`)
+	case vg.SquareCap:
+		c.wtex(`\pgfsetrectcap`)
+	default:
+		c.wtex(`\pgfsetbuttcap`)
+	}
+	switch c.context().join {
+	case vg.MiterJoin:
+		c.wtex(`\pgfsetmiterjoin`)
+		c.wtex(`\pgfsetmiterlimit{%g}`, c.context().miter)
+	case vg.BevelJoin:
+		c.wtex(`\pgfsetbeveljoin`)
+	default:
+		c.wtex(`\pgfsetroundjoin`)
+	}
+}
+
 func (c *Canvas) wcolor() {
 	col := c.context().color
 	if col == nil {
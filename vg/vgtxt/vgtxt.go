@@ -0,0 +1,632 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vgtxt implements the vg.Canvas interface by drawing
+// to a grid of characters, that can be displayed in a terminal.
+//
+// Paths and images are rasterized and drawn with Unicode braille
+// patterns or block elements, optionally colored with ANSI escape
+// sequences. Text is drawn as characters of the grid.
+package vgtxt // import "github.com/emptywe/plot/vg/vgtxt"
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/vgimg"
+)
+
+func init() {
+	draw.RegisterFormat("txt", func(w, h vg.Length) vg.CanvasWriterTo {
+		return New(w, h)
+	})
+
+	draw.RegisterFormat("ansi", func(w, h vg.Length) vg.CanvasWriterTo {
+		return NewWith(UseWH(w, h), UseColor(Color256))
+	})
+}
+
+const (
+	// CellWidth and CellHeight are the dimensions of
+	// a character of the grid.
+	CellWidth  = 6 * vg.Length(1)
+	CellHeight = 12 * vg.Length(1)
+
+	// DefaultWidth and DefaultHeight are the default canvas
+	// dimensions.
+	DefaultWidth  = 4 * vg.Inch
+	DefaultHeight = 4 * vg.Inch
+)
+
+// Mode is the set of characters used to draw graphics.
+type Mode int
+
+const (
+	// Braille draws graphics with braille patterns, which
+	// have 2×4 dots per character.
+	Braille Mode = iota
+
+	// Blocks draws graphics with half block elements, which
+	// have 1×2 dots per character.
+	// When colors are used, the two dots of a character
+	// may have different colors.
+	Blocks
+)
+
+// ColorMode is the set of colors used to draw to the terminal.
+type ColorMode int
+
+const (
+	// NoColor draws without escape sequences.
+	NoColor ColorMode = iota
+
+	// Color256 draws with the 256 colors of ANSI terminals.
+	Color256
+
+	// TrueColor draws with 24-bit colors.
+	TrueColor
+)
+
+// supersampling is the number of pixels per dot, in each
+// direction, used to rasterize graphics.
+const supersampling = 4
+
+// threshold is the smallest difference of a pixel with
+// the background color for its dot to be drawn.
+const threshold = 0.25
+
+// Canvas implements the vg.Canvas interface,
+// drawing to a grid of characters.
+type Canvas struct {
+	img  *vgimg.Canvas
+	w, h vg.Length
+
+	mode  Mode
+	color ColorMode
+	bg    color.Color
+
+	// cols and rows are the dimensions of the grid.
+	cols, rows int
+
+	// text holds the characters drawn by FillString,
+	// row by row. A zero rune holds no character.
+	text []cell
+
+	// stack is the stack of states, pushed and
+	// popped with the image canvas.
+	stack []state
+}
+
+// cell is a character of the grid and its color.
+type cell struct {
+	r   rune
+	clr color.Color
+}
+
+// state is the part of the state of the canvas that is
+// needed to draw text.
+type state struct {
+	m     matrix
+	color color.Color
+}
+
+type option func(*Canvas)
+
+// UseWH specifies the width and height of the canvas.
+// The grid has as many characters as fit in the canvas,
+// rounded up.
+func UseWH(w, h vg.Length) option {
+	return func(c *Canvas) {
+		if w <= 0 || h <= 0 {
+			panic("vgtxt: w and h must both be > 0")
+		}
+		c.w, c.h = w, h
+	}
+}
+
+// UseMode specifies the characters used to draw graphics.
+// Without UseMode, braille patterns are used.
+func UseMode(m Mode) option {
+	return func(c *Canvas) {
+		c.mode = m
+	}
+}
+
+// UseColor specifies the colors used to draw to the terminal.
+// Without UseColor, no escape sequences are written.
+func UseColor(m ColorMode) option {
+	return func(c *Canvas) {
+		c.color = m
+	}
+}
+
+// UseBackgroundColor specifies the background color of the canvas.
+// Graphics of the background color are not drawn, so that the
+// background of the terminal shows through.
+// Without UseBackgroundColor, the default color is white.
+func UseBackgroundColor(clr color.Color) option {
+	return func(c *Canvas) {
+		c.bg = clr
+	}
+}
+
+// New returns a new text canvas.
+func New(w, h vg.Length) *Canvas {
+	return NewWith(UseWH(w, h))
+}
+
+// NewWith returns a new text canvas created according to the specified
+// options. The currently accepted options are UseWH, UseMode, UseColor
+// and UseBackgroundColor.
+// If size is not specified, the default is used.
+func NewWith(opts ...option) *Canvas {
+	c := &Canvas{
+		w:  DefaultWidth,
+		h:  DefaultHeight,
+		bg: color.White,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.cols = int(math.Ceil(float64(c.w / CellWidth)))
+	c.rows = int(math.Ceil(float64(c.h / CellHeight)))
+	c.text = make([]cell, c.cols*c.rows)
+
+	// The image has a whole number of characters, supersampled,
+	// and is translated for its top to match the top of the canvas.
+	dx, dy := c.dots()
+	c.img = vgimg.NewWith(
+		vgimg.UseImage(image.NewRGBA(image.Rect(0, 0,
+			c.cols*dx*supersampling,
+			c.rows*dy*supersampling,
+		))),
+		vgimg.UseDPI(dx*supersampling*int(vg.Inch/CellWidth)),
+	)
+	fill(c.img.Image().(*image.RGBA), c.bg)
+	_, h := c.img.Size()
+	c.img.Translate(vg.Point{Y: h - c.h})
+
+	c.stack = []state{{m: identity, color: color.Black}}
+	vg.Initialize(c)
+	return c
+}
+
+// dots returns the number of dots per character.
+func (c *Canvas) dots() (x, y int) {
+	if c.mode == Blocks {
+		return 1, 2
+	}
+	return 2, 4
+}
+
+// fill fills img with clr.
+func fill(img *image.RGBA, clr color.Color) {
+	r, g, b, a := clr.RGBA()
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i+0] = uint8(r >> 8)
+		img.Pix[i+1] = uint8(g >> 8)
+		img.Pix[i+2] = uint8(b >> 8)
+		img.Pix[i+3] = uint8(a >> 8)
+	}
+}
+
+// Size returns the width and height of the canvas.
+func (c *Canvas) Size() (w, h vg.Length) {
+	return c.w, c.h
+}
+
+// Grid returns the number of columns and rows of the grid.
+func (c *Canvas) Grid() (cols, rows int) {
+	return c.cols, c.rows
+}
+
+func (c *Canvas) cur() *state {
+	return &c.stack[len(c.stack)-1]
+}
+
+// SetLineWidth implements the vg.Canvas.SetLineWidth method.
+func (c *Canvas) SetLineWidth(w vg.Length) {
+	c.img.SetLineWidth(w)
+}
+
+// SetLineDash implements the vg.Canvas.SetLineDash method.
+func (c *Canvas) SetLineDash(pattern []vg.Length, offset vg.Length) {
+	c.img.SetLineDash(pattern, offset)
+}
+
+// SetLineCap implements the vg.LineStyler.SetLineCap method.
+func (c *Canvas) SetLineCap(capStyle vg.LineCap) {
+	c.img.SetLineCap(capStyle)
+}
+
+// SetLineJoin implements the vg.LineStyler.SetLineJoin method.
+func (c *Canvas) SetLineJoin(joinStyle vg.LineJoin) {
+	c.img.SetLineJoin(joinStyle)
+}
+
+// SetMiterLimit implements the vg.LineStyler.SetMiterLimit method.
+func (c *Canvas) SetMiterLimit(limit float64) {
+	c.img.SetMiterLimit(limit)
+}
+
+// SetColor implements the vg.Canvas.SetColor method.
+func (c *Canvas) SetColor(clr color.Color) {
+	c.img.SetColor(clr)
+	if clr == nil {
+		clr = color.Black
+	}
+	c.cur().color = clr
+}
+
+// Rotate implements the vg.Canvas.Rotate method.
+func (c *Canvas) Rotate(rad float64) {
+	c.img.Rotate(rad)
+	c.cur().m = c.cur().m.rotate(rad)
+}
+
+// Translate implements the vg.Canvas.Translate method.
+func (c *Canvas) Translate(pt vg.Point) {
+	c.img.Translate(pt)
+	c.cur().m = c.cur().m.translate(pt.X.Points(), pt.Y.Points())
+}
+
+// Scale implements the vg.Canvas.Scale method.
+func (c *Canvas) Scale(x, y float64) {
+	c.img.Scale(x, y)
+	c.cur().m = c.cur().m.scale(x, y)
+}
+
+// Push implements the vg.Canvas.Push method.
+func (c *Canvas) Push() {
+	c.img.Push()
+	c.stack = append(c.stack, *c.cur())
+}
+
+// Pop implements the vg.Canvas.Pop method.
+func (c *Canvas) Pop() {
+	c.img.Pop()
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+// Clip implements the vg.Clipper.Clip method.
+// Text is not clipped.
+func (c *Canvas) Clip(p vg.Path) {
+	c.img.Clip(p)
+}
+
+// Stroke implements the vg.Canvas.Stroke method.
+func (c *Canvas) Stroke(p vg.Path) {
+	c.img.Stroke(p)
+}
+
+// Fill implements the vg.Canvas.Fill method.
+func (c *Canvas) Fill(p vg.Path) {
+	c.img.Fill(p)
+}
+
+// DrawImage implements the vg.Canvas.DrawImage method.
+func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
+	c.img.DrawImage(rect, img)
+}
+
+// FillString implements the vg.Canvas.FillString method.
+// Each character of the string is drawn in a character of
+// the grid, starting at the character containing pt.
+// Text rotated by more than 45° is drawn vertically.
+func (c *Canvas) FillString(f font.Face, pt vg.Point, str string) {
+	if f.Font.Size == 0 {
+		return
+	}
+
+	var (
+		m = c.cur().m
+		// x and y are at the middle of the first character,
+		// a quarter of a line above the baseline.
+		x = pt.X.Points() + CellWidth.Points()/2
+		y = pt.Y.Points() + CellHeight.Points()/4
+
+		x0, y0 = m.apply(x, y)
+		x1, y1 = m.apply(x+1, y)
+		dx, dy = x1 - x0, y1 - y0
+
+		adv = CellWidth.Points()
+	)
+	if math.Abs(dy) > math.Abs(dx) {
+		adv = CellHeight.Points()
+	}
+	adv /= math.Hypot(dx, dy)
+
+	i := 0
+	for _, r := range str {
+		px, py := m.apply(x+float64(i)*adv, y)
+		i++
+
+		col := int(math.Floor(px / CellWidth.Points()))
+		row := int(math.Floor((c.h.Points() - py) / CellHeight.Points()))
+		if col < 0 || col >= c.cols || row < 0 || row >= c.rows {
+			continue
+		}
+		if r < ' ' {
+			r = ' '
+		}
+		c.text[row*c.cols+col] = cell{r: r, clr: c.cur().color}
+	}
+}
+
+// WriteTo writes the grid of characters to w, one line per
+// row, and returns the number of bytes written.
+// Spaces at the end of lines are not written.
+func (c *Canvas) WriteTo(w io.Writer) (int64, error) {
+	wc := &writerCounter{Writer: w}
+	b := bufio.NewWriter(wc)
+
+	img := c.img.Image().(*image.RGBA)
+	dx, dy := c.dots()
+	for row := 0; row < c.rows; row++ {
+		var (
+			line  []cell
+			bgs   []color.Color
+			blank = 0
+		)
+		for col := 0; col < c.cols; col++ {
+			cl, bg := c.cell(img, col, row, dx, dy)
+			line = append(line, cl)
+			bgs = append(bgs, bg)
+			if cl.r != ' ' || bg != nil {
+				blank = len(line)
+			}
+		}
+		line, bgs = line[:blank], bgs[:blank]
+
+		var sgr string
+		for i, cl := range line {
+			s := c.sgr(cl, bgs[i], sgr)
+			if s != sgr {
+				b.WriteString(s)
+				sgr = s
+			}
+			b.WriteRune(cl.r)
+		}
+		if sgr != "" && sgr != reset {
+			b.WriteString(reset)
+		}
+		b.WriteString("\n")
+	}
+
+	err := b.Flush()
+	return wc.n, err
+}
+
+// reset is the escape sequence resetting the colors.
+const reset = "\x1b[0m"
+
+// sgr returns the escape sequence setting the colors of the
+// character cl with the background color bg, given the
+// escape sequence cur that is currently in use.
+func (c *Canvas) sgr(cl cell, bg color.Color, cur string) string {
+	if c.color == NoColor {
+		return ""
+	}
+	if bg == nil && cl.r == ' ' {
+		if strings.Contains(cur, ";48;") {
+			return reset
+		}
+		return cur
+	}
+	s := "\x1b[0;38;" + c.ansi(cl.clr)
+	if bg != nil {
+		s += ";48;" + c.ansi(bg)
+	}
+	return s + "m"
+}
+
+// ansi returns the parameters of the escape sequence
+// selecting the color clr.
+func (c *Canvas) ansi(clr color.Color) string {
+	n := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	if c.color == TrueColor {
+		return fmt.Sprintf("2;%d;%d;%d", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("5;%d", ansi256(n))
+}
+
+// ansi256 returns the index of the color of the 256 colors
+// of ANSI terminals closest to clr.
+func ansi256(clr color.NRGBA) int {
+	// cube returns the level of the 6×6×6 color cube
+	// closest to v, and its value.
+	levels := [...]int{0, 95, 135, 175, 215, 255}
+	cube := func(v uint8) (int, int) {
+		best := 0
+		for i, l := range levels {
+			if abs(int(v)-l) < abs(int(v)-levels[best]) {
+				best = i
+			}
+		}
+		return best, levels[best]
+	}
+	r, rv := cube(clr.R)
+	g, gv := cube(clr.G)
+	b, bv := cube(clr.B)
+	idx := 16 + 36*r + 6*g + b
+	dist := sq(int(clr.R)-rv) + sq(int(clr.G)-gv) + sq(int(clr.B)-bv)
+
+	// The grayscale ramp goes from 8 to 238 by steps of 10.
+	avg := (int(clr.R) + int(clr.G) + int(clr.B)) / 3
+	gray := (avg - 8 + 5) / 10
+	if gray < 0 {
+		gray = 0
+	}
+	if gray > 23 {
+		gray = 23
+	}
+	gv = 8 + 10*gray
+	if d := sq(int(clr.R)-gv) + sq(int(clr.G)-gv) + sq(int(clr.B)-gv); d < dist {
+		idx = 232 + gray
+	}
+	return idx
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sq(v int) int { return v * v }
+
+// cell returns the character of the grid at col and row, and its
+// background color, or nil if the character has no background.
+func (c *Canvas) cell(img *image.RGBA, col, row, dx, dy int) (cell, color.Color) {
+	if cl := c.text[row*c.cols+col]; cl.r != 0 {
+		return cl, nil
+	}
+
+	var (
+		lit  [2][4]bool
+		clrs [2][4]color.Color
+	)
+	for j := 0; j < dy; j++ {
+		for i := 0; i < dx; i++ {
+			clrs[i][j], lit[i][j] = c.dot(img, col*dx+i, row*dy+j)
+		}
+	}
+
+	if c.mode == Blocks {
+		top, bot := clrs[0][0], clrs[0][1]
+		switch {
+		case lit[0][0] && lit[0][1]:
+			if c.color == NoColor || top == bot {
+				return cell{r: '█', clr: top}, nil
+			}
+			return cell{r: '▀', clr: top}, bot
+		case lit[0][0]:
+			return cell{r: '▀', clr: top}, nil
+		case lit[0][1]:
+			return cell{r: '▄', clr: bot}, nil
+		}
+		return cell{r: ' '}, nil
+	}
+
+	// bits are the bits of the braille dots, by column and row.
+	bits := [2][4]rune{
+		{0x01, 0x02, 0x04, 0x40},
+		{0x08, 0x10, 0x20, 0x80},
+	}
+	var (
+		r    rune
+		clr  color.Color
+		best float64
+	)
+	for i := 0; i < dx; i++ {
+		for j := 0; j < dy; j++ {
+			if !lit[i][j] {
+				continue
+			}
+			r |= bits[i][j]
+			if d := c.diff(clrs[i][j]); clr == nil || d > best {
+				clr, best = clrs[i][j], d
+			}
+		}
+	}
+	if r == 0 {
+		return cell{r: ' '}, nil
+	}
+	return cell{r: 0x2800 + r, clr: clr}, nil
+}
+
+// dot returns the color of the dot at x and y, that is the color
+// of its pixel differing most from the background, and whether
+// the dot is drawn.
+func (c *Canvas) dot(img *image.RGBA, x, y int) (color.Color, bool) {
+	var (
+		clr  color.Color
+		best = -1.0
+	)
+	for j := 0; j < supersampling; j++ {
+		for i := 0; i < supersampling; i++ {
+			p := img.RGBAAt(x*supersampling+i, y*supersampling+j)
+			if d := c.diff(p); d > best {
+				clr, best = p, d
+			}
+		}
+	}
+	return clr, best >= threshold
+}
+
+// diff returns the largest difference between the components
+// of clr and of the background color, between 0 and 1.
+func (c *Canvas) diff(clr color.Color) float64 {
+	r1, g1, b1, a1 := clr.RGBA()
+	r2, g2, b2, a2 := c.bg.RGBA()
+	d := 0.0
+	for _, v := range [...][2]uint32{{r1, r2}, {g1, g2}, {b1, b2}, {a1, a2}} {
+		d = math.Max(d, math.Abs(float64(v[0])-float64(v[1]))/0xffff)
+	}
+	return d
+}
+
+// matrix is an affine transformation, mapping x and y to
+// a*x + c*y + e and b*x + d*y + f.
+type matrix struct {
+	a, b, c, d, e, f float64
+}
+
+var identity = matrix{a: 1, d: 1}
+
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return m.a*x + m.c*y + m.e, m.b*x + m.d*y + m.f
+}
+
+func (m matrix) translate(x, y float64) matrix {
+	m.e, m.f = m.apply(x, y)
+	return m
+}
+
+func (m matrix) scale(x, y float64) matrix {
+	m.a, m.b = m.a*x, m.b*x
+	m.c, m.d = m.c*y, m.d*y
+	return m
+}
+
+func (m matrix) rotate(rad float64) matrix {
+	sin, cos := math.Sincos(rad)
+	return matrix{
+		a: m.a*cos + m.c*sin,
+		b: m.b*cos + m.d*sin,
+		c: -m.a*sin + m.c*cos,
+		d: -m.b*sin + m.d*cos,
+		e: m.e,
+		f: m.f,
+	}
+}
+
+// writerCounter implements the io.Writer interface, and counts
+// the total number of bytes written.
+type writerCounter struct {
+	io.Writer
+	n int64
+}
+
+func (w *writerCounter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+var (
+	_ vg.CanvasWriterTo = (*Canvas)(nil)
+	_ vg.Clipper        = (*Canvas)(nil)
+	_ vg.LineStyler     = (*Canvas)(nil)
+)
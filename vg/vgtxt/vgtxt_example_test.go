@@ -0,0 +1,80 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgtxt_test
+
+import (
+	"log"
+	"math"
+	"os"
+
+	"github.com/emptywe/plot"
+	"github.com/emptywe/plot/plotter"
+	"github.com/emptywe/plot/plotutil"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/draw"
+	"github.com/emptywe/plot/vg/vgtxt"
+)
+
+func Example() {
+	p := plot.New()
+	p.Title.Text = "sin(x)"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+
+	sin := plotter.NewFunction(math.Sin)
+	sin.XMin = 0
+	sin.XMax = 2 * math.Pi
+	p.Add(sin)
+	p.X.Min = 0
+	p.X.Max = 2 * math.Pi
+	p.Y.Min = -1
+	p.Y.Max = +1
+
+	// The txt format draws with braille patterns, without colors.
+	err := p.Save(6*vg.Inch, 3*vg.Inch, "testdata/sine.txt")
+	if err != nil {
+		log.Fatalf("could not save plot: %v", err)
+	}
+}
+
+func Example_colors() {
+	p := plot.New()
+	p.Title.Text = "Functions"
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+
+	for i, fn := range []func(float64) float64{math.Sin, math.Cos} {
+		f := plotter.NewFunction(fn)
+		f.Color = plotutil.Color(i)
+		f.Width = vg.Points(2)
+		p.Add(f)
+	}
+	p.X.Min = 0
+	p.X.Max = 2 * math.Pi
+	p.Y.Min = -1
+	p.Y.Max = +1
+
+	c := vgtxt.NewWith(
+		vgtxt.UseWH(6*vg.Inch, 3*vg.Inch),
+		vgtxt.UseMode(vgtxt.Blocks),
+		vgtxt.UseColor(vgtxt.TrueColor),
+	)
+	p.Draw(draw.New(c))
+
+	f, err := os.Create("testdata/functions.ansi")
+	if err != nil {
+		log.Fatalf("could not create output file: %v", err)
+	}
+	defer f.Close()
+
+	_, err = c.WriteTo(f)
+	if err != nil {
+		log.Fatalf("could not write plot: %v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		log.Fatalf("could not close output file: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright ©2026 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vgtxt_test
+
+import (
+	"bytes"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/emptywe/plot/cmpimg"
+	"github.com/emptywe/plot/font"
+	"github.com/emptywe/plot/vg"
+	"github.com/emptywe/plot/vg/vgtxt"
+)
+
+func TestTXT(t *testing.T) {
+	cmpimg.CheckPlot(Example, t, "sine.txt")
+}
+
+func TestColors(t *testing.T) {
+	cmpimg.CheckPlot(Example_colors, t, "functions.ansi")
+}
+
+func TestCanvas(t *testing.T) {
+	fnt := font.Face{Font: font.Font{Size: 12}}
+	rect := func(x0, y0, x1, y1 vg.Length) vg.Path {
+		var p vg.Path
+		p.Move(vg.Point{X: x0, Y: y0})
+		p.Line(vg.Point{X: x1, Y: y0})
+		p.Line(vg.Point{X: x1, Y: y1})
+		p.Line(vg.Point{X: x0, Y: y1})
+		p.Close()
+		return p
+	}
+
+	for _, test := range []struct {
+		name string
+		c    *vgtxt.Canvas
+		draw func(c vg.Canvas)
+		want string
+	}{
+		{
+			name: "braille",
+			c:    vgtxt.New(12, 24),
+			draw: func(c vg.Canvas) {
+				c.Fill(rect(0, 12, 6, 24))
+				c.Fill(rect(6, 0, 9, 6))
+			},
+			want: "\u28ff\n \u2844\n",
+		},
+		{
+			name: "blocks",
+			c:    vgtxt.NewWith(vgtxt.UseWH(18, 12), vgtxt.UseMode(vgtxt.Blocks)),
+			draw: func(c vg.Canvas) {
+				c.Fill(rect(0, 0, 6, 6))
+				c.Fill(rect(6, 6, 12, 12))
+			},
+			want: "\u2584\u2580\n",
+		},
+		{
+			name: "text",
+			c:    vgtxt.New(30, 12),
+			draw: func(c vg.Canvas) {
+				c.FillString(fnt, vg.Point{X: 6, Y: 2}, "ab")
+			},
+			want: " ab\n",
+		},
+		{
+			name: "rotated text",
+			c:    vgtxt.New(6, 36),
+			draw: func(c vg.Canvas) {
+				c.Translate(vg.Point{X: 6})
+				c.Rotate(0.5 * math.Pi)
+				c.FillString(fnt, vg.Point{}, "abc")
+			},
+			want: "c\nb\na\n",
+		},
+		{
+			name: "color 256",
+			c:    vgtxt.NewWith(vgtxt.UseWH(12, 12), vgtxt.UseColor(vgtxt.Color256)),
+			draw: func(c vg.Canvas) {
+				c.SetColor(color.RGBA{R: 255, A: 255})
+				c.Fill(rect(0, 0, 6, 12))
+				c.SetColor(color.Gray{Y: 128})
+				c.FillString(fnt, vg.Point{X: 6}, "x")
+			},
+			want: "\x1b[0;38;5;196m\u28ff\x1b[0;38;5;244mx\x1b[0m\n",
+		},
+		{
+			name: "true color blocks",
+			c: vgtxt.NewWith(
+				vgtxt.UseWH(6, 12),
+				vgtxt.UseMode(vgtxt.Blocks),
+				vgtxt.UseColor(vgtxt.TrueColor),
+			),
+			draw: func(c vg.Canvas) {
+				c.SetColor(color.RGBA{R: 255, A: 255})
+				c.Fill(rect(0, 6, 6, 12))
+				c.SetColor(color.RGBA{B: 255, A: 255})
+				c.Fill(rect(0, 0, 6, 6))
+			},
+			want: "\x1b[0;38;2;255;0;0;48;2;0;0;255m\u2580\x1b[0m\n",
+		},
+		{
+			name: "background",
+			c:    vgtxt.NewWith(vgtxt.UseWH(6, 12), vgtxt.UseBackgroundColor(color.Black)),
+			draw: func(c vg.Canvas) {
+				c.Fill(rect(0, 0, 6, 12))
+			},
+			want: "\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.draw(test.c)
+			var buf bytes.Buffer
+			n, err := test.c.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("could not write canvas: %+v", err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("invalid number of bytes written: got=%d, want=%d", n, buf.Len())
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("invalid output:\ngot= %q\nwant=%q", got, test.want)
+			}
+		})
+	}
+}
@@ -9,8 +9,10 @@ package plot // import "github.com/emptywe/plot"
 
 import (
 	_ "github.com/emptywe/plot/vg/vgeps"
+	_ "github.com/emptywe/plot/vg/vghtml"
 	_ "github.com/emptywe/plot/vg/vgimg"
 	_ "github.com/emptywe/plot/vg/vgpdf"
 	_ "github.com/emptywe/plot/vg/vgsvg"
 	_ "github.com/emptywe/plot/vg/vgtex"
+	_ "github.com/emptywe/plot/vg/vgtxt"
 )